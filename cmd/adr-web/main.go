@@ -6,6 +6,7 @@ import (
 	"io/fs"
 	"log"
 	"os"
+	"slices"
 	"strings"
 	"sync"
 
@@ -15,9 +16,10 @@ import (
 )
 
 // configScopeStore persists scope-vocabulary additions to .adr.json under a
-// mutex, keeping the in-memory config consistent for concurrent requests.
+// mutex, keeping the in-memory config consistent for concurrent requests. The
+// stores of a project's roots share the mutex, as they save the same file.
 type configScopeStore struct {
-	mu  sync.Mutex
+	mu  *sync.Mutex
 	dir string
 	cfg *adr.Config
 }
@@ -44,41 +46,73 @@ func (s *configScopeStore) AddScope(value string) ([]string, error) {
 	return scopes, nil
 }
 
+// mountedProject is a --project flag: a project served under name from the
+// .adr.json in dir.
+type mountedProject struct {
+	name, dir string
+}
+
 func main() {
 	addr := flag.String("addr", ":8080", "HTTP listen address")
+	authorHeader := flag.String("author-header", "",
+		"request header, set by a trusted proxy, naming the author of created ADRs (e.g. X-Forwarded-User)")
+	var projects []mountedProject
+	flag.Func("project", "serve the project at a path under /api/projects/{name}/ (name=path, repeatable)", func(v string) error {
+		name, dir, ok := strings.Cut(v, "=")
+		if !ok || name == "" || dir == "" {
+			return fmt.Errorf("expected name=path, got %q", v)
+		}
+		for _, p := range projects {
+			if p.name == name {
+				return fmt.Errorf("project %q is mounted twice", name)
+			}
+		}
+		projects = append(projects, mountedProject{name: name, dir: dir})
+		return nil
+	})
 	flag.Parse()
 
 	var repo adr.Repository
-	var opts []web.ServerOption
+	var common, opts []web.ServerOption
+	if *authorHeader != "" {
+		common = append(common, web.WithAuthorHeader(*authorHeader))
+	}
+	opts = append(opts, common...)
 	cfg, err := adr.LoadConfig(".")
-	if err != nil {
+	if err != nil && len(projects) == 0 {
 		log.Printf("warning: could not load config: %v (API will return 503)", err)
-	} else {
-		fileRepo := adr.NewFileRepository(cfg.Directory)
-		repo = fileRepo
-		opts = append(opts, web.WithStatusUpdater(fileRepo))
-		opts = append(opts, web.WithSuperseder(fileRepo))
-		opts = append(opts, web.WithRelator(fileRepo))
-		opts = append(opts, web.WithContentUpdater(fileRepo))
-
-		// Auto-discover scopes from existing ADRs into the served vocabulary.
-		// In-memory only: no config write at boot (safe on read-only mounts and
-		// multi-replica deploys). Persistence stays with `adr init` / `adr scope
-		// discover`, and lazily via the next web AddScope which saves the config.
-		added, invalid, derr := adr.DiscoverAndMergeScopes(cfg)
-		if derr != nil {
-			log.Printf("warning: scope discovery failed: %v", derr)
+	}
+	// Mount every project before taking roots, so that renaming an ADR in
+	// one of them rewrites the links to it in the others.
+	loaded := err == nil
+	var federation []*adr.Config
+	if loaded {
+		federation = append(federation, cfg)
+	}
+	mounted := make([]*adr.Config, len(projects))
+	for i, p := range projects {
+		if mounted[i], err = adr.Mount(p.name, p.dir); err != nil {
+			log.Fatalf("project %s: %v", p.name, err)
 		}
-		for _, v := range invalid {
-			log.Printf("warning: skipped invalid scope %q from ADRs", v)
+		federation = append(federation, mounted[i])
+	}
+	adr.Federate(federation...)
+	if loaded {
+		if projectRepo, projectOpts, perr := projectOptions(cfg, "."); perr != nil {
+			log.Printf("warning: could not load roots: %v (API will return 503)", perr)
+		} else {
+			repo = projectRepo
+			opts = append(opts, projectOpts...)
 		}
-		if len(added) > 0 {
-			log.Printf("discovered %d scope(s) from existing ADRs (in-memory; run 'adr scope discover' to persist): %s",
-				len(added), strings.Join(added, ", "))
+	}
+	for i, p := range projects {
+		projectRepo, projectOpts, err := projectOptions(mounted[i], p.dir)
+		if err != nil {
+			log.Fatalf("project %s: %v", p.name, err)
 		}
-
-		opts = append(opts, web.WithConfig(cfg))
-		opts = append(opts, web.WithScopeStore(&configScopeStore{dir: ".", cfg: cfg}))
+		srv := web.NewServer(projectRepo, slices.Concat(common, projectOpts)...)
+		opts = append(opts, web.WithProject(p.name, srv))
+		log.Printf("serving project %s from %s", p.name, p.dir)
 	}
 	if subFS, err := fs.Sub(webui.DistFS, "dist"); err == nil {
 		if _, err := subFS.Open("index.html"); err == nil {
@@ -94,3 +128,74 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+// projectOptions returns the repository and server options for the project
+// configured by cfg, whose .adr.json is in dir: the default root's ADRs are
+// the server's own, and every other root is served with web.WithRoot.
+func projectOptions(cfg *adr.Config, dir string) (adr.Repository, []web.ServerOption, error) {
+	roots, err := cfg.AllRoots()
+	if err != nil {
+		return nil, nil, err
+	}
+	var repo adr.Repository
+	var opts []web.ServerOption
+	if cfg.Directory == "" {
+		opts = append(opts, web.WithConfig(cfg))
+	}
+	mu := new(sync.Mutex)
+	for _, root := range roots {
+		rootOpts := rootOptions(root, dir, mu)
+		if root.RootName() == "" {
+			repo = root.Repository()
+			opts = append(opts, rootOpts...)
+			continue
+		}
+		opts = append(opts, web.WithRoot(root.RootName(), web.NewServer(root.Repository(), rootOpts...)))
+	}
+	return repo, opts, nil
+}
+
+// rootOptions returns the server options for the ADRs of cfg's root, after
+// finishing any change interrupted in it and discovering its scopes. Scope
+// additions are saved to the .adr.json in dir.
+func rootOptions(cfg *adr.Config, dir string, mu *sync.Mutex) []web.ServerOption {
+	fileRepo := cfg.Repository()
+	opts := []web.ServerOption{
+		web.WithStatusUpdater(fileRepo),
+		web.WithSuperseder(fileRepo),
+		web.WithRelator(fileRepo),
+		web.WithContentUpdater(fileRepo),
+		web.WithRenamer(fileRepo),
+		web.WithArchiveLister(fileRepo),
+		web.WithAssetStore(fileRepo),
+		web.WithTemplateProvider(adr.NewTemplateLoader(cfg)),
+	}
+
+	// Finish any multi-file change (rename, …) interrupted by a crash.
+	if recovered, rerr := fileRepo.Recover(); rerr != nil {
+		log.Printf("warning: recovering interrupted change: %v", rerr)
+	} else if recovered {
+		log.Printf("completed an interrupted change in %s", cfg.Directory)
+	}
+
+	// Auto-discover scopes from existing ADRs into the served vocabulary.
+	// In-memory only: no config write at boot (safe on read-only mounts and
+	// multi-replica deploys). Persistence stays with `adr init` / `adr scope
+	// discover`, and lazily via the next web AddScope which saves the config.
+	added, invalid, derr := adr.DiscoverAndMergeScopes(cfg)
+	if derr != nil {
+		log.Printf("warning: scope discovery failed: %v", derr)
+	}
+	for _, v := range invalid {
+		log.Printf("warning: skipped invalid scope %q from ADRs", v)
+	}
+	if len(added) > 0 {
+		log.Printf("discovered %d scope(s) from existing ADRs in %s (in-memory; run 'adr scope discover' to persist): %s",
+			len(added), cfg.Directory, strings.Join(added, ", "))
+	}
+
+	return append(opts,
+		web.WithConfig(cfg),
+		web.WithScopeStore(&configScopeStore{mu: mu, dir: dir, cfg: cfg}),
+	)
+}
@@ -119,3 +119,49 @@ func TestBinary_DiscoversScopesAtBoot(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotContains(t, string(data), "Backend", "boot discovery must not persist to disk")
 }
+
+func TestBinary_MountsProjects(t *testing.T) {
+	bin := filepath.Join(t.TempDir(), "adr-web-test")
+	if out, err := exec.Command("go", "build", "-o", bin, ".").CombinedOutput(); err != nil {
+		t.Fatalf("build failed: %v\n%s", err, out)
+	}
+
+	// Two service repositories side by side; the server runs from neither.
+	work := t.TempDir()
+	for _, name := range []string{"billing", "shop"} {
+		adrDir := filepath.Join(work, name, "docs", "adr")
+		require.NoError(t, os.MkdirAll(adrDir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(work, name, ".adr.json"),
+			[]byte(`{"version":"1","directory":"docs/adr","template":"nygard"}`), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(adrDir, "0001-start-"+name+".md"),
+			[]byte("# 1. Start "+name+"\n\n## Status\n\nAccepted\n"), 0o644))
+	}
+
+	addr := freePort(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, bin, "--addr", addr, "--project", "billing=billing", "--project", "shop=shop")
+	cmd.Dir = work
+	require.NoError(t, cmd.Start())
+	defer cmd.Process.Kill()
+
+	get := func(path string) string {
+		var body []byte
+		for i := 0; i < 50; i++ {
+			resp, e := http.Get(fmt.Sprintf("http://%s%s", addr, path))
+			if e == nil {
+				body, _ = io.ReadAll(resp.Body)
+				resp.Body.Close()
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		return string(body)
+	}
+	catalog := get("/api/catalog")
+	assert.Contains(t, catalog, `"project":"billing"`)
+	assert.Contains(t, catalog, "Start shop")
+	assert.Contains(t, get("/api/projects/shop/adr/1"), "Start shop")
+	assert.Contains(t, get("/api/config"), `"projects":["billing","shop"]`)
+}
@@ -111,7 +111,8 @@ func ParseStatus(s string) (Status, bool) {
 	}
 	for _, st := range AllStatuses() {
 		name := strings.ToLower(st.String())
-		if lower == name || strings.HasPrefix(lower, name+" ") {
+		// "accepted, supersedes [ADR-…]" is how SetSupersedes writes frontmatter.
+		if lower == name || strings.HasPrefix(lower, name+" ") || strings.HasPrefix(lower, name+",") {
 			return st, true
 		}
 	}
@@ -139,6 +140,17 @@ type ADR struct {
 	// each a comma-split list of trimmed tokens. Populated from the raw content by
 	// ExtractMetaFields; absent when the ADR carries no recognized metadata.
 	Meta map[string][]string
+	// Archived is set on ADRs stored in the archive directory (see
+	// FileRepository.Archive).
+	Archived bool
+	// Root is the name of the root the ADR belongs to (see Config.Root), or
+	// "" for the default root.
+	Root string
+}
+
+// ID returns the ADR's root-qualified ID.
+func (a ADR) ID() ID {
+	return ID{Root: a.Root, Number: a.Number}
 }
 
 // New creates a new ADR with the given number and title, defaulting to Proposed status.
@@ -82,6 +82,8 @@ func TestParseStatus(t *testing.T) {
 		{"superseded", adr.Superseded, true},
 		{"Superseded by ADR-0005", adr.Superseded, true},
 		{"superseded by ...", adr.Superseded, true},
+		{"accepted, supersedes [ADR-0001](0001-a.md)", adr.Accepted, true},
+		{"acceptedly", adr.Status(0), false},
 		{"unknown", adr.Status(0), false},
 		{"", adr.Status(0), false},
 	}
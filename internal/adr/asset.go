@@ -0,0 +1,124 @@
+package adr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// AssetsDir is the directory, relative to the ADR directory, that SaveAsset
+// stores attachments in.
+const AssetsDir = "assets"
+
+var assetExtPattern = regexp.MustCompile(`^\.[a-z0-9]+$`)
+
+// ErrInvalidAsset is returned for asset names that may not be served or stored.
+var ErrInvalidAsset = errors.New("invalid asset")
+
+// OpenAsset opens a non-markdown file kept alongside the ADRs, such as an
+// embedded image or a diagram source. name is slash-separated and relative to
+// the ADR directory. Markdown files, hidden files (including the transaction
+// journal) and anything outside the directory, through ".." or a symlink, are
+// refused: they report ErrNotFound, so callers can't probe for them.
+func (r *FileRepository) OpenAsset(name string) (fs.File, error) {
+	if err := checkAssetPath(name); err != nil {
+		return nil, fmt.Errorf("asset %q: %w", name, ErrNotFound)
+	}
+	root, err := os.OpenRoot(r.dir)
+	if err != nil {
+		return nil, fmt.Errorf("opening directory %q: %w", r.dir, err)
+	}
+	defer root.Close()
+
+	f, err := root.Open(filepath.FromSlash(name))
+	if err != nil {
+		return nil, fmt.Errorf("asset %q: %w", name, ErrNotFound)
+	}
+	info, err := f.Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		f.Close()
+		return nil, fmt.Errorf("asset %q: %w", name, ErrNotFound)
+	}
+	return f, nil
+}
+
+// SaveAsset stores data as an attachment of ADR number in the AssetsDir of
+// the ADR directory and returns its path as a link from the ADR's file:
+// "assets/0012-flow.png" for an upload named "Flow.png", or
+// "../assets/0012-flow.png" for an ADR in a subdirectory. The name keeps its
+// extension, which must not be ".md"; an existing file is never overwritten
+// (ErrConflict).
+func (r *FileRepository) SaveAsset(_ context.Context, number int, name string, data io.Reader) (string, error) {
+	file, err := r.FindFile(number)
+	if err != nil {
+		return "", err
+	}
+	stored, err := assetFilename(number, name)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Join(r.dir, AssetsDir), 0o755); err != nil {
+		return "", fmt.Errorf("creating directory %q: %w", AssetsDir, err)
+	}
+	root, err := os.OpenRoot(r.dir)
+	if err != nil {
+		return "", fmt.Errorf("opening directory %q: %w", r.dir, err)
+	}
+	defer root.Close()
+
+	rel := path.Join(AssetsDir, stored)
+	f, err := root.OpenFile(filepath.FromSlash(rel), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return "", fmt.Errorf("asset %q already exists: %w", rel, ErrConflict)
+		}
+		return "", fmt.Errorf("creating asset %q: %w", rel, err)
+	}
+	_, err = io.Copy(f, data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = root.Remove(filepath.FromSlash(rel))
+		return "", fmt.Errorf("writing asset %q: %w", rel, err)
+	}
+	return RelativePath(file, rel), nil
+}
+
+// checkAssetPath rejects names OpenAsset must not serve.
+func checkAssetPath(name string) error {
+	if !fs.ValidPath(name) || name == "." || strings.EqualFold(path.Ext(name), ".md") {
+		return ErrInvalidAsset
+	}
+	for _, elem := range strings.Split(name, "/") {
+		if strings.HasPrefix(elem, ".") {
+			return ErrInvalidAsset
+		}
+	}
+	return nil
+}
+
+// assetFilename returns the name an upload is stored under: the ADR's
+// zero-padded number and the slug of the upload's base name, with its
+// extension lowercased.
+func assetFilename(number int, name string) (string, error) {
+	base := path.Base(strings.ReplaceAll(name, `\`, "/"))
+	ext := strings.ToLower(path.Ext(base))
+	if !assetExtPattern.MatchString(ext) || ext == ".md" {
+		return "", fmt.Errorf("%q: attachments need a non-markdown file extension: %w", name, ErrInvalidAsset)
+	}
+	prefix := fmt.Sprintf("%04d-", number)
+	slug, err := Slugify(strings.TrimPrefix(strings.TrimSuffix(base, path.Ext(base)), prefix))
+	if err != nil {
+		slug = "attachment"
+	}
+	return prefix + slug + ext, nil
+}
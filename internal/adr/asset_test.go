@@ -0,0 +1,100 @@
+package adr_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAsset_ServesFilesNextToADRs(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"0001-use-go.md":            "# 1. Use Go\n",
+		"assets/0001-flow.png":      "png",
+		"diagrams/context.drawio":   "<mxfile/>",
+		adr.JournalFileName:         "{}",
+		"assets/.hidden/secret.txt": "secret",
+	})
+	repo := adr.NewFileRepository(dir)
+
+	for name, want := range map[string]string{"assets/0001-flow.png": "png", "diagrams/context.drawio": "<mxfile/>"} {
+		f, err := repo.OpenAsset(name)
+		require.NoError(t, err, name)
+		data, err := io.ReadAll(f)
+		require.NoError(t, err)
+		assert.Equal(t, want, string(data))
+		f.Close()
+	}
+
+	for _, name := range []string{"0001-use-go.md", adr.JournalFileName, "assets/.hidden/secret.txt",
+		"../outside.txt", "assets/../../outside.txt", "/etc/passwd", "assets", "", "missing.png"} {
+		_, err := repo.OpenAsset(name)
+		assert.ErrorIs(t, err, adr.ErrNotFound, name)
+	}
+}
+
+func TestOpenAsset_RefusesSymlinkOutOfDirectory(t *testing.T) {
+	outside := filepath.Join(t.TempDir(), "secret.txt")
+	require.NoError(t, os.WriteFile(outside, []byte("secret"), 0o644))
+	dir := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(dir, "link.txt")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	_, err := adr.NewFileRepository(dir).OpenAsset("link.txt")
+	assert.ErrorIs(t, err, adr.ErrNotFound)
+}
+
+func TestSaveAsset_StoresUnderAssetsDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"0012-pick-a-queue.md": "# 12. Pick a queue\n"})
+	repo := adr.NewFileRepository(dir)
+
+	rel, err := repo.SaveAsset(context.Background(), 12, `C:\Users\me\Flow Chart.PNG`, strings.NewReader("png"))
+	require.NoError(t, err)
+	assert.Equal(t, "assets/0012-flow-chart.png", rel)
+	assert.Equal(t, "png", readFile(t, filepath.Join(dir, "assets", "0012-flow-chart.png")))
+
+	// Re-uploading a stored name keeps a single prefix and never overwrites.
+	_, err = repo.SaveAsset(context.Background(), 12, "0012-flow-chart.png", strings.NewReader("other"))
+	assert.ErrorIs(t, err, adr.ErrConflict)
+	assert.Equal(t, "png", readFile(t, filepath.Join(dir, "assets", "0012-flow-chart.png")))
+}
+
+func TestSaveAsset_LinkFromSubdirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"platform/0003-use-kafka.md": "# 3. Use Kafka\n"})
+	repo := (&adr.Config{Directory: dir, Recursive: true}).Repository()
+
+	rel, err := repo.SaveAsset(context.Background(), 3, "Topology.png", strings.NewReader("png"))
+	require.NoError(t, err)
+	assert.Equal(t, "../assets/0003-topology.png", rel)
+	assert.Equal(t, "png", readFile(t, filepath.Join(dir, "assets", "0003-topology.png")))
+}
+
+func TestSaveAsset_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"0001-use-go.md": "# 1. Use Go\n"})
+	repo := adr.NewFileRepository(dir)
+
+	_, err := repo.SaveAsset(context.Background(), 2, "flow.png", strings.NewReader("png"))
+	assert.ErrorIs(t, err, adr.ErrNotFound)
+
+	for _, name := range []string{"notes.md", "README", "flow.p ng"} {
+		_, err = repo.SaveAsset(context.Background(), 1, name, strings.NewReader("x"))
+		assert.ErrorIs(t, err, adr.ErrInvalidAsset, name)
+	}
+
+	_, err = repo.SaveAsset(context.Background(), 1, "flow.png", iotest.ErrReader(errors.New("connection reset")))
+	assert.Error(t, err)
+	assert.NoFileExists(t, filepath.Join(dir, "assets", "0001-flow.png"))
+}
@@ -32,6 +32,63 @@ type Config struct {
 	Template     string   `json:"template"`
 	TemplateFile string   `json:"templateFile"`
 	Scopes       []string `json:"scopes,omitempty"`
+	// Templates declares project-defined templates by name (see
+	// LoadProjectTemplate). Names must not shadow a built-in template.
+	Templates map[string]TemplateDef `json:"templates,omitempty"`
+	// TemplatesDir is a directory, relative to Directory, whose <name>.md files
+	// are additional project templates with a section schema derived from
+	// their headings.
+	TemplatesDir string `json:"templatesDir,omitempty"`
+	// PartialsDir is a directory, relative to Directory, whose <name>.md files
+	// every template can include as {{template "name" .}}.
+	PartialsDir string `json:"partialsDir,omitempty"`
+	// SlugMaxLength caps the slug of new ADR filenames, in characters; 0
+	// means no limit. Existing files keep their names.
+	SlugMaxLength int `json:"slugMaxLength,omitempty"`
+	// FilenamePattern, NumberWidth, NumberPrefix and LinkLabel set how ADR
+	// files are named and links to them labelled (see Naming); empty means
+	// the default "0001-slug.md" files linked as "ADR-0001".
+	FilenamePattern string `json:"filenamePattern,omitempty"`
+	NumberWidth     int    `json:"numberWidth,omitempty"`
+	NumberPrefix    string `json:"numberPrefix,omitempty"`
+	LinkLabel       string `json:"linkLabel,omitempty"`
+	// Recursive, Include and Exclude select the files searched for ADRs
+	// (see Discovery); by default only Directory's own files are.
+	Recursive bool     `json:"recursive,omitempty"`
+	Include   []string `json:"include,omitempty"`
+	Exclude   []string `json:"exclude,omitempty"`
+	// ArchiveDir is where `adr archive` moves inactive ADRs, relative to
+	// Directory; empty means DefaultArchiveDir.
+	ArchiveDir string `json:"archiveDir,omitempty"`
+	// Roots declares further named ADR directories (see RootDef and Root).
+	// Directory may be empty when there are roots.
+	Roots []RootDef `json:"roots,omitempty"`
+
+	// root and parent are set on the config of a named root: its name and
+	// the project config it was derived from.
+	root   string
+	parent *Config
+	// project and projectDir are set on a mounted project's config (see
+	// Mount): its name and the directory its .adr.json is in.
+	project    string
+	projectDir string
+	// federation holds the projects served together with this one (see
+	// Federate), whose links into it are rewritten with its ADRs.
+	federation []*Config
+	// metaFields holds the metadata fields of the project's templates (see
+	// MetaFieldDefs); nil, meaning the built-ins', unless set by LoadConfig
+	// or a TemplateLoader.
+	metaFields *metaFieldRegistry
+}
+
+// TemplateDef declares a project-defined template.
+type TemplateDef struct {
+	// File is the template path relative to Directory. When empty the template
+	// is read from TemplatesDir/<name>.md.
+	File string `json:"file,omitempty"`
+	// Sections is the template's section schema. When empty it is derived from
+	// the template's headings and metadata lines (see DeriveTemplateSections).
+	Sections []TemplateSectionDef `json:"sections,omitempty"`
 }
 
 // HasScope reports whether value matches an existing scope, case-insensitively.
@@ -46,6 +103,34 @@ func (c *Config) HasScope(value string) (string, bool) {
 	return "", false
 }
 
+// CanonicalScopes matches values against vocabulary case-insensitively and
+// returns them in the vocabulary's spelling, deduplicated, in input order.
+// Blank values are skipped; values not in the vocabulary are reported in an
+// ErrInvalidScope error that lists the valid scopes.
+func CanonicalScopes(vocabulary, values []string) ([]string, error) {
+	vocab := &Config{Scopes: vocabulary}
+	var canonical, invalid []string
+	seen := make(map[string]bool)
+	for _, v := range values {
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+		name, ok := vocab.HasScope(v)
+		if !ok {
+			invalid = append(invalid, strings.TrimSpace(v))
+			continue
+		}
+		if !seen[name] {
+			seen[name] = true
+			canonical = append(canonical, name)
+		}
+	}
+	if len(invalid) > 0 {
+		return nil, fmt.Errorf("unknown scope(s) %v; valid scopes are %v: %w", invalid, vocabulary, ErrInvalidScope)
+	}
+	return canonical, nil
+}
+
 // AddScope validates value and appends it to the vocabulary unless an equal
 // scope already exists (case-insensitive), in which case it is a no-op. It
 // returns a copy of the updated scope list. Invalid values return ErrInvalidScope.
@@ -72,8 +157,29 @@ func (c *Config) AddScope(value string) ([]string, error) {
 	return append([]string(nil), c.Scopes...), nil
 }
 
-// SaveConfig writes the config as indented JSON to dir/.adr.json.
+// SaveConfig writes the config as indented JSON to dir/.adr.json. Saving a
+// root's config (see Config.Root) stores its scopes in the project config and
+// saves that.
 func SaveConfig(dir string, cfg *Config) error {
+	if cfg.parent != nil {
+		for i := range cfg.parent.Roots {
+			if cfg.parent.Roots[i].Name == cfg.root {
+				cfg.parent.Roots[i].Scopes = cfg.Scopes
+			}
+		}
+		return SaveConfig(dir, cfg.parent)
+	}
+	if cfg.projectDir != "" {
+		// A mounted config's directories were resolved against its project
+		// directory; keep the file's own and save just the scopes.
+		disk, err := LoadConfig(dir)
+		if err != nil {
+			return err
+		}
+		disk.Scopes = cfg.Scopes
+		disk.Roots = cfg.unmountedRoots(disk.Roots)
+		return SaveConfig(dir, disk)
+	}
 	out := *cfg
 	out.Version = ConfigVersion
 
@@ -106,12 +212,29 @@ func LoadConfig(dir string) (*Config, error) {
 	if cfg.Version != ConfigVersion {
 		return nil, fmt.Errorf("unsupported config version %q: %w", cfg.Version, ErrConfigInvalid)
 	}
-	if cfg.Directory == "" {
+	if cfg.Directory == "" && len(cfg.Roots) == 0 {
 		return nil, fmt.Errorf("directory must not be empty: %w", ErrConfigInvalid)
 	}
+	if err := validateRoots(&cfg); err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrConfigInvalid)
+	}
 	if cfg.TemplateFile == "" {
 		cfg.TemplateFile = "template.md"
 	}
+	if cfg.SlugMaxLength < 0 {
+		return nil, fmt.Errorf("slugMaxLength must not be negative: %w", ErrConfigInvalid)
+	}
+	if err := cfg.Naming().validate(); err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrConfigInvalid)
+	}
+	if err := cfg.Discovery().validate(); err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrConfigInvalid)
+	}
+	if err := validateTemplateDefs(&cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	cfg.ownMetaFields()
+	registerProjectMetaFields(&cfg)
 
 	return &cfg, nil
 }
@@ -141,6 +141,61 @@ func TestLoadConfig_EmptyDirectory_ReturnsErrConfigInvalid(t *testing.T) {
 	assert.True(t, errors.Is(err, adr.ErrConfigInvalid))
 }
 
+func TestLoadConfig_NegativeSlugMaxLength_ReturnsErrConfigInvalid(t *testing.T) {
+	dir := t.TempDir()
+	data := []byte(`{"version": "1", "directory": "docs/adr", "template": "nygard", "slugMaxLength": -1}`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, adr.ConfigFileName), data, 0o644))
+
+	_, err := adr.LoadConfig(dir)
+	assert.ErrorIs(t, err, adr.ErrConfigInvalid)
+}
+
+func TestLoadConfig_Naming(t *testing.T) {
+	dir := t.TempDir()
+	data := []byte(`{"version": "1", "directory": "docs/adr", "template": "nygard",
+		"numberPrefix": "API-", "numberWidth": 3, "linkLabel": "{prefix}{number}"}`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, adr.ConfigFileName), data, 0o644))
+
+	cfg, err := adr.LoadConfig(dir)
+	require.NoError(t, err)
+	assert.Equal(t, adr.Naming{Prefix: "API-", NumberWidth: 3, LinkLabel: "{prefix}{number}"}, cfg.Naming())
+}
+
+func TestLoadConfig_InvalidNaming_ReturnsErrConfigInvalid(t *testing.T) {
+	tests := map[string]string{
+		"no slug":              `"filenamePattern": "{number}.md"`,
+		"not markdown":         `"filenamePattern": "{number}-{slug}.txt"`,
+		"subdirectory":         `"filenamePattern": "{number}/{slug}.md"`,
+		"unknown placeholder":  `"filenamePattern": "{number}-{title}.md"`,
+		"repeated number":      `"filenamePattern": "{number}-{number}-{slug}.md"`,
+		"negative width":       `"numberWidth": -1`,
+		"label without number": `"linkLabel": "ADR"`,
+		"bracketed label":      `"linkLabel": "[{number}]"`,
+		"prefix with slash":    `"numberPrefix": "api/"`,
+		"malformed glob":       `"include": ["platform/[a-"]`,
+		"absolute glob":        `"exclude": ["/archive"]`,
+		"archive outside":      `"archiveDir": "../archive"`,
+		"unnamed root":         `"roots": [{"directory": "a"}]`,
+		"root name with colon": `"roots": [{"name": "a:b", "directory": "a"}]`,
+		"duplicate root":       `"roots": [{"name": "a", "directory": "a"}, {"name": "a", "directory": "b"}]`,
+		"root without dir":     `"roots": [{"name": "a"}]`,
+		"shared root dir":      `"roots": [{"name": "a", "directory": "docs/adr"}]`,
+		"root inside root":     `"roots": [{"name": "a", "directory": "docs/adr/payments"}]`,
+		"root around root":     `"roots": [{"name": "a", "directory": "docs"}]`,
+		"nested named roots":   `"roots": [{"name": "a", "directory": "svc"}, {"name": "b", "directory": "svc/b/../b/adr"}]`,
+	}
+	for name, field := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			data := []byte(`{"version": "1", "directory": "docs/adr", "template": "nygard", ` + field + `}`)
+			require.NoError(t, os.WriteFile(filepath.Join(dir, adr.ConfigFileName), data, 0o644))
+
+			_, err := adr.LoadConfig(dir)
+			assert.ErrorIs(t, err, adr.ErrConfigInvalid)
+		})
+	}
+}
+
 func TestConfig_AddScope_AppendsNewValue(t *testing.T) {
 	cfg := &adr.Config{}
 
@@ -229,3 +284,15 @@ func TestConfig_SaveLoad_RoundTripsScopes(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, []string{"Backend", "Frontend"}, loaded.Scopes)
 }
+
+func TestCanonicalScopes(t *testing.T) {
+	vocab := []string{"Backend", "Data"}
+
+	got, err := adr.CanonicalScopes(vocab, []string{" data", "BACKEND", "", "Data"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Data", "Backend"}, got)
+
+	_, err = adr.CanonicalScopes(vocab, []string{"Backend", " Payments "})
+	assert.ErrorIs(t, err, adr.ErrInvalidScope)
+	assert.ErrorContains(t, err, "unknown scope(s) [Payments]; valid scopes are [Backend Data]")
+}
@@ -0,0 +1,566 @@
+package adr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sectionAliases groups the headings different template formats use for the
+// same content, compared case-insensitively. Sections not listed here map to
+// a target section with the same heading.
+var sectionAliases = [][]string{
+	{"context", "context and problem statement"},
+	{"decision", "decision outcome"},
+}
+
+// docSection is a "##"-or-deeper heading and the text directly under it, up
+// to the next heading of any level.
+type docSection struct {
+	level   int
+	heading string
+	body    string
+	filled  bool
+}
+
+// metaPair is a "Label: value" title-block line or a "key: value" frontmatter
+// line.
+type metaPair struct {
+	key   string
+	value string
+}
+
+// statusParts is an ADR status split into the status line (e.g. "Accepted"
+// or "Superseded by [ADR-0005](…)"), the "Supersedes" links, and any other
+// text kept with the status.
+type statusParts struct {
+	line       string
+	supersedes []string
+	extra      string
+}
+
+// ConvertContent rewrites an ADR into the format of tmpl, for `adr convert`.
+// The converted file is tmpl rendered for the ADR, with:
+//
+//   - the status moved between a "## Status" section and frontmatter
+//     "status:", keeping "Superseded by" and "Supersedes" links;
+//   - title-block fields (e.g. Scope) and frontmatter fields carried over,
+//     added to the target's title block or frontmatter when it lacks them;
+//   - each section written under the target heading with the same name or an
+//     equivalent one (Context ↔ Context and Problem Statement, Decision ↔
+//     Decision Outcome), and the Relations section kept;
+//   - everything else appended to "More Information", which is added when the
+//     target has none.
+//
+// Optional target sections left empty are dropped; required ones keep their
+// template guidance. number is used when the heading carries none.
+func ConvertContent(content string, number int, tmpl *ProjectTemplate) (string, error) {
+	content, style := normalizeText(content)
+	meta := ExtractMetadata(content)
+	if meta.Title == "" {
+		return "", fmt.Errorf("no title heading: %w", ErrInvalidRecord)
+	}
+	if meta.Number > 0 {
+		number = meta.Number
+	}
+
+	src := parseConvertSource(content)
+	rendered, err := tmpl.Execute(TemplateData{
+		Number: number,
+		Title:  meta.Title,
+		Date:   meta.Date,
+		Status: upperFirst(src.status.line),
+	})
+	if err != nil {
+		return "", err
+	}
+	rendered, _ = normalizeText(rendered)
+	rendered, _ = ReplaceHeading(rendered, number, meta.Title)
+
+	fmLines, hasFrontmatter := frontmatterLines(rendered)
+	preamble, sections := splitDocument(bodyAfterFrontmatter(rendered))
+
+	// Status: into the target's Status section or frontmatter, or a new
+	// Status section when the target has neither.
+	statusPlaced := false
+	for i := range sections {
+		if sections[i].level == 2 && strings.EqualFold(sections[i].heading, "Status") {
+			sections[i].body = src.status.sectionText()
+			sections[i].filled = true
+			statusPlaced = true
+			break
+		}
+	}
+
+	// Frontmatter: status and date, fields the source shares, and source
+	// fields the target lacks. Unfilled placeholders are cleared.
+	usedFM := make(map[string]bool)
+	for i, line := range fmLines {
+		m := frontmatterKeyLinePattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		key := m[1]
+		switch {
+		case key == "status":
+			if !statusPlaced {
+				fmLines[i] = `status: "` + src.status.frontmatterValue() + `"`
+				statusPlaced = true
+			}
+		case key == "date":
+			fmLines[i] = strings.TrimSpace("date: " + meta.Date)
+		default:
+			if v, ok := lookupPair(src.frontmatter, key, false); ok {
+				fmLines[i] = key + ": " + v
+				usedFM[key] = true
+			} else if isPlaceholder(stripQuotes(strings.TrimSpace(m[2]))) {
+				fmLines[i] = key + ":"
+			}
+		}
+	}
+	for _, p := range src.frontmatter {
+		if !usedFM[p.key] {
+			fmLines = append(fmLines, p.key+": "+p.value)
+			hasFrontmatter = true
+		}
+	}
+	if !statusPlaced {
+		status := docSection{level: 2, heading: "Status", body: src.status.sectionText(), filled: true}
+		sections = append([]docSection{status}, sections...)
+	}
+
+	// Keep the date as a title-block line when the target has no place for it.
+	metaLines := src.meta
+	hasDate := dateUpperPattern.MatchString(preamble)
+	for _, line := range fmLines {
+		hasDate = hasDate || strings.HasPrefix(line, "date:")
+	}
+	if !hasDate && meta.Date != "" {
+		metaLines = append([]metaPair{{key: "Date", value: meta.Date}}, metaLines...)
+	}
+	preamble = convertPreamble(preamble, meta.Date, metaLines)
+
+	// Sections, then Relations, then whatever found no home.
+	var unmapped []docSection // at their new level
+	var moreInfo string
+	type open struct {
+		level, newLevel int // newLevel is 0 for a mapped section
+	}
+	var stack []open
+	for _, s := range src.sections {
+		for len(stack) > 0 && stack[len(stack)-1].level >= s.level {
+			stack = stack[:len(stack)-1]
+		}
+		if i := findTargetSection(sections, s.heading); i >= 0 {
+			sections[i].body = s.body
+			sections[i].filled = true
+			stack = append(stack, open{level: s.level})
+			continue
+		}
+		if s.level == 2 && strings.EqualFold(s.heading, "More Information") {
+			// The target has none: this becomes the start of the one added.
+			moreInfo = s.body
+			stack = append(stack, open{level: s.level, newLevel: 2})
+			continue
+		}
+		// Unmapped sections move under "## More Information", keeping their
+		// nesting among themselves.
+		level := 3
+		if len(stack) > 0 && stack[len(stack)-1].newLevel > 0 {
+			parent := stack[len(stack)-1]
+			level = min(parent.newLevel+s.level-parent.level, 6)
+		}
+		stack = append(stack, open{level: s.level, newLevel: level})
+		s.level = level
+		unmapped = append(unmapped, s)
+	}
+
+	if src.relations != "" {
+		if i := findTargetSection(sections, "Relations"); i >= 0 {
+			sections[i].body = src.relations
+			sections[i].filled = true
+		} else {
+			at := 0
+			for i := range sections {
+				if sections[i].level == 2 && strings.EqualFold(sections[i].heading, "Status") {
+					at = i + 1
+					break
+				}
+			}
+			relations := docSection{level: 2, heading: "Relations", body: src.relations, filled: true}
+			sections = append(sections[:at], append([]docSection{relations}, sections[at:]...)...)
+		}
+	}
+
+	var extra []string
+	if moreInfo != "" && !isPlaceholderText(moreInfo) {
+		extra = append(extra, moreInfo)
+	}
+	if src.prose != "" {
+		extra = append(extra, src.prose)
+	}
+	if src.status.extra != "" {
+		extra = append(extra, src.status.extra)
+	}
+	keep := keptUnmapped(unmapped)
+	for i, s := range unmapped {
+		if !keep[i] {
+			continue
+		}
+		block := strings.Repeat("#", s.level) + " " + s.heading
+		if s.body != "" {
+			block += "\n\n" + s.body
+		}
+		extra = append(extra, block)
+	}
+	if len(extra) > 0 {
+		text := strings.Join(extra, "\n\n")
+		if i := findTargetSection(sections, "More Information"); i >= 0 {
+			if sections[i].filled && sections[i].body != "" {
+				text = sections[i].body + "\n\n" + text
+			}
+			sections[i].body = text
+			sections[i].filled = true
+		} else {
+			sections = append(sections, docSection{level: 2, heading: "More Information", body: text, filled: true})
+		}
+	}
+
+	sections = dropUnfilledOptional(sections, tmpl.Sections)
+
+	if !hasFrontmatter {
+		fmLines = nil
+	}
+	return style.restore(joinDocument(fmLines, preamble, sections)), nil
+}
+
+// joinDocument assembles an ADR from its frontmatter lines (none for nil), the
+// preamble and the sections, separated by blank lines.
+func joinDocument(fmLines []string, preamble string, sections []docSection) string {
+	var b strings.Builder
+	if fmLines != nil {
+		b.WriteString("---\n")
+		for _, line := range fmLines {
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("---\n\n")
+	}
+	b.WriteString(preamble)
+	for _, s := range sections {
+		b.WriteString("\n\n" + strings.Repeat("#", s.level) + " " + s.heading)
+		if s.body != "" {
+			b.WriteString("\n\n" + s.body)
+		}
+	}
+	return strings.TrimSpace(b.String()) + "\n"
+}
+
+// convertSource is the content of an ADR being converted.
+type convertSource struct {
+	status      statusParts
+	frontmatter []metaPair
+	meta        []metaPair
+	// prose is title-block text other than metadata lines.
+	prose     string
+	sections  []docSection
+	relations string
+}
+
+func parseConvertSource(content string) convertSource {
+	var src convertSource
+
+	fm, _ := frontmatterLines(content)
+	for _, line := range fm {
+		m := frontmatterKeyLinePattern.FindStringSubmatch(line)
+		if m == nil || m[1] == "status" || m[1] == "date" {
+			continue
+		}
+		value := strings.TrimSpace(m[2])
+		if v := stripQuotes(value); v == "" || isPlaceholder(v) {
+			continue
+		}
+		src.frontmatter = append(src.frontmatter, metaPair{key: m[1], value: value})
+	}
+
+	preamble, sections := splitDocument(bodyAfterFrontmatter(content))
+	if h, ok := titleHeading(preamble); ok {
+		preamble = preamble[:h.Start] + preamble[h.End:]
+	}
+	var prose []string
+	for _, line := range strings.Split(preamble, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if m := templateMetaLinePattern.FindStringSubmatch(trimmed); m != nil {
+			label := strings.TrimSpace(m[1])
+			if strings.EqualFold(label, "Date") || strings.EqualFold(label, "Status") {
+				continue
+			}
+			if v := strings.TrimSpace(m[2]); v != "" && !isPlaceholder(v) {
+				src.meta = append(src.meta, metaPair{key: label, value: v})
+			}
+			continue
+		}
+		prose = append(prose, line)
+	}
+	src.prose = strings.TrimSpace(strings.Join(prose, "\n"))
+
+	statusFound := false
+	for _, s := range sections {
+		switch {
+		case s.level == 2 && strings.EqualFold(s.heading, "Status") && !statusFound:
+			src.status = parseStatusSection(s.body)
+			statusFound = true
+		case s.level == 2 && strings.EqualFold(s.heading, "Relations"):
+			src.relations = s.body
+		default:
+			src.sections = append(src.sections, s)
+		}
+	}
+	if !statusFound && hasFrontmatterStatus(content) {
+		src.status = parseFrontmatterStatus(getFrontmatterStatusValue(content))
+	}
+	return src
+}
+
+// splitDocument splits markdown into the preamble before the first heading of
+// level 2 or deeper (the title and its metadata lines) and the sections that
+// follow. Headings inside code fences are body text.
+func splitDocument(body string) (string, []docSection) {
+	preamble, parsed := parseSections(body, nil)
+	sections := make([]docSection, 0, len(parsed))
+	for _, s := range parsed {
+		text := strings.TrimRight(strings.Trim(s.rawBody, "\n"), " \t\n")
+		sections = append(sections, docSection{level: s.Level, heading: s.Heading, body: text})
+	}
+	return strings.Trim(preamble, "\n"), sections
+}
+
+// frontmatterLines returns the lines of content's YAML frontmatter, and
+// whether it has any.
+func frontmatterLines(content string) ([]string, bool) {
+	fm := extractFrontmatter(content)
+	if fm == "" {
+		return nil, false
+	}
+	return strings.Split(strings.Trim(fm, "\n"), "\n"), true
+}
+
+// convertPreamble fills in the target title block: the date, the source's
+// metadata lines where the target has the same label, and the remaining
+// source lines after the target's last metadata line (or the title).
+func convertPreamble(preamble, date string, meta []metaPair) string {
+	lines := strings.Split(preamble, "\n")
+	used := make(map[string]bool)
+	insertAt, titleFirst, titleLast := -1, -1, -1
+	if h, ok := titleHeading(preamble); ok {
+		titleFirst, titleLast = headingLines(preamble, h)
+		insertAt = titleLast
+	}
+	for i, line := range lines {
+		if i >= titleFirst && i <= titleLast {
+			continue
+		}
+		m := templateMetaLinePattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		label := strings.TrimSpace(m[1])
+		insertAt = max(insertAt, i)
+		if strings.EqualFold(label, "Date") {
+			lines[i] = strings.TrimSpace("Date: " + date)
+		} else if v, ok := lookupPair(meta, label, true); ok {
+			lines[i] = label + ": " + v
+			used[strings.ToLower(label)] = true
+		}
+	}
+
+	var add []string
+	for _, p := range meta {
+		if !used[strings.ToLower(p.key)] {
+			add = append(add, "", p.key+": "+p.value)
+		}
+	}
+	if len(add) == 0 {
+		return strings.Join(lines, "\n")
+	}
+	if insertAt < 0 {
+		return strings.TrimSpace(strings.Join(append(add[1:], "", preamble), "\n"))
+	}
+	out := append([]string{}, lines[:insertAt+1]...)
+	out = append(out, add...)
+	return strings.Join(append(out, lines[insertAt+1:]...), "\n")
+}
+
+func lookupPair(pairs []metaPair, key string, foldCase bool) (string, bool) {
+	for _, p := range pairs {
+		if p.key == key || foldCase && strings.EqualFold(p.key, key) {
+			return p.value, true
+		}
+	}
+	return "", false
+}
+
+// findTargetSection returns the index of the unfilled section whose heading
+// matches heading directly or via sectionAliases, or -1.
+func findTargetSection(sections []docSection, heading string) int {
+	want := strings.ToLower(strings.TrimSpace(heading))
+	for i, s := range sections {
+		if !s.filled && sameSection(want, strings.ToLower(s.heading)) {
+			return i
+		}
+	}
+	return -1
+}
+
+func sameSection(a, b string) bool {
+	if a == b {
+		return true
+	}
+	for _, group := range sectionAliases {
+		inA, inB := false, false
+		for _, h := range group {
+			inA = inA || h == a
+			inB = inB || h == b
+		}
+		if inA && inB {
+			return true
+		}
+	}
+	return false
+}
+
+// keptUnmapped reports which unmapped sections carry content: a non-empty,
+// non-placeholder body, or a kept subsection.
+func keptUnmapped(sections []docSection) []bool {
+	keep := make([]bool, len(sections))
+	for i := len(sections) - 1; i >= 0; i-- {
+		s := sections[i]
+		if isPlaceholder(s.heading) {
+			continue
+		}
+		keep[i] = !isPlaceholderText(s.body)
+		for j := i + 1; j < len(sections) && sections[j].level > s.level; j++ {
+			keep[i] = keep[i] || keep[j]
+		}
+	}
+	return keep
+}
+
+// isPlaceholderText reports whether body holds nothing but unfilled template
+// guidance: "{…}" placeholders, possibly as list items, "…" and HTML comments.
+func isPlaceholderText(body string) bool {
+	for _, line := range strings.Split(htmlCommentPattern.ReplaceAllString(body, ""), "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "*-"))
+		if line != "" && line != "…" && !isPlaceholder(line) {
+			return false
+		}
+	}
+	return true
+}
+
+// dropUnfilledOptional removes target sections nothing was written to when
+// they are optional in defs or example headings like "### {title of option}",
+// unless a subsection of theirs is kept.
+func dropUnfilledOptional(sections []docSection, defs []TemplateSectionDef) []docSection {
+	optional := func(s docSection) bool {
+		if isPlaceholder(s.heading) {
+			return true
+		}
+		for _, d := range defs {
+			if (d.Kind == "h2" || d.Kind == "h3") && strings.EqualFold(d.Heading, s.heading) {
+				return d.Optional
+			}
+		}
+		return false
+	}
+
+	keep := make([]bool, len(sections))
+	for i := len(sections) - 1; i >= 0; i-- {
+		keep[i] = sections[i].filled || !optional(sections[i])
+		for j := i + 1; j < len(sections) && sections[j].level > sections[i].level; j++ {
+			keep[i] = keep[i] || keep[j]
+		}
+	}
+	var out []docSection
+	for i, s := range sections {
+		if keep[i] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// parseStatusSection splits the body of a "## Status" section.
+func parseStatusSection(body string) statusParts {
+	var p statusParts
+	var extra []string
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case p.line == "" && trimmed == "":
+		case p.line == "":
+			p.line = trimmed
+		case strings.HasPrefix(trimmed, "Supersedes ") && adrLinkNumberPattern.MatchString(trimmed):
+			p.supersedes = append(p.supersedes, adrLinkNumberPattern.FindAllString(trimmed, -1)...)
+		default:
+			extra = append(extra, strings.TrimRight(line, " \t"))
+		}
+	}
+	p.extra = strings.TrimSpace(strings.Join(extra, "\n"))
+	return p
+}
+
+// parseFrontmatterStatus splits a frontmatter status value such as
+// "accepted, supersedes [ADR-0001](…), [ADR-0002](…)".
+func parseFrontmatterStatus(value string) statusParts {
+	line, rest, _ := strings.Cut(strings.TrimSpace(value), ", supersedes ")
+	return statusParts{line: strings.TrimSpace(line), supersedes: adrLinkNumberPattern.FindAllString(rest, -1)}
+}
+
+// sectionText formats p as a "## Status" body, as SetSupersededBy and
+// SetSupersedes write it.
+func (p statusParts) sectionText() string {
+	text := upperFirst(p.line)
+	if text == "" {
+		text = Proposed.String()
+	}
+	if adrLinkNumberPattern.MatchString(text) {
+		text += "  "
+	}
+	if len(p.supersedes) > 0 {
+		lines := make([]string, len(p.supersedes))
+		for i, link := range p.supersedes {
+			lines[i] = "Supersedes " + link + "  "
+		}
+		text += "\n\n" + strings.Join(lines, "\n")
+	}
+	return text
+}
+
+// frontmatterValue formats p as a frontmatter "status:" value, as
+// SetSupersededBy and SetSupersedes write it.
+func (p statusParts) frontmatterValue() string {
+	value := lowerFirst(p.line)
+	if value == "" {
+		value = strings.ToLower(Proposed.String())
+	}
+	if len(p.supersedes) > 0 {
+		value += ", supersedes " + strings.Join(p.supersedes, ", ")
+	}
+	if adrLinkNumberPattern.MatchString(value) {
+		value += "  "
+	}
+	return value
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
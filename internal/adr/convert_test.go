@@ -0,0 +1,345 @@
+package adr_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func builtinTemplate(t *testing.T, name string) *adr.ProjectTemplate {
+	t.Helper()
+	tmpl, err := adr.LoadProjectTemplate(&adr.Config{Directory: t.TempDir()}, name)
+	require.NoError(t, err)
+	return tmpl
+}
+
+const nygardADR = `# 3. Use Kafka
+
+Date: 2024-01-02
+
+Scope: Backend
+
+## Status
+
+Accepted
+
+Supersedes [ADR-0001](0001-use-rabbitmq.md)  
+
+## Relations
+
+Relates to [ADR-0002](0002-use-go.md)
+
+## Context
+
+We need durable events.
+
+## Decision
+
+Use Kafka.
+
+## Consequences
+
+More ops work.
+
+## Notes
+
+See the wiki.
+
+### Benchmarks
+
+Fast enough.
+`
+
+func TestConvertContent_NygardToMADRFull(t *testing.T) {
+	got, err := adr.ConvertContent(nygardADR, 3, builtinTemplate(t, "madr-full"))
+	require.NoError(t, err)
+
+	assert.Equal(t, `---
+# These are optional metadata elements. Feel free to remove any of them.
+status: "accepted, supersedes [ADR-0001](0001-use-rabbitmq.md)  "
+date: 2024-01-02
+decision-makers:
+consulted:
+informed:
+---
+
+# 3. Use Kafka
+
+Scope: Backend
+
+## Relations
+
+Relates to [ADR-0002](0002-use-go.md)
+
+## Context and Problem Statement
+
+We need durable events.
+
+## Considered Options
+
+* {title of option 1}
+* {title of option 2}
+* {title of option 3}
+* … <!-- numbers of options can vary -->
+
+## Decision Outcome
+
+Use Kafka.
+
+### Consequences
+
+More ops work.
+
+## More Information
+
+### Notes
+
+See the wiki.
+
+#### Benchmarks
+
+Fast enough.
+`, got)
+
+	meta := adr.ExtractMetadata(got)
+	assert.Equal(t, "2024-01-02", meta.Date)
+	assert.Equal(t, []string{"Backend"}, meta.Meta["scope"])
+	record, err := adr.MetadataToADR(meta, 3)
+	require.NoError(t, err)
+	assert.Equal(t, adr.Accepted, record.Status)
+}
+
+func TestConvertContent_RoundTripKeepsContent(t *testing.T) {
+	madr, err := adr.ConvertContent(nygardADR, 3, builtinTemplate(t, "madr-full"))
+	require.NoError(t, err)
+
+	back, err := adr.ConvertContent(madr, 3, builtinTemplate(t, "nygard"))
+	require.NoError(t, err)
+
+	// The only difference is the section the unmapped content moved to.
+	assert.Equal(t, `# 3. Use Kafka
+
+Date: 2024-01-02
+
+Scope: Backend
+
+## Status
+
+Accepted
+
+Supersedes [ADR-0001](0001-use-rabbitmq.md)  
+
+## Relations
+
+Relates to [ADR-0002](0002-use-go.md)
+
+## Context
+
+We need durable events.
+
+## Decision
+
+Use Kafka.
+
+## Consequences
+
+More ops work.
+
+## More Information
+
+### Notes
+
+See the wiki.
+
+#### Benchmarks
+
+Fast enough.
+`, back)
+}
+
+func TestConvertContent_MADRFullToNygard(t *testing.T) {
+	src := `---
+status: "superseded by [ADR-0009](0009-use-nats.md)  "
+date: 2023-05-06
+decision-makers: Alice, Bob
+consulted: {list everyone whose opinions are sought}
+---
+
+# Use Kafka
+
+## Context and Problem Statement
+
+Events.
+
+## Considered Options
+
+* Kafka
+* NATS
+
+## Decision Outcome
+
+Chosen option: "Kafka".
+
+### Consequences
+
+* Good, because durable
+
+### Confirmation
+
+Load test.
+`
+	got, err := adr.ConvertContent(src, 4, builtinTemplate(t, "nygard"))
+	require.NoError(t, err)
+
+	assert.Equal(t, `---
+decision-makers: Alice, Bob
+---
+
+# 4. Use Kafka
+
+Date: 2023-05-06
+
+## Status
+
+Superseded by [ADR-0009](0009-use-nats.md)  
+
+## Context
+
+Events.
+
+## Decision
+
+Chosen option: "Kafka".
+
+## Consequences
+
+* Good, because durable
+
+## More Information
+
+### Considered Options
+
+* Kafka
+* NATS
+
+### Confirmation
+
+Load test.
+`, got)
+
+	record, err := adr.MetadataToADR(adr.ExtractMetadata(got), 4)
+	require.NoError(t, err)
+	assert.Equal(t, adr.Superseded, record.Status)
+	assert.Equal(t, []string{"Alice", "Bob"}, record.Meta["decision-makers"])
+}
+
+func TestConvertContent_CustomAndQualifiedLabels(t *testing.T) {
+	src := `---
+status: "accepted, supersedes [payments:ADR-0001](../../services/payments/docs/adr/0001-use-adyen.md), [API-002](API-002-use-rest.md)"
+---
+
+# Use gRPC
+
+## Context and Problem Statement
+
+Calls.
+`
+	got, err := adr.ConvertContent(src, 4, builtinTemplate(t, "nygard"))
+	require.NoError(t, err)
+	assert.Contains(t, got, "## Status\n\nAccepted\n\n"+
+		"Supersedes [payments:ADR-0001](../../services/payments/docs/adr/0001-use-adyen.md)  \n"+
+		"Supersedes [API-002](API-002-use-rest.md)  \n")
+
+	src = `# 7. Use gRPC
+
+## Status
+
+Accepted
+
+Supersedes [API-001](API-001-use-soap.md)  
+Supersedes [billing/ADR-0003](../../../billing/docs/adr/0003-use-rest.md)  
+
+## Context
+
+Calls.
+`
+	got, err = adr.ConvertContent(src, 7, builtinTemplate(t, "madr-full"))
+	require.NoError(t, err)
+	assert.Contains(t, got, `status: "accepted, supersedes [API-001](API-001-use-soap.md), `+
+		`[billing/ADR-0003](../../../billing/docs/adr/0003-use-rest.md)  "`)
+	assert.NotContains(t, got, "More Information")
+}
+
+func TestConvertContent_TargetWithoutStatusGetsStatusSection(t *testing.T) {
+	got, err := adr.ConvertContent(nygardADR, 3, builtinTemplate(t, "madr-minimal"))
+	require.NoError(t, err)
+
+	assert.Contains(t, got, "# 3. Use Kafka\n\nDate: 2024-01-02\n\nScope: Backend\n\n## Status\n\nAccepted\n\nSupersedes [ADR-0001](0001-use-rabbitmq.md)  \n")
+}
+
+func TestConvertContent_ScopeIntoScopedTemplate(t *testing.T) {
+	src := "# 2. Use Go\n\nDate: 2024-01-02\n\nScope: Backend\n\n## Status\n\nProposed\n\n## Context\n\nWhy.\n\n## Decision\n\nGo.\n\n## Consequences\n\nFine.\n"
+	got, err := adr.ConvertContent(src, 2, builtinTemplate(t, "nygard-scoped"))
+	require.NoError(t, err)
+
+	assert.Equal(t, src, got)
+}
+
+func TestConvertContent_StatusExtraTextAndProseGoToMoreInformation(t *testing.T) {
+	src := "# 2. Use Go\n\nDate: 2024-01-02\n\nDrafted at the offsite.\n\n## Status\n\nAccepted\n\nPending budget.\n\n## Context\n\nWhy.\n"
+	got, err := adr.ConvertContent(src, 2, builtinTemplate(t, "madr-full"))
+	require.NoError(t, err)
+
+	assert.Contains(t, got, "## More Information\n\nDrafted at the offsite.\n\nPending budget.\n")
+}
+
+func TestConvertContent_HeadingsInCodeFencesStayInBody(t *testing.T) {
+	src := "# 2. Use Go\n\nDate: 2024-01-02\n\n## Status\n\nProposed\n\n## Context\n\n```md\n## Not a heading\n```\n\n## Decision\n\nGo.\n"
+	got, err := adr.ConvertContent(src, 2, builtinTemplate(t, "madr-minimal"))
+	require.NoError(t, err)
+
+	assert.Contains(t, got, "## Context and Problem Statement\n\n```md\n## Not a heading\n```\n\n## Considered Options")
+}
+
+func TestConvertContent_NoTitle(t *testing.T) {
+	_, err := adr.ConvertContent("## Status\n\nAccepted\n", 1, builtinTemplate(t, "nygard"))
+	assert.ErrorIs(t, err, adr.ErrInvalidRecord)
+}
+
+func TestFileRepository_PlanAndApplyConversions(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0003-use-kafka.md"), []byte(nygardADR), 0o644))
+	plain := "# 4. Use Go\n\nDate: 2024-01-02\n\n## Status\n\nProposed\n\n## Context\n\nWhy.\n\n## Decision\n\nGo.\n\n## Consequences\n\nFine.\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0004-use-go.md"), []byte(plain), 0o644))
+	repo := adr.NewFileRepository(dir)
+
+	plan, err := repo.PlanConversions(context.Background(), nil, builtinTemplate(t, "nygard"))
+	require.NoError(t, err)
+	require.Len(t, plan, 2)
+	assert.True(t, plan[0].Changed(), "Notes move to More Information")
+	assert.False(t, plan[1].Changed())
+
+	// Nothing is written until the plan is applied.
+	data, err := os.ReadFile(filepath.Join(dir, "0003-use-kafka.md"))
+	require.NoError(t, err)
+	assert.Equal(t, nygardADR, string(data))
+
+	require.NoError(t, repo.ApplyConversions(context.Background(), plan))
+	data, err = os.ReadFile(filepath.Join(dir, "0003-use-kafka.md"))
+	require.NoError(t, err)
+	assert.Equal(t, plan[0].After, string(data))
+	_, err = os.Stat(filepath.Join(dir, adr.JournalFileName))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestFileRepository_PlanConversions_UnknownADR(t *testing.T) {
+	repo := adr.NewFileRepository(t.TempDir())
+
+	_, err := repo.PlanConversions(context.Background(), []int{7}, builtinTemplate(t, "nygard"))
+	assert.ErrorIs(t, err, adr.ErrNotFound)
+}
@@ -12,6 +12,7 @@ import (
 // title-block line — the form the app itself writes via ReplaceMetaField — is
 // discovered. The bool is false when no Scope line exists.
 func ExtractScope(content string) (string, bool) {
+	content, _ = normalizeText(content)
 	m := metaFieldPattern("Scope").FindStringSubmatch(bodyAfterFrontmatter(content))
 	if m == nil {
 		return "", false
@@ -19,23 +20,29 @@ func ExtractScope(content string) (string, bool) {
 	return strings.TrimSpace(m[1]), true
 }
 
-// DiscoverScopes scans dir for ADR files and returns every scope token found in
-// their "Scope:" lines, comma-split and trimmed. The result is RAW: it preserves
-// file+line order, keeps duplicates, and is NOT validated — callers must pass it
-// through Config.MergeScopes before use. A missing directory yields (nil, nil)
-// rather than an error; unreadable individual files are skipped.
+// DiscoverScopes scans dir for ADR files, named the default way, and returns
+// every scope token found in their "Scope:" lines, comma-split and trimmed. The
+// result is RAW: it preserves file+line order, keeps duplicates, and is NOT
+// validated — callers must pass it through Config.MergeScopes before use. A
+// missing directory yields (nil, nil) rather than an error; unreadable
+// individual files are skipped.
 func DiscoverScopes(dir string) ([]string, error) {
-	files, err := listADRFiles(dir)
+	return NewFileRepository(dir).discoverScopes()
+}
+
+// discoverScopes is DiscoverScopes for the repository's ADR files.
+func (r *FileRepository) discoverScopes() ([]string, error) {
+	files, err := r.listFiles()
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil
 		}
-		return nil, fmt.Errorf("reading directory %q: %w", dir, err)
+		return nil, fmt.Errorf("reading directory %q: %w", r.dir, err)
 	}
 
 	var tokens []string
 	for _, f := range files {
-		content, err := os.ReadFile(filepath.Join(dir, f.Name))
+		content, err := os.ReadFile(filepath.Join(r.dir, f.Name))
 		if err != nil {
 			continue // best-effort: skip files we can't read
 		}
@@ -81,7 +88,7 @@ func (c *Config) MergeScopes(values []string) (added, invalid []string) {
 // `adr scope discover`, and the web server at boot, so the discovery behavior
 // can't drift between call sites.
 func DiscoverAndMergeScopes(cfg *Config) (added, invalid []string, err error) {
-	raw, err := DiscoverScopes(cfg.Directory)
+	raw, err := cfg.Repository().discoverScopes()
 	if err != nil {
 		return nil, nil, err
 	}
@@ -0,0 +1,236 @@
+package adr
+
+import (
+	"strings"
+
+	"github.com/BobMali/adr-helper/internal/markdown"
+)
+
+// Document is an ADR parsed into its YAML frontmatter, its preamble (the title
+// and title-block lines) and its "##"-or-deeper sections, in file order.
+// The source text is kept alongside the parsed fields, so String returns the
+// parsed content byte for byte until a field is changed. The fields have LF
+// line endings; String writes CRLF ones (when most source lines had them) and
+// a byte order mark back when the source had them.
+type Document struct {
+	// Frontmatter maps each top-level frontmatter key to its value, with
+	// surrounding double quotes removed; nil when the ADR has no frontmatter.
+	// It is read-only: String always writes the frontmatter as parsed.
+	Frontmatter map[string]string
+	// Preamble is the text before the first section, blank lines trimmed.
+	Preamble string
+	Sections []Section
+
+	source         string
+	normalized     string
+	rawFrontmatter string
+	rawPreamble    string
+	parsedPreamble string
+	style          lineStyle
+}
+
+// Section is a heading and the text under it, up to the next heading of any
+// level; a "###" section inside a "##" one is a separate Section.
+type Section struct {
+	Level   int    `json:"level"`
+	Heading string `json:"heading"`
+	// Key is the key of the TemplateSectionDef whose heading and level match,
+	// or "" for a section no known template defines.
+	Key string `json:"key,omitempty"`
+	// Body is the section text, blank lines around it trimmed.
+	Body string `json:"body"`
+
+	rawHeading    string
+	rawBody       string
+	parsedLevel   int
+	parsedHeading string
+	parsedBody    string
+}
+
+// ParseDocument parses content into a Document. Section keys come from defs
+// (e.g. KnownSections): a section gets the key of the first "h2" or "h3"
+// definition with its level and heading, compared case-insensitively.
+// Headings are those of the markdown block parser (see bodyHeadings), so a
+// "## Status" line inside a code fence or HTML comment is body text.
+func ParseDocument(content string, defs []TemplateSectionDef) *Document {
+	doc := &Document{source: content}
+	content, doc.style = normalizeText(content)
+	doc.normalized = content
+	body := bodyAfterFrontmatter(content)
+	if len(body) < len(content) {
+		doc.rawFrontmatter = content[:len(content)-len(body)]
+		doc.Frontmatter = parseFrontmatterMap(extractFrontmatter(content))
+	}
+
+	doc.rawPreamble, doc.Sections = parseSections(body, defs)
+	doc.Preamble = trimBlankLines(doc.rawPreamble)
+	doc.parsedPreamble = doc.Preamble
+	for i := range doc.Sections {
+		s := &doc.Sections[i]
+		s.Body = trimBlankLines(s.rawBody)
+		s.parsedLevel, s.parsedHeading, s.parsedBody = s.Level, s.Heading, s.Body
+	}
+	return doc
+}
+
+// String returns the document as markdown. Unchanged parts are written as
+// parsed; a changed heading is written as "## Heading" and a changed body or
+// preamble between single blank lines.
+func (d *Document) String() string {
+	var b strings.Builder
+	b.WriteString(d.rawFrontmatter)
+	if d.Preamble == d.parsedPreamble {
+		b.WriteString(d.rawPreamble)
+	} else if d.Preamble != "" {
+		b.WriteString(d.Preamble + "\n\n")
+	}
+	for i, s := range d.Sections {
+		if s.rawHeading != "" && s.Level == s.parsedLevel && s.Heading == s.parsedHeading {
+			b.WriteString(s.rawHeading)
+		} else {
+			b.WriteString(strings.Repeat("#", s.Level) + " " + s.Heading + "\n")
+		}
+		switch {
+		case s.rawHeading != "" && s.Body == s.parsedBody:
+			b.WriteString(s.rawBody)
+		case s.Body == "":
+			b.WriteString("\n")
+		case i == len(d.Sections)-1:
+			b.WriteString("\n" + s.Body + "\n")
+		default:
+			b.WriteString("\n" + s.Body + "\n\n")
+		}
+	}
+	if b.String() == d.normalized {
+		return d.source
+	}
+	return d.style.restore(b.String())
+}
+
+// Section returns the first section with the given key, or nil.
+func (d *Document) Section(key string) *Section {
+	for i := range d.Sections {
+		if d.Sections[i].Key == key && key != "" {
+			return &d.Sections[i]
+		}
+	}
+	return nil
+}
+
+// parseSections splits body (an ADR without frontmatter) at its "##"-or-deeper
+// headings, returning the raw preamble and the sections with their raw text.
+func parseSections(body string, defs []TemplateSectionDef) (string, []Section) {
+	var hs []markdown.Heading
+	for _, h := range markdown.Headings(body) {
+		if h.Level >= 2 {
+			hs = append(hs, h)
+		}
+	}
+	if len(hs) == 0 {
+		return body, nil
+	}
+	sections := make([]Section, len(hs))
+	for i, h := range hs {
+		end := len(body)
+		if i+1 < len(hs) {
+			end = hs[i+1].Start
+		}
+		sections[i] = Section{
+			Level: h.Level, Heading: h.Text, Key: sectionKey(defs, h.Level, h.Text),
+			rawHeading: body[h.Start:h.End], rawBody: body[h.End:end],
+		}
+	}
+	return body[:hs[0].Start], sections
+}
+
+// bodyHeadings returns the headings of content's body, after any frontmatter,
+// with offsets into content. Headings are found by the markdown block parser,
+// so setext headings count and heading-like lines in code blocks, HTML
+// comments, lists and block quotes don't.
+func bodyHeadings(content string) []markdown.Heading {
+	body := bodyAfterFrontmatter(content)
+	hs := markdown.Headings(body)
+	shift := len(content) - len(body)
+	for i := range hs {
+		hs[i].Start += shift
+		hs[i].End += shift
+	}
+	return hs
+}
+
+// findSection returns the first heading of content at level whose text is
+// name (compared case-insensitively), and the offset its section ends at: the
+// next heading of the same or a higher level, or the end of content.
+func findSection(content string, level int, name string) (markdown.Heading, int, bool) {
+	hs := bodyHeadings(content)
+	for i, h := range hs {
+		if h.Level == level && strings.EqualFold(h.Text, name) {
+			end := len(content)
+			for _, next := range hs[i+1:] {
+				if next.Level <= level {
+					end = next.Start
+					break
+				}
+			}
+			return h, end, true
+		}
+	}
+	return markdown.Heading{}, 0, false
+}
+
+// headingLines returns the indexes of the first and last line of heading h in
+// s, split at "\n"; they differ for a setext heading.
+func headingLines(s string, h markdown.Heading) (int, int) {
+	first := strings.Count(s[:h.Start], "\n")
+	return first, first + strings.Count(strings.TrimSuffix(s[h.Start:h.End], "\n"), "\n")
+}
+
+// replaceSectionBody returns content with the text between heading h and end
+// replaced by body, set off by single blank lines.
+func replaceSectionBody(content string, h markdown.Heading, end int, body string) string {
+	head := content[:h.End]
+	if !strings.HasSuffix(head, "\n") {
+		head += "\n"
+	}
+	if end == len(content) {
+		return head + "\n" + body + "\n"
+	}
+	return head + "\n" + body + "\n\n" + content[end:]
+}
+
+func sectionKey(defs []TemplateSectionDef, level int, heading string) string {
+	for _, d := range defs {
+		if (d.Kind == "h2" && level == 2 || d.Kind == "h3" && level == 3) && strings.EqualFold(d.Heading, heading) {
+			return d.Key
+		}
+	}
+	return ""
+}
+
+// parseFrontmatterMap reads the top-level "key: value" lines of a frontmatter
+// block; indented and other lines are skipped.
+func parseFrontmatterMap(fm string) map[string]string {
+	m := make(map[string]string)
+	for _, line := range strings.Split(fm, "\n") {
+		if kv := frontmatterKeyLinePattern.FindStringSubmatch(strings.TrimRight(line, " \t\r")); kv != nil {
+			m[kv[1]] = stripQuotes(strings.TrimSpace(kv[2]))
+		}
+	}
+	return m
+}
+
+// trimBlankLines removes leading blank lines and trailing whitespace, keeping
+// the indentation of the first line.
+func trimBlankLines(s string) string {
+	s = strings.TrimRight(s, " \t\r\n")
+	for {
+		line, rest, ok := strings.Cut(s, "\n")
+		if strings.TrimSpace(line) != "" {
+			return s
+		}
+		if !ok {
+			return ""
+		}
+		s = rest
+	}
+}
@@ -0,0 +1,60 @@
+package adr
+
+import (
+	"strings"
+	"testing"
+)
+
+// FuzzParseUpdateRoundTrip checks the section model against arbitrary
+// markdown: parsing is lossless, UpdateStatus writes a status that reads back,
+// and ReplaceSectionContent changes one section body and no headings. Seeds
+// beyond the ones below live in testdata/fuzz/FuzzParseUpdateRoundTrip.
+func FuzzParseUpdateRoundTrip(f *testing.F) {
+	for _, seed := range []string{
+		"",
+		"# 1. Use Go\n\nDate: 2024-01-01\n\n## Status\n\nProposed\n\n## Context\n\nSome context.\n",
+		"---\nstatus: \"proposed\"\ndate: 2024-03-15\n---\n\n# 2. Use Chi\n\n## Context and Problem Statement\n\nNeed a router.\n",
+		"# 3. Fences\n\n```\n## Status\n# not a title\n```\n\n## Status\n\nAccepted\n\nSupersedes [ADR-0001](0001-a.md)  \n",
+		"<!--\n# NUMBER. TITLE\n-->\n# 4. Comments\n\n<!-- ## Status -->\n## Status\n\nProposed",
+		"Title\n=====\n\nStatus\n------\n\nProposed\n\n### Detail\n\n    ## indented code\n",
+		"# 5. Lists\n\n- ## Status\n> ## Status\n\n## Status\r\n\r\nAccepted\r\n",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, content string) {
+		if got := ParseDocument(content, nil).String(); got != content {
+			t.Fatalf("ParseDocument(%q).String() = %q", content, got)
+		}
+
+		if updated, err := UpdateStatus(content, "accepted"); err == nil {
+			if status := ExtractMetadata(updated).Status; !strings.HasPrefix(strings.ToLower(status), "accepted") {
+				t.Fatalf("UpdateStatus(%q) = %q, which reads back status %q", content, updated, status)
+			}
+		}
+
+		before := bodyHeadings(content)
+		if len(before) == 0 {
+			return
+		}
+		updated, found := ReplaceSectionContent(content, before[0].Text, "Replaced.")
+		if !found {
+			t.Fatalf("ReplaceSectionContent(%q, %q) found no section", content, before[0].Text)
+		}
+		after := bodyHeadings(updated)
+		if len(after) != len(before) {
+			t.Fatalf("ReplaceSectionContent(%q) = %q: %d headings, want %d", content, updated, len(after), len(before))
+		}
+		for i := range before {
+			if after[i].Level != before[i].Level || after[i].Text != before[i].Text {
+				t.Fatalf("ReplaceSectionContent(%q) = %q: heading %d is %q, want %q", content, updated, i, after[i].Text, before[i].Text)
+			}
+		}
+		end := len(updated)
+		if len(after) > 1 {
+			end = after[1].Start
+		}
+		if body := strings.TrimSpace(updated[after[0].End:end]); body != "Replaced." {
+			t.Fatalf("ReplaceSectionContent(%q) = %q: section body %q", content, updated, body)
+		}
+	})
+}
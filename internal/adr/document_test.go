@@ -0,0 +1,69 @@
+package adr_test
+
+import (
+	"testing"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocument_RoundTripsByteForByte(t *testing.T) {
+	docs := map[string]string{
+		"empty":            "",
+		"title only":       "# 1. Use Go",
+		"nygard":           "# 1. Use Go\n\nDate: 2024-01-01\n\n## Status\n\nAccepted\n\n## Context\n\nSome context.\n",
+		"frontmatter":      "---\nstatus: \"accepted\"\ndate: 2024-01-01\n---\n\n# 2. Use Chi\n\n## Context and Problem Statement\n\nNeed a router.\n",
+		"no final newline": "# 1. A\n\n## Context\n\ntext",
+		"odd spacing":      "\n\n# 1. A\n\n\n##   Context  \n   indented\n\n\n\n### Detail ##\n\n  \t\n",
+		"fenced heading":   "# 1. A\n\n## Decision\n\n```md\n## Not a section\n```\n\n~~~\n# nor this\n~~~\n",
+		"crlf":             "# 1. A\r\n\r\n## Context\r\n\r\nText.\r\n",
+	}
+	for _, name := range adr.ValidTemplateNames() {
+		content, err := adr.TemplateContent(name)
+		require.NoError(t, err)
+		docs["template "+name] = content
+	}
+	for name, content := range docs {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, content, adr.ParseDocument(content, nil).String())
+		})
+	}
+}
+
+func TestParseDocument_Sections(t *testing.T) {
+	content := "---\nstatus: \"accepted\"\ndecision-makers: alice, bob\n---\n\n# 1. Use Go\n\nScope: Backend\n\n" +
+		"## Context and Problem Statement\n\nWe need a language.\n\n### Forces\n\n* Speed\n\n## Notes\n\n```\n## code\n```\n"
+	defs, err := adr.TemplateSections(string(adr.TemplateMADRFull))
+	require.NoError(t, err)
+
+	doc := adr.ParseDocument(content, defs)
+
+	assert.Equal(t, map[string]string{"status": "accepted", "decision-makers": "alice, bob"}, doc.Frontmatter)
+	assert.Equal(t, "# 1. Use Go\n\nScope: Backend", doc.Preamble)
+	require.Len(t, doc.Sections, 3)
+	assert.Equal(t, adr.Section{Level: 2, Heading: "Context and Problem Statement", Key: "context-and-problem-statement", Body: "We need a language."},
+		exported(doc.Sections[0]))
+	assert.Equal(t, adr.Section{Level: 3, Heading: "Forces", Body: "* Speed"}, exported(doc.Sections[1]))
+	assert.Equal(t, adr.Section{Level: 2, Heading: "Notes", Body: "```\n## code\n```"}, exported(doc.Sections[2]))
+	assert.Same(t, &doc.Sections[0], doc.Section("context-and-problem-statement"))
+	assert.Nil(t, doc.Section("decision-outcome"))
+	assert.Nil(t, adr.ParseDocument("# 1. A\n", nil).Frontmatter)
+}
+
+func TestDocument_StringWritesChangedFields(t *testing.T) {
+	content := "# 1. Use Go\n\nDate: 2024-01-01\n\n## Context\n\nOld context.\n\n\n## Decision\n\nUse Go.\n"
+	doc := adr.ParseDocument(content, nil)
+
+	doc.Sections[0].Body = "New context.\n\nWith two paragraphs."
+	assert.Equal(t, "# 1. Use Go\n\nDate: 2024-01-01\n\n## Context\n\nNew context.\n\nWith two paragraphs.\n\n## Decision\n\nUse Go.\n", doc.String())
+
+	doc.Sections[1].Heading = "Decision Outcome"
+	doc.Sections[1].Body = "Use Go 1.25."
+	assert.Equal(t, "# 1. Use Go\n\nDate: 2024-01-01\n\n## Context\n\nNew context.\n\nWith two paragraphs.\n\n## Decision Outcome\n\nUse Go 1.25.\n", doc.String())
+}
+
+// exported returns s with only its exported fields, for comparison.
+func exported(s adr.Section) adr.Section {
+	return adr.Section{Level: s.Level, Heading: s.Heading, Key: s.Key, Body: s.Body}
+}
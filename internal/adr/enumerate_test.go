@@ -11,13 +11,13 @@ import (
 
 func TestListADRFiles(t *testing.T) {
 	t.Run("empty dir", func(t *testing.T) {
-		files, err := listADRFiles(t.TempDir())
+		files, err := listADRFiles(t.TempDir(), Naming{}, Discovery{})
 		require.NoError(t, err)
 		assert.Empty(t, files)
 	})
 
 	t.Run("missing dir returns a raw os.IsNotExist error", func(t *testing.T) {
-		_, err := listADRFiles(filepath.Join(t.TempDir(), "nope"))
+		_, err := listADRFiles(filepath.Join(t.TempDir(), "nope"), Naming{}, Discovery{})
 		require.Error(t, err)
 		assert.True(t, os.IsNotExist(err), "raw error must satisfy os.IsNotExist")
 	})
@@ -29,7 +29,7 @@ func TestListADRFiles(t *testing.T) {
 		}
 		require.NoError(t, os.MkdirAll(filepath.Join(dir, "0009-x.md"), 0o755)) // dir named like an ADR
 
-		files, err := listADRFiles(dir)
+		files, err := listADRFiles(dir, Naming{}, Discovery{})
 		require.NoError(t, err)
 		assert.Equal(t, []adrFile{
 			{Number: 1, Name: "0001-a.md"},
@@ -42,7 +42,7 @@ func TestListADRFiles(t *testing.T) {
 		require.NoError(t, os.WriteFile(filepath.Join(dir, "99999999999999999999-x.md"), []byte(""), 0o644)) // 20 nines
 		require.NoError(t, os.WriteFile(filepath.Join(dir, "0001-ok.md"), []byte(""), 0o644))
 
-		files, err := listADRFiles(dir)
+		files, err := listADRFiles(dir, Naming{}, Discovery{})
 		require.NoError(t, err)
 		assert.Equal(t, []adrFile{{Number: 1, Name: "0001-ok.md"}}, files)
 	})
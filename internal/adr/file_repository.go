@@ -5,39 +5,81 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
+	"strings"
+	"time"
 )
 
 // FileRepository implements Repository by reading ADR markdown files from a directory.
 type FileRepository struct {
-	dir string
+	dir       string
+	naming    Naming
+	discovery Discovery
+	// root names the root the repository's ADRs belong to (see ADR.Root).
+	root string
+	// metaFields are the metadata fields read into ADR.Meta: the project's
+	// (see Config.MetaFieldDefs), or the built-ins when nil.
+	metaFields *metaFieldRegistry
+	// linking returns the other roots' and projects' directories whose links
+	// to the repository's ADRs are rewritten with them; nil for none.
+	linking func() ([]linkingDir, error)
 }
 
-// NewFileRepository creates a FileRepository rooted at dir.
+// NewFileRepository creates a FileRepository rooted at dir, naming new files
+// the default way.
 func NewFileRepository(dir string) *FileRepository {
 	return &FileRepository{dir: dir}
 }
 
+// Repository returns a FileRepository for the project's ADR directory that
+// finds and names files by the project's settings (see Naming and Discovery).
+func (c *Config) Repository() *FileRepository {
+	return &FileRepository{dir: c.Directory, naming: c.Naming(), discovery: c.Discovery(), root: c.root,
+		metaFields: c.metaFieldRegistry(), linking: c.linkingDirs}
+}
+
+// Repository returns a FileRepository rooted at dir that finds and names
+// files by n.
+func (n Naming) Repository(dir string) *FileRepository {
+	return &FileRepository{dir: dir, naming: n}
+}
+
+// List returns the repository's ADRs by number, leaving out archived ones.
 func (r *FileRepository) List(_ context.Context) ([]ADR, error) {
-	files, err := listADRFiles(r.dir)
+	return r.list(false)
+}
+
+// ListWithArchived returns the repository's ADRs by number, archived ones
+// included and marked as such.
+func (r *FileRepository) ListWithArchived(_ context.Context) ([]ADR, error) {
+	return r.list(true)
+}
+
+func (r *FileRepository) list(withArchived bool) ([]ADR, error) {
+	files, err := r.listFiles()
 	if err != nil {
 		return nil, fmt.Errorf("reading directory %q: %w", r.dir, err)
 	}
 
 	var adrs []ADR
 	for _, f := range files {
+		if f.Archived && !withArchived {
+			continue
+		}
 		content, err := os.ReadFile(filepath.Join(r.dir, f.Name))
 		if err != nil {
 			continue
 		}
 
-		meta := ExtractMetadata(string(content))
-		record, err := MetadataToADR(meta, f.Number)
+		record, err := r.parse(string(content), f.Number)
 		if err != nil {
 			continue
 		}
 
+		record.Archived = f.Archived
+		record.Root = r.root
 		adrs = append(adrs, record)
 	}
 
@@ -49,7 +91,7 @@ func (r *FileRepository) List(_ context.Context) ([]ADR, error) {
 }
 
 func (r *FileRepository) Get(_ context.Context, number int) (*ADR, error) {
-	filename, err := FindADRFile(r.dir, number)
+	filename, err := r.FindFile(number)
 	if err != nil {
 		return nil, err
 	}
@@ -59,18 +101,19 @@ func (r *FileRepository) Get(_ context.Context, number int) (*ADR, error) {
 		return nil, fmt.Errorf("reading %q: %w", filename, err)
 	}
 
-	meta := ExtractMetadata(string(content))
-	record, err := MetadataToADR(meta, number)
+	record, err := r.parse(string(content), number)
 	if err != nil {
 		return nil, err
 	}
 
 	record.Content = string(content)
+	record.Archived = r.discovery.archived(filename)
+	record.Root = r.root
 	return &record, nil
 }
 
 func (r *FileRepository) NextNumber(_ context.Context) (int, error) {
-	return NextNumber(r.dir)
+	return r.nextNumber()
 }
 
 func (r *FileRepository) Save(_ context.Context, record *ADR) error {
@@ -80,8 +123,11 @@ func (r *FileRepository) Save(_ context.Context, record *ADR) error {
 	if record.Content == "" {
 		return fmt.Errorf("content must not be empty: %w", ErrInvalidRecord)
 	}
+	if _, err := r.Recover(); err != nil {
+		return err
+	}
 
-	filename, err := FormatFilename(record.Number, record.Title)
+	filename, err := r.naming.Filename(record.Number, record.Title, record.Date)
 	if err != nil {
 		return fmt.Errorf("formatting filename: %w", err)
 	}
@@ -103,13 +149,17 @@ func (r *FileRepository) Save(_ context.Context, record *ADR) error {
 }
 
 // Supersede marks the superseded ADR as "Superseded by" the superseding ADR,
-// and appends "Supersedes" to the superseding ADR. Returns the updated superseded record.
+// and appends "Supersedes" to the superseding ADR, as one journaled
+// transaction (see Rename). Returns the updated superseded record.
 func (r *FileRepository) Supersede(_ context.Context, supersededNum, supersedingNum int) (*ADR, error) {
-	supersededFile, err := FindADRFile(r.dir, supersededNum)
+	if _, err := r.Recover(); err != nil {
+		return nil, err
+	}
+	supersededFile, err := r.FindFile(supersededNum)
 	if err != nil {
 		return nil, err
 	}
-	supersedingFile, err := FindADRFile(r.dir, supersedingNum)
+	supersedingFile, err := r.FindFile(supersedingNum)
 	if err != nil {
 		return nil, err
 	}
@@ -126,32 +176,24 @@ func (r *FileRepository) Supersede(_ context.Context, supersededNum, superseding
 		return nil, fmt.Errorf("reading %q: %w", supersedingFile, err)
 	}
 
-	updatedSuperseded, err := SetSupersededBy(string(supersededContent), ADRLink{
-		Number:   supersedingNum,
-		Filename: supersedingFile,
-	})
+	updatedSuperseded, err := SetSupersededBy(string(supersededContent), r.link(supersededFile, supersedingNum, supersedingFile))
 	if err != nil {
 		return nil, fmt.Errorf("setting superseded-by on ADR %d: %w", supersededNum, err)
 	}
 
-	updatedSuperseding, err := SetSupersedes(string(supersedingContent), []ADRLink{{
-		Number:   supersededNum,
-		Filename: supersededFile,
-	}})
+	updatedSuperseding, err := SetSupersedes(string(supersedingContent), []ADRLink{r.link(supersedingFile, supersededNum, supersededFile)})
 	if err != nil {
 		return nil, fmt.Errorf("setting supersedes on ADR %d: %w", supersedingNum, err)
 	}
 
-	// Write superseding first — if it fails, the superseded file stays untouched
-	if err := os.WriteFile(supersedingPath, []byte(updatedSuperseding), 0o644); err != nil {
-		return nil, fmt.Errorf("writing %q: %w", supersedingFile, err)
-	}
-	if err := os.WriteFile(supersededPath, []byte(updatedSuperseded), 0o644); err != nil {
-		return nil, fmt.Errorf("writing %q: %w", supersededFile, err)
+	var txn fileTxn
+	txn.write(supersedingFile, updatedSuperseding)
+	txn.write(supersededFile, updatedSuperseded)
+	if err := txn.commit(r.dir); err != nil {
+		return nil, err
 	}
 
-	meta := ExtractMetadata(updatedSuperseded)
-	record, err := MetadataToADR(meta, supersededNum)
+	record, err := r.parse(updatedSuperseded, supersededNum)
 	if err != nil {
 		return nil, err
 	}
@@ -159,15 +201,17 @@ func (r *FileRepository) Supersede(_ context.Context, supersededNum, superseding
 	return &record, nil
 }
 
-// AddRelation adds bidirectional "Relates to" links between two ADR files.
-// Writes the target file first, then the source — if the target write fails, the source is untouched.
-// Note: the two-file write is not atomic (same risk as Supersede).
+// AddRelation adds bidirectional "Relates to" links between two ADR files, as
+// one journaled transaction (see Rename).
 func (r *FileRepository) AddRelation(_ context.Context, sourceNum, targetNum int) (*ADR, error) {
-	sourceFile, err := FindADRFile(r.dir, sourceNum)
+	if _, err := r.Recover(); err != nil {
+		return nil, err
+	}
+	sourceFile, err := r.FindFile(sourceNum)
 	if err != nil {
 		return nil, err
 	}
-	targetFile, err := FindADRFile(r.dir, targetNum)
+	targetFile, err := r.FindFile(targetNum)
 	if err != nil {
 		return nil, err
 	}
@@ -184,26 +228,24 @@ func (r *FileRepository) AddRelation(_ context.Context, sourceNum, targetNum int
 		return nil, fmt.Errorf("reading %q: %w", targetFile, err)
 	}
 
-	updatedSource, err := AddRelation(string(sourceContent), ADRLink{Number: targetNum, Filename: targetFile})
+	updatedSource, err := AddRelation(string(sourceContent), r.link(sourceFile, targetNum, targetFile))
 	if err != nil {
 		return nil, fmt.Errorf("adding relation to ADR %d: %w", sourceNum, err)
 	}
 
-	updatedTarget, err := AddRelation(string(targetContent), ADRLink{Number: sourceNum, Filename: sourceFile})
+	updatedTarget, err := AddRelation(string(targetContent), r.link(targetFile, sourceNum, sourceFile))
 	if err != nil {
 		return nil, fmt.Errorf("adding relation to ADR %d: %w", targetNum, err)
 	}
 
-	// Write target first — if it fails, source stays untouched
-	if err := os.WriteFile(targetPath, []byte(updatedTarget), 0o644); err != nil {
-		return nil, fmt.Errorf("writing %q: %w", targetFile, err)
-	}
-	if err := os.WriteFile(sourcePath, []byte(updatedSource), 0o644); err != nil {
-		return nil, fmt.Errorf("writing %q: %w", sourceFile, err)
+	var txn fileTxn
+	txn.write(targetFile, updatedTarget)
+	txn.write(sourceFile, updatedSource)
+	if err := txn.commit(r.dir); err != nil {
+		return nil, err
 	}
 
-	meta := ExtractMetadata(updatedSource)
-	record, err := MetadataToADR(meta, sourceNum)
+	record, err := r.parse(updatedSource, sourceNum)
 	if err != nil {
 		return nil, err
 	}
@@ -212,20 +254,26 @@ func (r *FileRepository) AddRelation(_ context.Context, sourceNum, targetNum int
 }
 
 // UpdateContent replaces the full markdown content of the ADR with the given number.
+// The file keeps its line endings and byte order mark (see MatchLineStyle).
 // This is a concrete method on FileRepository only — not part of the Repository interface.
 func (r *FileRepository) UpdateContent(_ context.Context, number int, content string) (*ADR, error) {
-	filename, err := FindADRFile(r.dir, number)
+	if _, err := r.Recover(); err != nil {
+		return nil, err
+	}
+	filename, err := r.FindFile(number)
 	if err != nil {
 		return nil, err
 	}
 
 	filePath := filepath.Join(r.dir, filename)
+	if existing, err := os.ReadFile(filePath); err == nil {
+		content = MatchLineStyle(string(existing), content)
+	}
 	if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
 		return nil, fmt.Errorf("writing %q: %w", filename, err)
 	}
 
-	meta := ExtractMetadata(content)
-	record, err := MetadataToADR(meta, number)
+	record, err := r.parse(content, number)
 	if err != nil {
 		return nil, err
 	}
@@ -239,8 +287,11 @@ func (r *FileRepository) UpdateStatus(_ context.Context, number int, newStatus s
 	if _, ok := ParseStatus(newStatus); !ok {
 		return nil, fmt.Errorf("invalid status %q", newStatus)
 	}
+	if _, err := r.Recover(); err != nil {
+		return nil, err
+	}
 
-	filename, err := FindADRFile(r.dir, number)
+	filename, err := r.FindFile(number)
 	if err != nil {
 		return nil, err
 	}
@@ -256,16 +307,454 @@ func (r *FileRepository) UpdateStatus(_ context.Context, number int, newStatus s
 		return nil, err
 	}
 
-	if err := os.WriteFile(filePath, []byte(updated), 0o644); err != nil {
-		return nil, fmt.Errorf("writing %q: %w", filename, err)
+	var txn fileTxn
+	txn.write(filename, updated)
+	if err := txn.commit(r.dir); err != nil {
+		return nil, err
+	}
+
+	record, err := r.parse(updated, number)
+	if err != nil {
+		return nil, err
+	}
+
+	record.Content = updated
+	return &record, nil
+}
+
+// Rename retitles the ADR with the given number: it rewrites the "# N. Title"
+// heading, renames the file to the new title's slug, and retargets every
+// inbound link in the other ADRs. All file changes are applied as one journaled
+// transaction, so a crash mid-way is completed by the repository's next change
+// (or RecoverTxn) rather than leaving dangling links. Renaming to a title with the same slug only
+// rewrites the heading.
+func (r *FileRepository) Rename(_ context.Context, number int, newTitle string) (*ADR, error) {
+	return r.retitle(number, strings.TrimSpace(newTitle), "")
+}
+
+// UpdateContentAndRename replaces the content of the ADR with the given number,
+// as UpdateContent does, and renames its file to the new content's title, as
+// Rename does. Both happen in one transaction: when the rename is rejected,
+// the content isn't written either.
+func (r *FileRepository) UpdateContentAndRename(_ context.Context, number int, content string) (*ADR, error) {
+	title := ExtractMetadata(content).Title
+	if title == "" {
+		return nil, fmt.Errorf("ADR %04d has no title heading: %w", number, ErrInvalidRecord)
+	}
+	return r.retitle(number, title, content)
+}
+
+// retitle renames the ADR with the given number to newTitle (see Rename),
+// after replacing its content with content unless that is empty.
+func (r *FileRepository) retitle(number int, newTitle, content string) (*ADR, error) {
+	if _, err := r.Recover(); err != nil {
+		return nil, err
 	}
 
-	meta := ExtractMetadata(updated)
-	record, err := MetadataToADR(meta, number)
+	oldFile, err := r.FindFile(number)
 	if err != nil {
 		return nil, err
 	}
+	newFile, err := r.naming.RenamedFilename(oldFile, number, newTitle)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrInvalidRecord)
+	}
+	if newFile != oldFile {
+		if _, err := os.Stat(filepath.Join(r.dir, newFile)); err == nil {
+			return nil, fmt.Errorf("file %q: %w", newFile, ErrConflict)
+		}
+	}
+
+	existing, err := os.ReadFile(filepath.Join(r.dir, oldFile))
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", oldFile, err)
+	}
+	if content == "" {
+		content = string(existing)
+	} else {
+		content = MatchLineStyle(string(existing), content)
+	}
+	updated, found := ReplaceHeading(content, number, newTitle)
+	if !found {
+		return nil, fmt.Errorf("ADR %04d has no title heading: %w", number, ErrInvalidRecord)
+	}
 
+	from := r.naming.Link(number, oldFile)
+	to := r.naming.Link(number, newFile)
+	updated, _ = RewriteADRLinks(updated, r.link(oldFile, number, oldFile), r.link(oldFile, number, newFile))
+
+	record, err := r.parse(updated, number)
+	if err != nil {
+		return nil, err
+	}
 	record.Content = updated
+
+	var txn fileTxn
+	if newFile != oldFile {
+		txn.rename(oldFile, newFile)
+		if err := r.rewriteInboundLinks(&txn, from, to, oldFile); err != nil {
+			return nil, err
+		}
+	}
+	txn.write(newFile, updated)
+	if err := txn.commit(r.dir); err != nil {
+		return nil, err
+	}
 	return &record, nil
 }
+
+// Recover completes a multi-file change interrupted in the directory (see
+// RecoverTxn), which may also have rewritten links in the directories linking
+// to it. It reports whether one was pending.
+func (r *FileRepository) Recover() (bool, error) {
+	var linked []string
+	if r.linking != nil {
+		dirs, err := r.linking()
+		if err != nil {
+			return false, err
+		}
+		for _, d := range dirs {
+			linked = append(linked, d.repo.dir)
+		}
+	}
+	return RecoverTxn(r.dir, linked...)
+}
+
+// rewriteInboundLinks adds a write to txn for every ADR in the directory (other
+// than skip), and in the directories linking to it (see Config.Repository),
+// that links to from, retargeted to to. The filenames of from and to are
+// relative to the directory; each ADR's links are relative to its own.
+func (r *FileRepository) rewriteInboundLinks(txn *fileTxn, from, to ADRLink, skip string) error {
+	files, err := r.listFiles()
+	if err != nil {
+		return fmt.Errorf("reading directory %q: %w", r.dir, err)
+	}
+	for _, f := range files {
+		if f.Name == skip {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(r.dir, f.Name))
+		if err != nil {
+			return fmt.Errorf("reading %q: %w", f.Name, err)
+		}
+		source, target := r.link(f.Name, from.Number, from.Filename), r.link(f.Name, to.Number, to.Filename)
+		if rewritten, n := RewriteADRLinks(string(content), source, target); n > 0 {
+			txn.write(f.Name, rewritten)
+		}
+	}
+	if r.linking == nil {
+		return nil
+	}
+	dirs, err := r.linking()
+	if err != nil {
+		return err
+	}
+	for _, d := range dirs {
+		if err := r.rewriteLinksIn(txn, d, from, to); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rewriteLinksIn adds a write to txn for every ADR in d that links to from,
+// retargeted to to. The writes' paths lead out of r's directory, where the
+// transaction is journaled.
+func (r *FileRepository) rewriteLinksIn(txn *fileTxn, d linkingDir, from, to ADRLink) error {
+	files, err := d.repo.listFiles()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil // a root without ADRs yet
+		}
+		return fmt.Errorf("reading directory %q: %w", d.repo.dir, err)
+	}
+	for _, f := range files {
+		file := filepath.Join(d.repo.dir, filepath.FromSlash(f.Name))
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("reading %q: %w", file, err)
+		}
+		source, target := d.link(f.Name, from.Number, from.Filename), d.link(f.Name, to.Number, to.Filename)
+		rewritten, n := RewriteADRLinks(string(content), source, target)
+		if n == 0 {
+			continue
+		}
+		rel, err := txnPath(r.dir, file)
+		if err != nil {
+			return err
+		}
+		txn.write(rel, rewritten)
+	}
+	return nil
+}
+
+// parse returns the record of the ADR with the given content, falling back to
+// number when its heading has none (see MetadataToADR), with the repository's
+// metadata fields.
+func (r *FileRepository) parse(content string, number int) (ADR, error) {
+	fields := r.metaFields
+	if fields == nil {
+		fields = builtinMetaFields
+	}
+	return MetadataToADR(extractMetadata(content, fields), number)
+}
+
+// link returns a link to ADR number, stored in target, as written in the ADR
+// stored in from.
+func (r *FileRepository) link(from string, number int, target string) ADRLink {
+	return r.naming.Link(number, RelativePath(from, target))
+}
+
+// Archive moves the ADR with the given number into the archive directory
+// (see Discovery), keeping its path below the ADR directory, so
+// "platform/0003-x.md" becomes "archive/platform/0003-x.md". Every inbound link
+// in the other ADRs is retargeted, as are the archived ADR's own relative
+// links, to ADRs and to other files such as images. All file changes are
+// applied as one journaled transaction.
+func (r *FileRepository) Archive(_ context.Context, number int) (*ADR, error) {
+	if _, err := r.Recover(); err != nil {
+		return nil, err
+	}
+
+	oldFile, err := r.FindFile(number)
+	if err != nil {
+		return nil, err
+	}
+	if r.discovery.archived(oldFile) {
+		return nil, fmt.Errorf("ADR %04d is already archived: %w", number, ErrConflict)
+	}
+	newFile := path.Join(r.discovery.archiveDir(), oldFile)
+	if _, err := os.Stat(filepath.Join(r.dir, filepath.FromSlash(newFile))); err == nil {
+		return nil, fmt.Errorf("file %q: %w", newFile, ErrConflict)
+	}
+
+	content, err := os.ReadFile(filepath.Join(r.dir, oldFile))
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", oldFile, err)
+	}
+	updated := moveLinks(string(content), oldFile, newFile)
+
+	record, err := r.parse(updated, number)
+	if err != nil {
+		return nil, err
+	}
+	record.Content = updated
+	record.Archived = true
+
+	var txn fileTxn
+	txn.rename(oldFile, newFile)
+	if err := r.rewriteInboundLinks(&txn, r.naming.Link(number, oldFile), r.naming.Link(number, newFile), oldFile); err != nil {
+		return nil, err
+	}
+	txn.write(newFile, updated)
+	if err := txn.commit(r.dir); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Renumbering describes one ADR file moved to a new number.
+type Renumbering struct {
+	From ADRLink
+	To   ADRLink
+}
+
+// Renumber moves the ADR with number oldNum to newNum. It fails when oldNum is
+// claimed by several files (use RenumberFile to pick one) or newNum is taken.
+func (r *FileRepository) Renumber(ctx context.Context, oldNum, newNum int) (*ADR, error) {
+	dups, err := r.DuplicateNumbers()
+	if err != nil {
+		return nil, err
+	}
+	if names, ok := dups[oldNum]; ok {
+		return nil, fmt.Errorf("ADR %04d is ambiguous (%s): renumber a file by name instead",
+			oldNum, strings.Join(names, ", "))
+	}
+	filename, err := r.FindFile(oldNum)
+	if err != nil {
+		return nil, err
+	}
+	return r.RenumberFile(ctx, filename, newNum)
+}
+
+// RenumberFile moves the ADR stored in filename to newNum: the file is renamed
+// to the new number (keeping its slug), its "# N." heading is updated, and
+// every link to it in the other ADRs is retargeted and relabelled. Links are
+// matched by filename, so this is safe even while another file shares the old
+// number. All changes are applied as one journaled transaction.
+func (r *FileRepository) RenumberFile(_ context.Context, filename string, newNum int) (*ADR, error) {
+	if newNum <= 0 {
+		return nil, fmt.Errorf("number must be positive: %w", ErrInvalidRecord)
+	}
+	if _, err := r.Recover(); err != nil {
+		return nil, err
+	}
+
+	oldNum, ok := r.FileNumber(filename)
+	if !ok {
+		return nil, fmt.Errorf("%q is not an ADR filename: %w", filename, ErrNotFound)
+	}
+
+	content, err := os.ReadFile(filepath.Join(r.dir, filename))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("file %q: %w", filename, ErrNotFound)
+		}
+		return nil, fmt.Errorf("reading %q: %w", filename, err)
+	}
+	if _, err := r.FindFile(newNum); err == nil {
+		return nil, fmt.Errorf("ADR %04d already exists: %w", newNum, ErrConflict)
+	}
+
+	newFile := r.naming.renumberedFilename(filename, newNum)
+	from := r.naming.Link(oldNum, filename)
+	to := r.naming.Link(newNum, newFile)
+
+	updated := string(content)
+	if meta := ExtractMetadata(updated); meta.Number > 0 {
+		updated, _ = ReplaceHeading(updated, newNum, meta.Title)
+	}
+	updated, _ = RewriteADRLinks(updated, r.link(filename, oldNum, filename), r.link(filename, newNum, newFile))
+
+	record, err := r.parse(updated, newNum)
+	if err != nil {
+		return nil, err
+	}
+	record.Content = updated
+
+	var txn fileTxn
+	txn.rename(filename, newFile)
+	if err := r.rewriteInboundLinks(&txn, from, to, filename); err != nil {
+		return nil, err
+	}
+	txn.write(newFile, updated)
+	if err := txn.commit(r.dir); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// PlanDuplicateFixes decides how to resolve duplicate ADR numbers: in each
+// group of files sharing a number the oldest keeps it and every newer file is
+// assigned the next free number (max+1, as NextNumber would). "Oldest" is by
+// the ADR's Date; undated files count as newest, and ties break by filename.
+// Nothing is written; apply each step with RenumberFile, in order.
+func (r *FileRepository) PlanDuplicateFixes(_ context.Context) ([]Renumbering, error) {
+	dups, err := r.DuplicateNumbers()
+	if err != nil {
+		return nil, err
+	}
+	if len(dups) == 0 {
+		return nil, nil
+	}
+	next, err := r.nextNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	numbers := make([]int, 0, len(dups))
+	for n := range dups {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+
+	var plan []Renumbering
+	for _, n := range numbers {
+		// Names arrive in filename order, so a stable sort by date keeps
+		// filename as the tiebreaker.
+		names := append([]string(nil), dups[n]...)
+		dates := make(map[string]time.Time, len(names))
+		for _, name := range names {
+			if content, err := os.ReadFile(filepath.Join(r.dir, name)); err == nil {
+				if rec, err := r.parse(string(content), n); err == nil {
+					dates[name] = rec.Date
+				}
+			}
+		}
+		sort.SliceStable(names, func(i, j int) bool {
+			a, b := dates[names[i]], dates[names[j]]
+			if a.IsZero() || b.IsZero() {
+				return !a.IsZero() && b.IsZero()
+			}
+			return a.Before(b)
+		})
+
+		for _, name := range names[1:] {
+			plan = append(plan, Renumbering{
+				From: ADRLink{Number: n, Filename: name},
+				To:   ADRLink{Number: next, Filename: r.naming.renumberedFilename(name, next)},
+			})
+			next++
+		}
+	}
+	return plan, nil
+}
+
+// Conversion is an ADR file rewritten into another template format (see
+// ConvertContent).
+type Conversion struct {
+	Filename string
+	Before   string
+	After    string
+}
+
+// Changed reports whether the conversion alters the file.
+func (c Conversion) Changed() bool {
+	return c.Before != c.After
+}
+
+// PlanConversions converts the ADRs with the given numbers, or every ADR when
+// numbers is empty, to tmpl. Nothing is written; apply the result with
+// ApplyConversions. Any ADR that fails to convert fails the whole plan.
+func (r *FileRepository) PlanConversions(_ context.Context, numbers []int, tmpl *ProjectTemplate) ([]Conversion, error) {
+	var files []adrFile
+	if len(numbers) == 0 {
+		all, err := r.listFiles()
+		if err != nil {
+			return nil, fmt.Errorf("reading directory %q: %w", r.dir, err)
+		}
+		files = all
+	}
+	for _, n := range numbers {
+		name, err := r.FindFile(n)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, adrFile{Number: n, Name: name})
+	}
+
+	plan := make([]Conversion, 0, len(files))
+	for _, f := range files {
+		content, err := os.ReadFile(filepath.Join(r.dir, f.Name))
+		if err != nil {
+			return nil, fmt.Errorf("reading %q: %w", f.Name, err)
+		}
+		converted, err := ConvertContent(string(content), f.Number, tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("converting %q: %w", f.Name, err)
+		}
+		if _, err := MetadataToADR(ExtractMetadata(converted), f.Number); err != nil {
+			return nil, fmt.Errorf("converting %q: %w", f.Name, err)
+		}
+		plan = append(plan, Conversion{Filename: f.Name, Before: string(content), After: converted})
+	}
+	return plan, nil
+}
+
+// ApplyConversions writes the changed files of plan as one journaled
+// transaction.
+func (r *FileRepository) ApplyConversions(_ context.Context, plan []Conversion) error {
+	if _, err := r.Recover(); err != nil {
+		return err
+	}
+	var txn fileTxn
+	for _, c := range plan {
+		if c.Changed() {
+			txn.write(c.Filename, c.After)
+		}
+	}
+	if len(txn.Writes) == 0 {
+		return nil
+	}
+	return txn.commit(r.dir)
+}
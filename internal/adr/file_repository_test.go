@@ -341,3 +341,194 @@ func writeFile(t *testing.T, dir, name, content string) {
 	err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644)
 	require.NoError(t, err)
 }
+
+func TestFileRepository_Rename_RenamesFileAndRewritesInboundLinks(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "0001-old-title.md", "# 1. Old Title\n\nDate: 2024-01-01\n\n## Status\n\nAccepted\n")
+	writeFile(t, dir, "0002-other.md", "# 2. Other\n\n## Status\n\nAccepted\n\nSupersedes [ADR-0001](0001-old-title.md)  \nSee also [the old one](./0001-old-title.md#context).\n")
+	writeFile(t, dir, "0003-unrelated.md", "# 3. Unrelated\n\n## Status\n\nAccepted\n")
+
+	repo := NewFileRepository(dir)
+	record, err := repo.Rename(context.Background(), 1, "New Title")
+	require.NoError(t, err)
+
+	assert.Equal(t, "New Title", record.Title)
+	assert.Contains(t, record.Content, "# 1. New Title")
+	_, err = os.Stat(filepath.Join(dir, "0001-old-title.md"))
+	assert.True(t, os.IsNotExist(err))
+	assert.Contains(t, readFile(t, dir, "0001-new-title.md"), "# 1. New Title")
+
+	other := readFile(t, dir, "0002-other.md")
+	assert.Contains(t, other, "Supersedes [ADR-0001](0001-new-title.md)")
+	assert.Contains(t, other, "[the old one](./0001-new-title.md#context)")
+	assert.NotContains(t, other, "0001-old-title.md")
+}
+
+func TestFileRepository_Rename_SameSlugOnlyUpdatesHeading(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "0001-use-go.md", "# 1. use go\n\n## Status\n\nAccepted\n")
+
+	repo := NewFileRepository(dir)
+	_, err := repo.Rename(context.Background(), 1, "Use Go")
+	require.NoError(t, err)
+
+	assert.Contains(t, readFile(t, dir, "0001-use-go.md"), "# 1. Use Go")
+}
+
+func TestFileRepository_Rename_Conflict(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "0001-a.md", "# 1. A\n\n## Status\n\nAccepted\n")
+	writeFile(t, dir, "0001-b.md", "# 1. B\n\n## Status\n\nAccepted\n")
+
+	repo := NewFileRepository(dir)
+	// os.ReadDir order is by filename, so ADR 1 resolves to 0001-a.md.
+	_, err := repo.Rename(context.Background(), 1, "B")
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.Contains(t, readFile(t, dir, "0001-a.md"), "# 1. A")
+}
+
+func TestFileRepository_UpdateContentAndRename(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "0001-a.md", "# 1. A\r\n\r\n## Status\r\n\r\nAccepted\r\n")
+	writeFile(t, dir, "0002-b.md", "# 2. B\n\n## Status\n\nAccepted\n\nRelates to [ADR-0001](0001-a.md)\n")
+
+	repo := NewFileRepository(dir)
+	record, err := repo.UpdateContentAndRename(context.Background(), 1, "# 1. C\n\n## Status\n\nProposed\n")
+	require.NoError(t, err)
+	assert.Equal(t, "C", record.Title)
+	assert.Equal(t, "# 1. C\r\n\r\n## Status\r\n\r\nProposed\r\n", readFile(t, dir, "0001-c.md"))
+	assert.Contains(t, readFile(t, dir, "0002-b.md"), "[ADR-0001](0001-c.md)")
+
+	// A rejected rename leaves the content untouched too.
+	writeFile(t, dir, "0001-d.md", "# 1. D\n")
+	_, err = repo.UpdateContentAndRename(context.Background(), 1, "# 1. D\n\n## Status\n\nAccepted\n")
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.Contains(t, readFile(t, dir, "0001-c.md"), "Proposed")
+}
+
+func TestFileRepository_Rename_EmptySlug(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "0001-a.md", "# 1. A\n\n## Status\n\nAccepted\n")
+
+	repo := NewFileRepository(dir)
+	_, err := repo.Rename(context.Background(), 1, "!!!")
+	assert.ErrorIs(t, err, ErrInvalidRecord)
+}
+
+func TestFileRepository_Rename_NotFound(t *testing.T) {
+	repo := NewFileRepository(t.TempDir())
+	_, err := repo.Rename(context.Background(), 7, "X")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestDuplicateNumbers(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "0001-a.md", "# 1. A\n")
+	writeFile(t, dir, "0002-b.md", "# 2. B\n")
+	writeFile(t, dir, "0002-c.md", "# 2. C\n")
+
+	dups, err := DuplicateNumbers(dir)
+	require.NoError(t, err)
+	assert.Equal(t, map[int][]string{2: {"0002-b.md", "0002-c.md"}}, dups)
+}
+
+func TestRenumberedFilename(t *testing.T) {
+	assert.Equal(t, "0014-use-kafka.md", Naming{}.renumberedFilename("0012-use-kafka.md", 14))
+	assert.Equal(t, "not-an-adr.md", Naming{}.renumberedFilename("not-an-adr.md", 3))
+	prefixed := Naming{Prefix: "API-", NumberWidth: 3}
+	assert.Equal(t, "API-014-use-kafka.md", prefixed.renumberedFilename("API-012-use-kafka.md", 14))
+	dated := Naming{Pattern: "{date}-{slug}.md"}
+	assert.Equal(t, "2024-05-01-use-kafka.md", dated.renumberedFilename("2024-05-01-use-kafka.md", 14))
+}
+
+func TestFileRepository_RenumberFile_MovesFileAndRewritesLinks(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "0002-b.md", "# 2. B\n\nDate: 2024-01-01\n\n## Status\n\nAccepted\n")
+	writeFile(t, dir, "0002-c.md", "# 2. C\n\nDate: 2024-02-01\n\n## Status\n\nAccepted\n")
+	writeFile(t, dir, "0003-d.md", "# 3. D\n\n## Status\n\nAccepted\n\nRelates to [ADR-0002](0002-c.md)  \nRelates to [ADR-0002](0002-b.md)  \n")
+
+	repo := NewFileRepository(dir)
+	record, err := repo.RenumberFile(context.Background(), "0002-c.md", 4)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, record.Number)
+	assert.Contains(t, readFile(t, dir, "0004-c.md"), "# 4. C")
+	_, err = os.Stat(filepath.Join(dir, "0002-c.md"))
+	assert.True(t, os.IsNotExist(err))
+
+	d := readFile(t, dir, "0003-d.md")
+	assert.Contains(t, d, "[ADR-0004](0004-c.md)")
+	assert.Contains(t, d, "[ADR-0002](0002-b.md)", "links to the other file sharing the number are untouched")
+	_, err = os.Stat(filepath.Join(dir, JournalFileName))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestFileRepository_RenumberFile_TargetTaken(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "0001-a.md", "# 1. A\n")
+	writeFile(t, dir, "0002-b.md", "# 2. B\n")
+
+	_, err := NewFileRepository(dir).RenumberFile(context.Background(), "0001-a.md", 2)
+	assert.ErrorIs(t, err, ErrConflict)
+}
+
+func TestFileRepository_Renumber_AmbiguousNumber(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "0002-b.md", "# 2. B\n")
+	writeFile(t, dir, "0002-c.md", "# 2. C\n")
+
+	_, err := NewFileRepository(dir).Renumber(context.Background(), 2, 5)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ambiguous")
+}
+
+func TestFileRepository_Renumber_ByNumber(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "0001-a.md", "# 1. A\n\n## Status\n\nAccepted\n")
+
+	record, err := NewFileRepository(dir).Renumber(context.Background(), 1, 7)
+	require.NoError(t, err)
+	assert.Equal(t, 7, record.Number)
+	assert.Contains(t, readFile(t, dir, "0007-a.md"), "# 7. A")
+}
+
+func TestFileRepository_PlanDuplicateFixes(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "0001-a.md", "# 1. A\n\nDate: 2024-01-01\n\n## Status\n\nAccepted\n")
+	// The newer ADR sorts first by name; the older one keeps the number.
+	writeFile(t, dir, "0002-aaa.md", "# 2. Newer\n\nDate: 2024-03-01\n\n## Status\n\nAccepted\n")
+	writeFile(t, dir, "0002-zzz.md", "# 2. Older\n\nDate: 2024-02-01\n\n## Status\n\nAccepted\n")
+	writeFile(t, dir, "0002-undated.md", "# 2. Undated\n\n## Status\n\nAccepted\n")
+
+	plan, err := NewFileRepository(dir).PlanDuplicateFixes(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []Renumbering{
+		{From: ADRLink{Number: 2, Filename: "0002-aaa.md"}, To: ADRLink{Number: 3, Filename: "0003-aaa.md"}},
+		{From: ADRLink{Number: 2, Filename: "0002-undated.md"}, To: ADRLink{Number: 4, Filename: "0004-undated.md"}},
+	}, plan)
+}
+
+func TestFileRepository_PlanDuplicateFixes_NoDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "0001-a.md", "# 1. A\n")
+
+	plan, err := NewFileRepository(dir).PlanDuplicateFixes(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, plan)
+}
+
+func TestFileRepository_List_MixedTemplateFormats(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "0001-small.md", "# 1. Small\n\nDate: 2024-01-01\n\nScope: API\n\n## Status\n\nAccepted\n")
+	writeFile(t, dir, "0002-big.md", "---\nstatus: proposed\ndate: 2024-02-01\ndecision-makers: Alice, Bob\n---\n\n# Big\n\n## Context and Problem Statement\n\nWhy.\n")
+
+	adrs, err := NewFileRepository(dir).List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, adrs, 2)
+
+	assert.Equal(t, Accepted, adrs[0].Status)
+	assert.Equal(t, []string{"API"}, adrs[0].Meta["scope"])
+	assert.Equal(t, "Big", adrs[1].Title)
+	assert.Equal(t, Proposed, adrs[1].Status)
+	assert.Equal(t, []string{"Alice", "Bob"}, adrs[1].Meta["decision-makers"])
+}
@@ -3,70 +3,342 @@ package adr
 import (
 	"fmt"
 	"os"
+	"path"
+	"path/filepath"
 	"regexp"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
-var adrFilePattern = regexp.MustCompile(`^(\d{4,})-.*\.md$`)
+const (
+	// DefaultFilenamePattern names ADR files "0001-use-go.md".
+	DefaultFilenamePattern = "{prefix}{number}-{slug}.md"
+	// DefaultNumberWidth is the number of digits ADR numbers are padded to.
+	DefaultNumberWidth = 4
+	// DefaultLinkLabel labels links to ADRs "ADR-0001".
+	DefaultLinkLabel = "ADR-{number}"
+)
+
+// placeholderPattern matches the "{name}" placeholders of filename patterns
+// and link labels.
+var placeholderPattern = regexp.MustCompile(`\{(\w*)\}`)
 
-// IsADRFilename reports whether name matches the ADR file naming convention
+// IsADRFilename reports whether name follows the default naming convention
 // (NNNN-*.md, four or more leading digits). name must be a bare filename, not a
 // path: passing a value that may contain '/' or '\' can yield a misleading result.
 func IsADRFilename(name string) bool {
-	return adrFilePattern.MatchString(name)
+	return Naming{}.Matches(name)
 }
 
-// adrFile is an ADR markdown file discovered in a directory.
-type adrFile struct {
-	Number int
-	Name   string
+// ADRFilenameNumber returns the number an ADR filename starts with, and false
+// when name does not follow the default naming convention (see IsADRFilename).
+func ADRFilenameNumber(name string) (int, bool) {
+	return Naming{}.FileNumber(name)
 }
 
-// listADRFiles returns the ADR files in dir (NNNN-*.md), parsed and in
-// os.ReadDir order. Directories, non-ADR names, and files whose number can't be
-// parsed are skipped. The raw os.ReadDir error is returned unwrapped so callers
-// can wrap it or check os.IsNotExist.
-//
-// It is deliberately a thin "matches convention + parses to a number" primitive:
-// keep all caller-specific filtering in the callers, not here.
-func listADRFiles(dir string) ([]adrFile, error) {
-	entries, err := os.ReadDir(dir)
-	if err != nil {
-		return nil, err
+// Naming is a project's convention for ADR filenames and the labels of links
+// between ADRs. The zero value is the default convention: files named
+// "0001-use-go.md" and linked as "[ADR-0001](0001-use-go.md)".
+type Naming struct {
+	// Pattern is the filename of an ADR with placeholders: {number}, the
+	// number zero-padded to NumberWidth digits; {prefix}; {slug}, the title's
+	// slug; and {date}, the ADR's date as YYYY-MM-DD. It must contain {slug}
+	// and end in ".md"; empty means DefaultFilenamePattern. Without {number},
+	// numbers are read from the ADRs' "# N. Title" headings.
+	Pattern string
+	// NumberWidth is the number of digits numbers are padded to; 0 means
+	// DefaultNumberWidth.
+	NumberWidth int
+	// Prefix is what {prefix} stands for, e.g. "ADR-" or "API-".
+	Prefix string
+	// LinkLabel is the label of links to an ADR, with the {number} and
+	// {prefix} placeholders. Empty means DefaultLinkLabel, or "{prefix}{number}"
+	// when Prefix is set.
+	LinkLabel string
+	// SlugMaxLength caps the slug; 0 means no limit.
+	SlugMaxLength int
+}
+
+// Naming returns the project's filename settings.
+func (c *Config) Naming() Naming {
+	return Naming{
+		Pattern:       c.FilenamePattern,
+		NumberWidth:   c.NumberWidth,
+		Prefix:        c.NumberPrefix,
+		LinkLabel:     c.LinkLabel,
+		SlugMaxLength: c.SlugMaxLength,
 	}
+}
 
-	var files []adrFile
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
+func (n Naming) pattern() string {
+	if n.Pattern == "" {
+		return DefaultFilenamePattern
+	}
+	return n.Pattern
+}
+
+func (n Naming) width() int {
+	if n.NumberWidth == 0 {
+		return DefaultNumberWidth
+	}
+	return n.NumberWidth
+}
+
+func (n Naming) label() string {
+	switch {
+	case n.LinkLabel != "":
+		return n.LinkLabel
+	case n.Prefix != "":
+		return "{prefix}{number}"
+	default:
+		return DefaultLinkLabel
+	}
+}
+
+// numbered reports whether filenames carry the ADR number.
+func (n Naming) numbered() bool {
+	return strings.Contains(n.pattern(), "{number}")
+}
+
+// validate reports settings that can't name files or label links.
+func (n Naming) validate() error {
+	if n.NumberWidth < 0 || n.NumberWidth > 9 {
+		return fmt.Errorf("numberWidth must be between 1 and 9, got %d", n.NumberWidth)
+	}
+	if strings.ContainsAny(n.Prefix, "/\\[]()\n") {
+		return fmt.Errorf("numberPrefix %q must not contain path separators, brackets or parentheses", n.Prefix)
+	}
+
+	pattern := n.pattern()
+	if strings.ContainsAny(pattern, "/\\") {
+		return fmt.Errorf("filenamePattern %q must not contain path separators", pattern)
+	}
+	if !strings.HasSuffix(pattern, ".md") {
+		return fmt.Errorf("filenamePattern %q must end in .md", pattern)
+	}
+	seen := make(map[string]bool)
+	for _, m := range placeholderPattern.FindAllStringSubmatch(pattern, -1) {
+		switch m[1] {
+		case "number", "prefix", "slug", "date":
+		default:
+			return fmt.Errorf("filenamePattern %q: unknown placeholder %s", pattern, m[0])
 		}
-		matches := adrFilePattern.FindStringSubmatch(entry.Name())
-		if matches == nil {
-			continue
+		if seen[m[1]] {
+			return fmt.Errorf("filenamePattern %q: placeholder %s appears twice", pattern, m[0])
 		}
-		n, err := strconv.Atoi(matches[1])
-		if err != nil {
-			continue
+		seen[m[1]] = true
+	}
+	if !seen["slug"] {
+		return fmt.Errorf("filenamePattern %q must contain {slug}", pattern)
+	}
+
+	label := n.label()
+	if strings.ContainsAny(label, "[]()\n") {
+		return fmt.Errorf("linkLabel %q must not contain brackets or parentheses", label)
+	}
+	for _, m := range placeholderPattern.FindAllStringSubmatch(label, -1) {
+		if m[1] != "number" && m[1] != "prefix" {
+			return fmt.Errorf("linkLabel %q: unknown placeholder %s", label, m[0])
 		}
-		files = append(files, adrFile{Number: n, Name: entry.Name()})
 	}
-	return files, nil
+	if !strings.Contains(label, "{number}") {
+		return fmt.Errorf("linkLabel %q must contain {number}", label)
+	}
+	return nil
 }
 
-// FormatFilename returns the ADR filename for the given number and title.
-func FormatFilename(number int, title string) (string, error) {
+// namingPatterns caches the compiled filename pattern of each Naming.
+var namingPatterns sync.Map
+
+// filePattern returns the regular expression filenames of n match, with the
+// groups "number" and "date" for those placeholders.
+func (n Naming) filePattern() *regexp.Regexp {
+	if re, ok := namingPatterns.Load(n); ok {
+		return re.(*regexp.Regexp)
+	}
+	pattern := n.pattern()
+	var b strings.Builder
+	b.WriteString("^")
+	last := 0
+	for _, loc := range placeholderPattern.FindAllStringSubmatchIndex(pattern, -1) {
+		b.WriteString(regexp.QuoteMeta(pattern[last:loc[0]]))
+		switch pattern[loc[2]:loc[3]] {
+		case "number":
+			fmt.Fprintf(&b, `(?P<number>\d{%d,})`, n.width())
+		case "prefix":
+			b.WriteString(regexp.QuoteMeta(n.Prefix))
+		case "slug":
+			b.WriteString(`.*`)
+		case "date":
+			b.WriteString(`(?P<date>\d{4}-\d{2}-\d{2})`)
+		}
+		last = loc[1]
+	}
+	b.WriteString(regexp.QuoteMeta(pattern[last:]) + "$")
+	re := regexp.MustCompile(b.String())
+	namingPatterns.Store(n, re)
+	return re
+}
+
+// Matches reports whether name, a bare filename, follows the naming.
+func (n Naming) Matches(name string) bool {
+	return n.filePattern().MatchString(name)
+}
+
+// FileNumber returns the number in filename name, and false when name does
+// not follow the naming or the naming's filenames carry no number.
+func (n Naming) FileNumber(name string) (int, bool) {
+	re := n.filePattern()
+	m := re.FindStringSubmatch(name)
+	i := re.SubexpIndex("number")
+	if m == nil || i < 0 {
+		return 0, false
+	}
+	number, err := strconv.Atoi(m[i])
+	return number, err == nil
+}
+
+// fileNumber returns the number of the ADR at name, a slash-separated path
+// relative to dir, read from its heading when the naming's filenames carry no
+// number. Paths outside dir are not read.
+func (n Naming) fileNumber(dir, name string) (int, bool) {
+	base := path.Base(name)
+	if n.numbered() || !n.Matches(base) {
+		return n.FileNumber(base)
+	}
+	if !filepath.IsLocal(filepath.FromSlash(name)) {
+		return 0, false
+	}
+	content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	if err != nil {
+		return 0, false
+	}
+	number := ExtractMetadata(string(content)).Number
+	return number, number > 0
+}
+
+// Filename returns the filename for a new ADR with the given number, title
+// and date: the naming's pattern filled in with the zero-padded number and
+// the title's slug (see Slugify). A zero date stands for today.
+func (n Naming) Filename(number int, title string, date time.Time) (string, error) {
 	slug, err := Slugify(title)
 	if err != nil {
 		return "", err
 	}
-	return fmt.Sprintf("%04d-%s.md", number, slug), nil
+	if date.IsZero() {
+		date = time.Now()
+	}
+	return n.format(number, truncateSlug(slug, n.SlugMaxLength), date.Format("2006-01-02")), nil
+}
+
+// format fills in the naming's pattern.
+func (n Naming) format(number int, slug, date string) string {
+	return placeholderPattern.ReplaceAllStringFunc(n.pattern(), func(p string) string {
+		switch p {
+		case "{number}":
+			return fmt.Sprintf("%0*d", n.width(), number)
+		case "{prefix}":
+			return n.Prefix
+		case "{slug}":
+			return slug
+		case "{date}":
+			return date
+		}
+		return p
+	})
 }
 
-// FindADRFile finds the ADR file with the given number in dir and returns its filename.
+// RenamedFilename returns the filename of the ADR stored as name once
+// retitled to title: the new title's slug, with the directory, number and
+// date name already has.
+func (n Naming) RenamedFilename(name string, number int, title string) (string, error) {
+	slug, err := Slugify(title)
+	if err != nil {
+		return "", err
+	}
+	dir, base := path.Split(name)
+	date := time.Now().Format("2006-01-02")
+	re := n.filePattern()
+	if m := re.FindStringSubmatch(base); m != nil && re.SubexpIndex("date") >= 0 {
+		date = m[re.SubexpIndex("date")]
+	}
+	return dir + n.format(number, truncateSlug(slug, n.SlugMaxLength), date), nil
+}
+
+// renumberedFilename returns name with its ADR number replaced by number,
+// keeping the rest as written (the slug may intentionally differ from what
+// Slugify would produce for the current title) and the directory. Names
+// without a number are returned as they are.
+func (n Naming) renumberedFilename(name string, number int) string {
+	dir, base := path.Split(name)
+	re := n.filePattern()
+	i := re.SubexpIndex("number")
+	loc := re.FindStringSubmatchIndex(base)
+	if loc == nil || i < 0 {
+		return name
+	}
+	return dir + base[:loc[2*i]] + fmt.Sprintf("%0*d", n.width(), number) + base[loc[2*i+1]:]
+}
+
+// Label returns the label of links to ADR number, e.g. "ADR-0012".
+func (n Naming) Label(number int) string {
+	return strings.NewReplacer(
+		"{number}", fmt.Sprintf("%0*d", n.width(), number),
+		"{prefix}", n.Prefix,
+	).Replace(n.label())
+}
+
+// Link returns a link to ADR number, stored in filename, with the naming's
+// label.
+func (n Naming) Link(number int, filename string) ADRLink {
+	return ADRLink{Number: number, Filename: filename, Label: n.Label(number)}
+}
+
+// FormatFilename returns the ADR filename for the given number and title with
+// the default naming.
+func FormatFilename(number int, title string) (string, error) {
+	return Naming{}.Filename(number, title, time.Time{})
+}
+
+// FindADRFile finds the ADR file with the given number in dir, named the
+// default way, and returns its filename.
 func FindADRFile(dir string, number int) (string, error) {
-	files, err := listADRFiles(dir)
+	return NewFileRepository(dir).FindFile(number)
+}
+
+// ADRFilenames returns the filename of every ADR in dir, named the default
+// way, by number. When files share a number the first by name wins, as with
+// FindADRFile.
+func ADRFilenames(dir string) (map[int]string, error) {
+	return NewFileRepository(dir).Filenames()
+}
+
+// NextNumber scans dir for ADR files named the default way and returns
+// max+1, or 1 if none found.
+func NextNumber(dir string) (int, error) {
+	return NewFileRepository(dir).nextNumber()
+}
+
+// DuplicateNumbers returns the ADR numbers claimed by more than one file in
+// dir, named the default way (see FileRepository.DuplicateNumbers).
+func DuplicateNumbers(dir string) (map[int][]string, error) {
+	return NewFileRepository(dir).DuplicateNumbers()
+}
+
+// listFiles returns the repository's ADR files (see listADRFiles).
+func (r *FileRepository) listFiles() ([]adrFile, error) {
+	return listADRFiles(r.dir, r.naming, r.discovery)
+}
+
+// FindFile finds the ADR file with the given number and returns its filename,
+// a slash-separated path relative to the directory for an ADR in a
+// subdirectory.
+func (r *FileRepository) FindFile(number int) (string, error) {
+	files, err := r.listFiles()
 	if err != nil {
-		return "", fmt.Errorf("reading directory %q: %w", dir, err)
+		return "", fmt.Errorf("reading directory %q: %w", r.dir, err)
 	}
 
 	for _, f := range files {
@@ -77,11 +349,36 @@ func FindADRFile(dir string, number int) (string, error) {
 	return "", fmt.Errorf("ADR %04d: %w", number, ErrNotFound)
 }
 
-// NextNumber scans dir for existing ADR files and returns max+1, or 1 if none found.
-func NextNumber(dir string) (int, error) {
-	files, err := listADRFiles(dir)
+// Filenames returns the filename of every ADR by number. When files share a
+// number the first by name wins, as with FindFile.
+func (r *FileRepository) Filenames() (map[int]string, error) {
+	files, err := r.listFiles()
+	if err != nil {
+		return nil, fmt.Errorf("reading directory %q: %w", r.dir, err)
+	}
+
+	names := make(map[int]string, len(files))
+	for _, f := range files {
+		if _, ok := names[f.Number]; !ok {
+			names[f.Number] = f.Name
+		}
+	}
+	return names, nil
+}
+
+// FileNumber returns the number of the ADR stored in name, a slash-separated
+// path relative to the directory, and false when name isn't an ADR file.
+// Under a naming without {number} the number is read from the file's heading.
+func (r *FileRepository) FileNumber(name string) (int, bool) {
+	return r.naming.fileNumber(r.dir, name)
+}
+
+// nextNumber returns max+1 over all the repository's ADR files, in every
+// discovered subdirectory, or 1 if none found.
+func (r *FileRepository) nextNumber() (int, error) {
+	files, err := r.listFiles()
 	if err != nil {
-		return 0, fmt.Errorf("reading directory %q: %w", dir, err)
+		return 0, fmt.Errorf("reading directory %q: %w", r.dir, err)
 	}
 
 	max := 0
@@ -92,3 +389,25 @@ func NextNumber(dir string) (int, error) {
 	}
 	return max + 1, nil
 }
+
+// DuplicateNumbers returns the ADR numbers claimed by more than one file (e.g.
+// after merging two branches that each ran `adr new`), each mapped to its
+// filenames in name order. The result is empty when every number is unique.
+func (r *FileRepository) DuplicateNumbers() (map[int][]string, error) {
+	files, err := r.listFiles()
+	if err != nil {
+		return nil, fmt.Errorf("reading directory %q: %w", r.dir, err)
+	}
+
+	byNumber := make(map[int][]string)
+	for _, f := range files {
+		byNumber[f.Number] = append(byNumber[f.Number], f.Name)
+	}
+	dups := make(map[int][]string)
+	for n, names := range byNumber {
+		if len(names) > 1 {
+			dups[n] = names
+		}
+	}
+	return dups, nil
+}
@@ -1,9 +1,12 @@
 package adr_test
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/BobMali/adr-helper/internal/adr"
 	"github.com/stretchr/testify/assert"
@@ -28,6 +31,142 @@ func TestFormatFilename_FourDigits(t *testing.T) {
 	assert.Equal(t, "1234-test.md", name)
 }
 
+func TestNaming_SlugMaxLength(t *testing.T) {
+	naming := adr.Naming{SlugMaxLength: 20}
+	tests := map[string]string{
+		"Use Go":                                  "0003-use-go.md",
+		"Use PostgreSQL for the reporting store":  "0003-use-postgresql-for.md",
+		"Use PostgreSQL-for the reporting store":  "0003-use-postgresql-for.md",
+		"Supercalifragilisticexpialidocious tool": "0003-supercalifragilistic.md",
+	}
+	for title, want := range tests {
+		name, err := naming.Filename(3, title, time.Time{})
+		require.NoError(t, err, title)
+		assert.Equal(t, want, name, title)
+	}
+
+	name, err := adr.Naming{}.Filename(3, "Use PostgreSQL for the reporting store", time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "0003-use-postgresql-for-the-reporting-store.md", name)
+}
+
+func TestNaming_Conventions(t *testing.T) {
+	date := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		naming   adr.Naming
+		filename string
+		label    string
+	}{
+		{"default", adr.Naming{}, "0012-use-go.md", "ADR-0012"},
+		{"prefixed", adr.Naming{Prefix: "ADR-"}, "ADR-0012-use-go.md", "ADR-0012"},
+		{"scope namespace", adr.Naming{Prefix: "API-", NumberWidth: 3}, "API-012-use-go.md", "API-012"},
+		{"custom label", adr.Naming{LinkLabel: "Decision {number}", NumberWidth: 2}, "12-use-go.md", "Decision 12"},
+		{"date prefixed", adr.Naming{Pattern: "{date}-{slug}.md"}, "2024-05-01-use-go.md", "ADR-0012"},
+		{"date and number", adr.Naming{Pattern: "{date}-{number}-{slug}.md"}, "2024-05-01-0012-use-go.md", "ADR-0012"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, err := tt.naming.Filename(12, "Use Go", date)
+			require.NoError(t, err)
+			assert.Equal(t, tt.filename, name)
+			assert.True(t, tt.naming.Matches(name))
+			assert.Equal(t, tt.label, tt.naming.Label(12))
+			assert.Equal(t, adr.ADRLink{Number: 12, Filename: name, Label: tt.label}, tt.naming.Link(12, name))
+		})
+	}
+
+	n, ok := adr.Naming{Prefix: "API-", NumberWidth: 3}.FileNumber("API-1234-x.md")
+	assert.True(t, ok)
+	assert.Equal(t, 1234, n)
+	assert.False(t, adr.Naming{Prefix: "API-", NumberWidth: 3}.Matches("0012-x.md"))
+	_, ok = adr.Naming{Pattern: "{date}-{slug}.md"}.FileNumber("2024-05-01-use-go.md")
+	assert.False(t, ok, "dated names carry no number")
+}
+
+func TestNaming_RenamedFilenameKeepsDate(t *testing.T) {
+	naming := adr.Naming{Pattern: "{date}-{slug}.md"}
+	name, err := naming.RenamedFilename("2023-01-31-use-go.md", 3, "Use Rust")
+	require.NoError(t, err)
+	assert.Equal(t, "2023-01-31-use-rust.md", name)
+
+	name, err = adr.Naming{}.RenamedFilename("0003-use-go.md", 3, "Use Rust")
+	require.NoError(t, err)
+	assert.Equal(t, "0003-use-rust.md", name)
+}
+
+func TestFileRepository_CustomNaming(t *testing.T) {
+	dir := t.TempDir()
+	naming := adr.Naming{Prefix: "API-", NumberWidth: 3}
+	for _, name := range []string{"API-001-a.md", "API-007-b.md", "0009-default.md", "README.md"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("# T\n"), 0o644))
+	}
+	repo := naming.Repository(dir)
+
+	name, err := repo.FindFile(7)
+	require.NoError(t, err)
+	assert.Equal(t, "API-007-b.md", name)
+	_, err = repo.FindFile(9)
+	assert.ErrorIs(t, err, adr.ErrNotFound)
+	next, err := repo.NextNumber(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 8, next)
+	names, err := repo.Filenames()
+	require.NoError(t, err)
+	assert.Equal(t, map[int]string{1: "API-001-a.md", 7: "API-007-b.md"}, names)
+}
+
+func TestFileRepository_DatedNamingReadsNumbersFromHeadings(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"2024-01-10-use-go.md":   "# 1. Use Go\n\n## Status\n\nAccepted\n",
+		"2024-03-02-use-chi.md":  "# 2. Use Chi\n\n## Status\n\nAccepted\n\nRelates to [ADR-0001](2024-01-10-use-go.md)  \n",
+		"2024-04-01-untitled.md": "No heading here.\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	repo := adr.Naming{Pattern: "{date}-{slug}.md"}.Repository(dir)
+
+	name, err := repo.FindFile(2)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-02-use-chi.md", name)
+	next, err := repo.NextNumber(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, next)
+
+	record, err := repo.Rename(context.Background(), 1, "Use Rust")
+	require.NoError(t, err)
+	assert.Equal(t, "Use Rust", record.Title)
+	data, err := os.ReadFile(filepath.Join(dir, "2024-03-02-use-chi.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Relates to [ADR-0001](2024-01-10-use-rust.md)")
+
+	_, err = repo.Renumber(context.Background(), 2, 5)
+	require.NoError(t, err)
+	data, err = os.ReadFile(filepath.Join(dir, "2024-03-02-use-chi.md"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "# 5. Use Chi\n"))
+	data, err = os.ReadFile(filepath.Join(dir, "2024-01-10-use-rust.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# 1. Use Rust")
+}
+
+func TestFindADRFile_ResolvesExistingSlugs(t *testing.T) {
+	dir := t.TempDir()
+	// Written before transliteration, and by hand with a Unicode slug.
+	for _, name := range []string{"0001-berarbeitung-der-authentifizierung.md", "0002-認証.md"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("# T\n"), 0o644))
+	}
+
+	name, err := adr.FindADRFile(dir, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "0001-berarbeitung-der-authentifizierung.md", name)
+	name, err = adr.FindADRFile(dir, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "0002-認証.md", name)
+}
+
 func TestNextNumber_EmptyDirectory(t *testing.T) {
 	dir := t.TempDir()
 
@@ -142,3 +281,23 @@ func TestIsADRFilename(t *testing.T) {
 		})
 	}
 }
+
+func TestADRFilenameNumber(t *testing.T) {
+	n, ok := adr.ADRFilenameNumber("0042-use-go.md")
+	assert.True(t, ok)
+	assert.Equal(t, 42, n)
+
+	_, ok = adr.ADRFilenameNumber("template.md")
+	assert.False(t, ok)
+}
+
+func TestADRFilenames(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"0001-first.md", "0002-b.md", "0002-a.md", "notes.md"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(""), 0o644))
+	}
+
+	names, err := adr.ADRFilenames(dir)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]string{1: "0001-first.md", 2: "0002-a.md"}, names)
+}
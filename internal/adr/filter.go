@@ -1,6 +1,7 @@
 package adr
 
 import (
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -70,6 +71,23 @@ func FilterByMetaField(records []ADR, key string, values []string, matchAll bool
 	return result
 }
 
+// MetaValues returns the distinct values of a metadata field across records,
+// compared case-insensitively (the first spelling wins), sorted.
+func MetaValues(records []ADR, key string) []string {
+	seen := make(map[string]bool)
+	var values []string
+	for _, r := range records {
+		for _, v := range r.Meta[key] {
+			if lower := strings.ToLower(v); !seen[lower] {
+				seen[lower] = true
+				values = append(values, v)
+			}
+		}
+	}
+	sort.Slice(values, func(i, j int) bool { return strings.ToLower(values[i]) < strings.ToLower(values[j]) })
+	return values
+}
+
 // metaMatches reports whether the present value set satisfies the wanted values under
 // the given mode. Any: at least one wanted value present. All: every wanted value present.
 func metaMatches(present map[string]bool, wanted []string, matchAll bool) bool {
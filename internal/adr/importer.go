@@ -0,0 +1,522 @@
+package adr
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Tools whose ADRs Import can adopt.
+const (
+	ImportADRTools   = "adr-tools"
+	ImportLog4brains = "log4brains"
+)
+
+// ImportSources returns the tool names accepted by Import.
+func ImportSources() []string {
+	return []string{ImportADRTools, ImportLog4brains}
+}
+
+// ImportedFile is one ADR taken over by Import.
+type ImportedFile struct {
+	// From is the original filename, To the NNNN-slug.md one.
+	From string
+	To   string
+}
+
+// ImportResult describes a completed import.
+type ImportResult struct {
+	// Config is the configuration for the imported directory. It is not
+	// saved; see Import.
+	Config   *Config
+	Files    []ImportedFile
+	Warnings []string
+}
+
+var (
+	adrToolsFilePattern     = regexp.MustCompile(`^(\d+)-.*\.md$`)
+	log4brainsDatePrefix    = regexp.MustCompile(`^(\d{4})(\d{2})(\d{2})-`)
+	log4brainsFolderPattern = regexp.MustCompile(`(?m)^\s*adrFolder:\s*(.+?)\s*$`)
+	log4brainsMetaPattern   = regexp.MustCompile(`^[-*]\s+([A-Za-z][A-Za-z ]*):\s*(.*)$`)
+	// localMDLinkPattern matches a markdown link to a .md file in the same
+	// directory: label, "./" prefix, filename and fragment.
+	localMDLinkPattern = regexp.MustCompile(`\[([^\]]*)\]\((\./)?([^)\s#/]+\.md)(#[^)\s]*)?\)`)
+	// linkLinePattern matches a link line such as "Amended by [3. Foo](0003-foo.md)"
+	// or "- Refines [Foo](20200101-foo.md)", capturing the verb and filename.
+	linkLinePattern    = regexp.MustCompile(`^(?:[-*]\s+)?(.*?)\s*\[[^\]]*\]\((?:\./)?([^)\s#/]+\.md)(?:#[^)\s]*)?\)\s*$`)
+	bracketPlaceholder = regexp.MustCompile(`^\[[^\]]*\]$`)
+)
+
+// importRecord is an ADR read from another tool.
+type importRecord struct {
+	file    string
+	title   string
+	date    string
+	content string // normalized; written back in style
+	style   lineStyle
+	link    ADRLink
+}
+
+// relationLine is a link to another ADR that becomes a "Relates to" line.
+type relationLine struct {
+	verb string
+	link ADRLink
+}
+
+// Import adopts the ADRs of an adr-tools or log4brains project rooted at root,
+// in place. Files are renamed to the NNNN-slug.md convention (log4brains
+// records are numbered in date order), headings become "# N. Title", and
+// status and link syntax is rewritten into this tool's: a "## Status" section
+// for adr-tools and frontmatter "status:" for log4brains, with "Supersedes"
+// and "Superseded by" links, and other links between ADRs ("Amended by",
+// log4brains' "## Links", …) moved to a "## Relations" section. Other links to
+// renamed files are retargeted. A copy of the matching built-in template
+// (nygard or madr-full) is added.
+//
+// Everything is applied as one journaled transaction. The returned Config is
+// not saved: the caller writes it with SaveConfig.
+func Import(root, from string) (*ImportResult, error) {
+	var dir, template string
+	var err error
+	switch from {
+	case ImportADRTools:
+		template = string(TemplateNygard)
+		dir, err = adrToolsDirectory(root)
+	case ImportLog4brains:
+		template = string(TemplateMADRFull)
+		dir, err = log4brainsDirectory(root)
+	default:
+		return nil, fmt.Errorf("unknown import source %q, valid sources: %v", from, ImportSources())
+	}
+	if err != nil {
+		return nil, err
+	}
+	absDir := filepath.Join(root, dir)
+
+	var records []*importRecord
+	if from == ImportADRTools {
+		records, err = readADRToolsRecords(absDir)
+	} else {
+		records, err = readLog4brainsRecords(absDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Links may name a file by its old or new name.
+	byName := make(map[string]ADRLink)
+	newNames := make(map[string]string)
+	for _, rec := range records {
+		name, err := FormatFilename(rec.link.Number, rec.title)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v: %w", rec.file, err, ErrInvalidRecord)
+		}
+		if other, ok := newNames[name]; ok {
+			return nil, fmt.Errorf("%s and %s would both become %s: %w", other, rec.file, name, ErrConflict)
+		}
+		if name != rec.file {
+			if _, err := os.Stat(filepath.Join(absDir, name)); err == nil {
+				return nil, fmt.Errorf("cannot rename %s: %s already exists: %w", rec.file, name, ErrConflict)
+			}
+		}
+		newNames[name] = rec.file
+		rec.link.Filename = name
+		byName[rec.file] = rec.link
+		byName[name] = rec.link
+	}
+
+	templateFile := "template.md"
+	if _, err := os.Stat(filepath.Join(absDir, templateFile)); err == nil {
+		// log4brains keeps its own template there.
+		templateFile = "adr-template.md"
+		if _, err := os.Stat(filepath.Join(absDir, templateFile)); err == nil {
+			return nil, fmt.Errorf("template files %q and %q already exist: %w", "template.md", templateFile, ErrConflict)
+		}
+	}
+	templateContent, err := TemplateContent(template)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{
+		Config: &Config{Directory: filepath.ToSlash(dir), Template: template, TemplateFile: templateFile},
+	}
+	var txn fileTxn
+	for _, rec := range records {
+		var content string
+		var warnings []string
+		if from == ImportADRTools {
+			content, warnings = rewriteADRToolsRecord(rec, byName)
+		} else {
+			content, warnings = rewriteLog4brainsRecord(rec, byName)
+		}
+		if _, err := MetadataToADR(ExtractMetadata(content), rec.link.Number); err != nil {
+			return nil, fmt.Errorf("%s: %w", rec.file, err)
+		}
+		for _, w := range warnings {
+			result.Warnings = append(result.Warnings, rec.file+": "+w)
+		}
+		if rec.link.Filename != rec.file {
+			txn.rename(rec.file, rec.link.Filename)
+		}
+		txn.write(rec.link.Filename, rec.style.restore(content))
+		result.Files = append(result.Files, ImportedFile{From: rec.file, To: rec.link.Filename})
+	}
+	txn.write(templateFile, templateContent)
+	if err := txn.commit(absDir); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// adrToolsDirectory reads the ADR directory from adr-tools' .adr-dir file,
+// defaulting to doc/adr as adr-tools does.
+func adrToolsDirectory(root string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(root, ".adr-dir"))
+	if dir := strings.TrimSpace(string(data)); err == nil && dir != "" {
+		return projectRelative(root, ".adr-dir", dir)
+	}
+	return "doc/adr", nil
+}
+
+// log4brainsDirectory reads project.adrFolder from .log4brains.yml, defaulting
+// to docs/adr.
+func log4brainsDirectory(root string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(root, ".log4brains.yml"))
+	if err == nil {
+		if m := log4brainsFolderPattern.FindSubmatch(data); m != nil {
+			if dir := strings.Trim(string(m[1]), `"'`); dir != "" {
+				return projectRelative(root, ".log4brains.yml", dir)
+			}
+		}
+	}
+	return "docs/adr", nil
+}
+
+// projectRelative returns dir, named in source, relative to root. A directory
+// outside root is rejected: Import rewrites files in place and must not touch
+// another project.
+func projectRelative(root, source, dir string) (string, error) {
+	rel := filepath.Clean(dir)
+	if filepath.IsAbs(dir) {
+		var err error
+		if rel, err = filepath.Rel(root, dir); err != nil {
+			rel = dir
+		}
+	}
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("%s: ADR directory %q is outside the project: %w", source, dir, ErrConfigInvalid)
+	}
+	return rel, nil
+}
+
+func readADRToolsRecords(dir string) ([]*importRecord, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading directory %q: %w", dir, err)
+	}
+	var records []*importRecord
+	for _, e := range entries {
+		m := adrToolsFilePattern.FindStringSubmatch(e.Name())
+		if e.IsDir() || m == nil {
+			continue
+		}
+		number, _ := strconv.Atoi(m[1])
+		content, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading %q: %w", e.Name(), err)
+		}
+		text, style := normalizeText(string(content))
+		meta := ExtractMetadata(text)
+		if meta.Title == "" {
+			return nil, fmt.Errorf("%s has no title heading: %w", e.Name(), ErrInvalidRecord)
+		}
+		records = append(records, &importRecord{
+			file: e.Name(), title: meta.Title, date: meta.Date,
+			content: text, style: style, link: ADRLink{Number: number},
+		})
+	}
+	return records, nil
+}
+
+func readLog4brainsRecords(dir string) ([]*importRecord, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading directory %q: %w", dir, err)
+	}
+	var records []*importRecord
+	for _, e := range entries {
+		name := e.Name()
+		switch strings.ToLower(name) {
+		case "template.md", "index.md", "readme.md":
+			continue
+		}
+		if e.IsDir() || filepath.Ext(name) != ".md" {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("reading %q: %w", name, err)
+		}
+		rec := &importRecord{file: name}
+		rec.content, rec.style = normalizeText(string(content))
+		if h, ok := titleHeading(rec.content); ok {
+			rec.title = h.Text
+		}
+		if rec.title == "" {
+			return nil, fmt.Errorf("%s has no title heading: %w", name, ErrInvalidRecord)
+		}
+		fields, _ := log4brainsFields(rec.content)
+		rec.date = fields["date"]
+		if rec.date == "" {
+			if m := log4brainsDatePrefix.FindStringSubmatch(name); m != nil {
+				rec.date = m[1] + "-" + m[2] + "-" + m[3]
+			}
+		}
+		records = append(records, rec)
+	}
+
+	// Undated records go last; ties break by filename.
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if (a.date == "") != (b.date == "") {
+			return a.date != ""
+		}
+		if a.date != b.date {
+			return a.date < b.date
+		}
+		return a.file < b.file
+	})
+	for i, rec := range records {
+		rec.link.Number = i + 1
+	}
+	return records, nil
+}
+
+// log4brainsFields returns a log4brains ADR's metadata, keyed by lowercase
+// name, from its frontmatter and "- Status: …" lines under the title, and the
+// order the keys were found in. Unfilled "[…]" placeholders are skipped.
+func log4brainsFields(content string) (map[string]string, []string) {
+	fields := make(map[string]string)
+	var order []string
+	add := func(key, value string) {
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(htmlCommentPattern.ReplaceAllString(value, ""))
+		value = strings.TrimSpace(stripQuotes(value))
+		if value == "" || bracketPlaceholder.MatchString(value) {
+			return
+		}
+		if _, ok := fields[key]; !ok {
+			order = append(order, key)
+		}
+		fields[key] = value
+	}
+
+	fm, _ := frontmatterLines(content)
+	for _, line := range fm {
+		if m := frontmatterKeyLinePattern.FindStringSubmatch(line); m != nil {
+			add(m[1], m[2])
+		}
+	}
+	preamble, _ := splitDocument(bodyAfterFrontmatter(content))
+	for _, line := range strings.Split(preamble, "\n") {
+		if m := log4brainsMetaPattern.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			add(m[1], m[2])
+		}
+	}
+	return fields, order
+}
+
+// rewriteADRToolsRecord rewrites an adr-tools ADR: its Status section's link
+// lines become Supersedes/Superseded by links or relations.
+func rewriteADRToolsRecord(rec *importRecord, byName map[string]ADRLink) (string, []string) {
+	content, _ := ReplaceHeading(rec.content, rec.link.Number, rec.title)
+	content = retargetLocalLinks(content, byName)
+	if !hasStatusSection(content) {
+		return content, nil
+	}
+
+	var warnings []string
+	var status string
+	var supersededBy *ADRLink
+	var supersedes []ADRLink
+	var relations []relationLine
+	var extra []string
+	for _, line := range strings.Split(extractStatusSectionContent(content), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if m := linkLinePattern.FindStringSubmatch(trimmed); m != nil && m[1] != "" {
+			if link, ok := byName[m[2]]; ok {
+				switch strings.ToLower(m[1]) {
+				case "supersedes":
+					supersedes = append(supersedes, link)
+				case "superseded by":
+					supersededBy = &link
+				default:
+					relations = append(relations, relationLine{verb: m[1], link: link})
+				}
+				continue
+			}
+		}
+		if status == "" {
+			status = trimmed
+			continue
+		}
+		extra = append(extra, trimmed)
+	}
+
+	switch {
+	case supersededBy != nil:
+		status = "Superseded by " + formatADRLink(*supersededBy) + "  "
+	case status == "":
+		status = Proposed.String()
+	default:
+		if _, ok := ParseStatus(status); !ok {
+			warnings = append(warnings, fmt.Sprintf("unknown status %q imported as %s", status, Proposed))
+			extra = append([]string{"Imported status: " + status}, extra...)
+			status = Proposed.String()
+		}
+	}
+	text := status
+	if len(supersedes) > 0 {
+		lines := make([]string, len(supersedes))
+		for i, link := range supersedes {
+			lines[i] = "Supersedes " + formatADRLink(link) + "  "
+		}
+		text += "\n\n" + strings.Join(lines, "\n")
+	}
+	if len(extra) > 0 {
+		text += "\n\n" + strings.Join(extra, "\n\n")
+	}
+	content = replaceStatusSectionContent(content, text)
+	return addRelationLines(content, relations), warnings
+}
+
+// rewriteLog4brainsRecord rewrites a log4brains ADR into madr-full shape: its
+// metadata goes to frontmatter (Deciders as decision-makers) and the links of
+// its "## Links" section to ADRs become relations.
+func rewriteLog4brainsRecord(rec *importRecord, byName map[string]ADRLink) (string, []string) {
+	var warnings []string
+	fields, order := log4brainsFields(rec.content)
+
+	status := strings.ToLower(Proposed.String())
+	if raw := fields["status"]; raw != "" {
+		m := linkLinePattern.FindStringSubmatch(raw)
+		link, linked := ADRLink{}, false
+		if m != nil {
+			link, linked = byName[m[2]]
+		}
+		switch st, ok := ParseStatus(raw); {
+		case linked && strings.EqualFold(m[1], "superseded by"):
+			status = "superseded by " + formatADRLink(link) + "  "
+		case ok:
+			status = strings.ToLower(st.String())
+		case strings.EqualFold(raw, "draft"):
+			warnings = append(warnings, "draft status imported as proposed")
+		default:
+			warnings = append(warnings, fmt.Sprintf("unknown status %q imported as proposed", raw))
+		}
+	}
+
+	fm := []string{`status: "` + status + `"`}
+	if rec.date != "" {
+		fm = append(fm, "date: "+rec.date)
+	}
+	for _, key := range order {
+		switch key {
+		case "status", "date":
+			continue
+		case "deciders":
+			fm = append(fm, "decision-makers: "+fields[key])
+		default:
+			fm = append(fm, strings.ReplaceAll(key, " ", "-")+": "+fields[key])
+		}
+	}
+
+	preamble, sections := splitDocument(bodyAfterFrontmatter(rec.content))
+	if retitled, ok := ReplaceHeading(preamble, rec.link.Number, rec.title); ok {
+		preamble = retitled
+	}
+	var kept []string
+	for _, line := range strings.Split(preamble, "\n") {
+		if log4brainsMetaPattern.MatchString(strings.TrimSpace(line)) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	preamble = strings.TrimSpace(strings.Join(kept, "\n"))
+
+	var relations []relationLine
+	out := sections[:0]
+	for _, s := range sections {
+		if s.level == 2 && strings.EqualFold(s.heading, "Links") {
+			var rest []string
+			for _, line := range strings.Split(s.body, "\n") {
+				if m := linkLinePattern.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+					if link, ok := byName[m[2]]; ok {
+						relations = append(relations, relationLine{verb: m[1], link: link})
+						continue
+					}
+				}
+				rest = append(rest, line)
+			}
+			if s.body = strings.TrimSpace(strings.Join(rest, "\n")); s.body == "" || isPlaceholderText(s.body) {
+				continue
+			}
+		}
+		out = append(out, s)
+	}
+
+	content := retargetLocalLinks(joinDocument(fm, preamble, out), byName)
+	return addRelationLines(content, relations), warnings
+}
+
+// retargetLocalLinks points links to imported ADRs at their new filenames,
+// keeping labels, "./" prefixes and fragments.
+func retargetLocalLinks(content string, byName map[string]ADRLink) string {
+	return localMDLinkPattern.ReplaceAllStringFunc(content, func(match string) string {
+		m := localMDLinkPattern.FindStringSubmatch(match)
+		link, ok := byName[m[3]]
+		if !ok {
+			return match
+		}
+		return "[" + m[1] + "](" + m[2] + link.Filename + m[4] + ")"
+	})
+}
+
+// addRelationLines adds a "Relates to" line per relation to the Relations
+// section, noting the original link verb, e.g. "(amended by)".
+func addRelationLines(content string, relations []relationLine) string {
+	seen := make(map[ADRLink]bool)
+	var lines []string
+	for _, r := range relations {
+		if seen[r.link] {
+			continue
+		}
+		seen[r.link] = true
+		line := "Relates to " + formatADRLink(r.link)
+		if verb := strings.ToLower(strings.TrimSpace(r.verb)); verb != "" && verb != "relates to" && verb != "related to" {
+			line += " (" + verb + ")"
+		}
+		lines = append(lines, line+"  ")
+	}
+	if len(lines) == 0 {
+		return content
+	}
+	if hasRelationsSection(content) {
+		for _, line := range lines {
+			content = appendToRelationsSection(content, line)
+		}
+		return content
+	}
+	if updated, err := insertRelationsSection(content, strings.Join(lines, "\n")); err == nil {
+		return updated
+	}
+	return strings.TrimRight(content, "\n") + "\n\n## Relations\n\n" + strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,184 @@
+package adr_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestImport_ADRTools(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		".adr-dir": "architecture/decisions\n",
+		"architecture/decisions/0001-record-architecture-decisions.md": "# 1. Record architecture decisions\n\nDate: 2020-01-01\n\n## Status\n\nAccepted\n\n## Context\n\nWe need to record decisions.\n\n## Decision\n\nUse ADRs.\n\n## Consequences\n\nSee [2. Use MySQL](0002-use-mysql.md).\n",
+		"architecture/decisions/0002-use-mysql.md":                     "# 2. Use MySQL\n\nDate: 2020-02-01\n\n## Status\n\nAccepted\n\nSuperseded by [3. Use PostgreSQL!](0003-use-postgresql.md)\n\nAmended by [4. Tune pool](0004-tune-pool.md)\n\n## Context\n\nC.\n\n## Decision\n\nD.\n\n## Consequences\n\nE.\n",
+		"architecture/decisions/0003-use-postgresql.md":                "# 3. Use PostgreSQL!\n\nDate: 2020-03-01\n\n## Status\n\nAccepted\n\nSupersedes [2. Use MySQL](0002-use-mysql.md)\n\n## Context\n\nC.\n\n## Decision\n\nD.\n\n## Consequences\n\nE.\n",
+		"architecture/decisions/0004-tune-pool.md":                     "# 4. Tune pool\n\nDate: 2020-04-01\n\n## Status\n\nPending review\n\nAmends [2. Use MySQL](0002-use-mysql.md)\n\n## Context\n\nC.\n\n## Decision\n\nD.\n\n## Consequences\n\nE.\n",
+	})
+
+	result, err := adr.Import(root, adr.ImportADRTools)
+	require.NoError(t, err)
+
+	assert.Equal(t, "architecture/decisions", result.Config.Directory)
+	assert.Equal(t, "nygard", result.Config.Template)
+	assert.Equal(t, "template.md", result.Config.TemplateFile)
+	assert.Contains(t, result.Files, adr.ImportedFile{From: "0003-use-postgresql.md", To: "0003-use-postgresql.md"})
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], `0004-tune-pool.md: unknown status "Pending review"`)
+
+	dir := filepath.Join(root, "architecture/decisions")
+	assert.FileExists(t, filepath.Join(dir, "template.md"))
+
+	mysql := readFile(t, filepath.Join(dir, "0002-use-mysql.md"))
+	assert.Contains(t, mysql, "## Status\n\nSuperseded by [ADR-0003](0003-use-postgresql.md)  \n\n## Relations\n\nRelates to [ADR-0004](0004-tune-pool.md) (amended by)  \n\n## Context")
+	postgres := readFile(t, filepath.Join(dir, "0003-use-postgresql.md"))
+	assert.Contains(t, postgres, "## Status\n\nAccepted\n\nSupersedes [ADR-0002](0002-use-mysql.md)  \n\n## Context")
+	pool := readFile(t, filepath.Join(dir, "0004-tune-pool.md"))
+	assert.Contains(t, pool, "## Status\n\nProposed\n\nImported status: Pending review\n\n## Relations\n\nRelates to [ADR-0002](0002-use-mysql.md) (amends)  ")
+
+	repo := adr.NewFileRepository(dir)
+	got, err := repo.Get(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, adr.Superseded, got.Status)
+	got, err = repo.Get(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, adr.Accepted, got.Status)
+}
+
+func TestImport_ADRToolsDefaultDirectoryAndRename(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"doc/adr/1-old-name.md": "# 1. Use Go\n\nDate: 2020-01-01\n\n## Status\n\nAccepted\n\n## Context\n\nC.\n",
+		"doc/adr/0002-other.md": "# 2. Other\n\nDate: 2020-01-02\n\n## Status\n\nAccepted\n\n## Context\n\nBuilds on [Use Go](1-old-name.md#context).\n",
+	})
+
+	result, err := adr.Import(root, adr.ImportADRTools)
+	require.NoError(t, err)
+
+	assert.Equal(t, "doc/adr", result.Config.Directory)
+	assert.Contains(t, result.Files, adr.ImportedFile{From: "1-old-name.md", To: "0001-use-go.md"})
+	dir := filepath.Join(root, "doc/adr")
+	assert.NoFileExists(t, filepath.Join(dir, "1-old-name.md"))
+	assert.FileExists(t, filepath.Join(dir, "0001-use-go.md"))
+	assert.Contains(t, readFile(t, filepath.Join(dir, "0002-other.md")), "Builds on [Use Go](0001-use-go.md#context).")
+}
+
+func TestImport_Log4brains(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		".log4brains.yml":                         "---\nproject:\n  name: demo\n  adrFolder: ./docs/decisions\n",
+		"docs/decisions/template.md":              "# [short title]\n\n- Status: [draft | proposed]\n",
+		"docs/decisions/index.md":                 "# Decisions\n",
+		"docs/decisions/20210301-use-kafka.md":    "# Use Kafka\n\n- Status: accepted\n- Date: 2021-03-01\n- Deciders: Ana, Bo\n- Tags: backend\n\n## Context and Problem Statement\n\nEvents.\n\n## Decision Outcome\n\nKafka, replacing [RabbitMQ](20200115-use-rabbitmq.md).\n\n## Links\n\n- Supersedes [Use RabbitMQ](20200115-use-rabbitmq.md)\n- [Upstream docs](https://kafka.apache.org)\n",
+		"docs/decisions/20200115-use-rabbitmq.md": "# Use RabbitMQ\n\n- Status: superseded by [20210301-use-kafka](20210301-use-kafka.md)\n- Date: 2020-01-15\n\n## Context and Problem Statement\n\nQueues.\n\n## Links\n\n- Relates to [Use Kafka](20210301-use-kafka.md)\n",
+		"docs/decisions/20220101-try-nats.md":     "---\nstatus: draft\ndate: 2022-01-01\n---\n\n# Try NATS\n\n## Context and Problem Statement\n\nLighter.\n",
+	})
+
+	result, err := adr.Import(root, adr.ImportLog4brains)
+	require.NoError(t, err)
+
+	assert.Equal(t, "docs/decisions", result.Config.Directory)
+	assert.Equal(t, "madr-full", result.Config.Template)
+	assert.Equal(t, "adr-template.md", result.Config.TemplateFile)
+	assert.Equal(t, []adr.ImportedFile{
+		{From: "20200115-use-rabbitmq.md", To: "0001-use-rabbitmq.md"},
+		{From: "20210301-use-kafka.md", To: "0002-use-kafka.md"},
+		{From: "20220101-try-nats.md", To: "0003-try-nats.md"},
+	}, result.Files)
+	assert.Equal(t, []string{"20220101-try-nats.md: draft status imported as proposed"}, result.Warnings)
+
+	dir := filepath.Join(root, "docs/decisions")
+	assert.Equal(t, "# [short title]\n\n- Status: [draft | proposed]\n", readFile(t, filepath.Join(dir, "template.md")))
+	assert.FileExists(t, filepath.Join(dir, "adr-template.md"))
+
+	kafka := readFile(t, filepath.Join(dir, "0002-use-kafka.md"))
+	assert.Equal(t, "---\nstatus: \"accepted\"\ndate: 2021-03-01\ndecision-makers: Ana, Bo\ntags: backend\n---\n\n# 2. Use Kafka\n\n"+
+		"## Relations\n\nRelates to [ADR-0001](0001-use-rabbitmq.md) (supersedes)  \n\n"+
+		"## Context and Problem Statement\n\nEvents.\n\n"+
+		"## Decision Outcome\n\nKafka, replacing [RabbitMQ](0001-use-rabbitmq.md).\n\n"+
+		"## Links\n\n- [Upstream docs](https://kafka.apache.org)\n", kafka)
+
+	rabbit := readFile(t, filepath.Join(dir, "0001-use-rabbitmq.md"))
+	assert.Contains(t, rabbit, "status: \"superseded by [ADR-0002](0002-use-kafka.md)  \"")
+	assert.Contains(t, rabbit, "## Relations\n\nRelates to [ADR-0002](0002-use-kafka.md)  ")
+	assert.NotContains(t, rabbit, "## Links")
+
+	repo := adr.NewFileRepository(dir)
+	got, err := repo.Get(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, adr.Superseded, got.Status)
+	got, err = repo.Get(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, adr.Proposed, got.Status)
+	assert.Equal(t, "Try NATS", got.Title)
+}
+
+func TestImport_KeepsCRLFFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		".adr-dir":          "doc/adr\n",
+		"doc/adr/0001-a.md": "\ufeff# 1. A\r\n\r\nDate: 2020-01-01\r\n\r\n## Status\r\n\r\nAccepted\r\n\r\nAmended by [2. B](0002-b.md)\r\n",
+		"doc/adr/0002-b.md": "# 2. B\r\n\r\nDate: 2020-02-01\r\n\r\n## Status\r\n\r\nAccepted\r\n",
+	})
+
+	_, err := adr.Import(root, adr.ImportADRTools)
+	require.NoError(t, err)
+
+	a := readFile(t, filepath.Join(root, "doc/adr/0001-a.md"))
+	assert.Equal(t, "\ufeff# 1. A\r\n\r\nDate: 2020-01-01\r\n\r\n## Status\r\n\r\nAccepted\r\n\r\n"+
+		"## Relations\r\n\r\nRelates to [ADR-0002](0002-b.md) (amended by)  \r\n", a)
+}
+
+func TestImport_NameCollision(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"doc/adr/0001-a.md": "# 1. Same\n\nDate: 2020-01-01\n\n## Status\n\nAccepted\n",
+		"doc/adr/1-b.md":    "# 1. Same\n\nDate: 2020-01-01\n\n## Status\n\nAccepted\n",
+	})
+
+	_, err := adr.Import(root, adr.ImportADRTools)
+	require.ErrorIs(t, err, adr.ErrConflict)
+	assert.FileExists(t, filepath.Join(root, "doc/adr/1-b.md"))
+}
+
+func TestImport_DirectoryOutsideProject(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "project")
+	writeFiles(t, parent, map[string]string{
+		"elsewhere/0001-a.md":     "# 1. A\n\nDate: 2020-01-01\n\n## Status\n\nAccepted\n",
+		"project/.adr-dir":        "../elsewhere\n",
+		"project/.log4brains.yml": "project:\n  adrFolder: " + filepath.Join(parent, "elsewhere") + "\n",
+	})
+
+	for _, from := range []string{adr.ImportADRTools, adr.ImportLog4brains} {
+		_, err := adr.Import(root, from)
+		assert.ErrorIs(t, err, adr.ErrConfigInvalid, from)
+	}
+	assert.FileExists(t, filepath.Join(parent, "elsewhere/0001-a.md"))
+}
+
+func TestImport_UnknownSource(t *testing.T) {
+	_, err := adr.Import(t.TempDir(), "jira")
+	assert.ErrorContains(t, err, `unknown import source "jira"`)
+}
@@ -6,69 +6,63 @@ import (
 )
 
 // metaFieldExtractor holds a precompiled matcher for one metadata field. Compiling
-// once (package init) matters because ExtractMetaFields runs over every ADR on the
+// once (at registration) matters because ExtractMetaFields runs over every ADR on the
 // List() hot path, once per field.
 type metaFieldExtractor struct {
 	key  string
 	kind string         // "meta" (title-block line) or "frontmatter" (YAML key)
-	re   *regexp.Regexp // capture group 1 holds the raw value
+	re   *regexp.Regexp // "meta" only: capture group 1 holds the raw value
 }
 
-var metaFieldExtractors = buildMetaFieldExtractors()
-
-func buildMetaFieldExtractors() []metaFieldExtractor {
-	out := make([]metaFieldExtractor, 0, len(allMetaFieldDefs))
-	for _, d := range allMetaFieldDefs {
-		var re *regexp.Regexp
-		switch d.Kind {
-		case "meta":
-			// Title-block line "Heading: value" (case-insensitive), matched on the
-			// friendly Heading — the label the app writes via ReplaceMetaField.
-			re = metaFieldPattern(d.Heading)
-		case "frontmatter":
-			// YAML "key: value" matched on the exact lowercase Key. Frontmatter is
-			// single-line only: multi-line block ("- Alice") or flow ("[Alice, Bob]")
-			// YAML lists are not supported (regex-based parse, no YAML dependency).
-			re = regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(d.Key) + `:[ \t]*(.*)$`)
-		default:
-			continue
-		}
-		out = append(out, metaFieldExtractor{key: d.Key, kind: d.Kind, re: re})
+// newMetaFieldExtractor compiles the matcher for d. "frontmatter" fields need
+// none: the YAML block is parsed once per ADR (see frontmatterValues) and
+// looked up by the exact lowercase Key.
+func newMetaFieldExtractor(d TemplateSectionDef) metaFieldExtractor {
+	var re *regexp.Regexp
+	if d.Kind == "meta" {
+		// Title-block line "Heading: value" (case-insensitive), matched on the
+		// friendly Heading — the label the app writes via ReplaceMetaField.
+		re = metaFieldPattern(d.Heading)
 	}
-	return out
+	return metaFieldExtractor{key: d.Key, kind: d.Kind, re: re}
 }
 
-// ExtractMetaFields parses recognized metadata fields (see AllMetaFieldDefs) from an
-// ADR's raw content, returning field key -> trimmed, comma-split values. Fields with
-// no value are omitted; the result is nil when nothing is found. Title-block ("meta")
-// fields are read from the body (frontmatter skipped); "frontmatter" fields from the
-// YAML block. Unfilled template placeholders like "{list everyone…}" are dropped.
+// ExtractMetaFields parses the metadata fields of the built-in templates (see
+// AllMetaFieldDefs) from an ADR's raw content, returning field key -> trimmed
+// values; Config.ExtractMetaFields adds a project's own fields. Fields with no
+// value are omitted; the result is nil when nothing is found. Title-block ("meta")
+// fields are read from the body (frontmatter skipped) and comma-split; "frontmatter"
+// fields from the YAML block, as a comma-separated scalar, a flow list ("[Alice, Bob]")
+// or a block list ("- Alice" lines) — the forms SetFrontmatterList writes. Unfilled
+// template placeholders like "{list everyone…}" are dropped.
 func ExtractMetaFields(content string) map[string][]string {
+	return builtinMetaFields.extract(content)
+}
+
+// extract parses reg's fields from content (see ExtractMetaFields).
+func (reg *metaFieldRegistry) extract(content string) map[string][]string {
+	content, _ = normalizeText(content)
 	body := bodyAfterFrontmatter(content)
-	fm := extractFrontmatter(content)
+	var fmValues map[string][]string
+	if fm := extractFrontmatter(content); fm != "" {
+		fmValues = frontmatterValues(fm)
+	}
+
+	reg.mu.RLock()
+	extractors := reg.extractors
+	reg.mu.RUnlock()
 
 	var result map[string][]string
-	for _, ex := range metaFieldExtractors {
-		var raw string
+	for _, ex := range extractors {
+		var values []string
 		switch ex.kind {
 		case "meta":
-			m := ex.re.FindStringSubmatch(body)
-			if m == nil {
-				continue
+			if m := ex.re.FindStringSubmatch(body); m != nil {
+				values = splitMetaValue(m[1])
 			}
-			raw = m[1]
 		case "frontmatter":
-			if fm == "" {
-				continue
-			}
-			m := ex.re.FindStringSubmatch(fm)
-			if m == nil {
-				continue
-			}
-			raw = stripQuotes(strings.TrimSpace(m[1]))
+			values = fmValues[ex.key]
 		}
-
-		values := splitMetaValue(raw)
 		if len(values) == 0 {
 			continue
 		}
@@ -80,6 +74,103 @@ func ExtractMetaFields(content string) map[string][]string {
 	return result
 }
 
+// frontmatterValues reads the top-level keys of a frontmatter block into their
+// values: a scalar is unquoted and comma-split (see splitMetaValue), each item of
+// a flow or block list is kept whole. Comment lines are skipped; other YAML
+// (nested maps, multi-line strings) yields no values for its key.
+func frontmatterValues(fm string) map[string][]string {
+	values := make(map[string][]string)
+	key := "" // the key whose block list is being read
+	add := func(k, item string) {
+		item = strings.TrimSpace(yamlUnquote(strings.TrimSpace(item)))
+		if item != "" && !isPlaceholder(item) {
+			values[k] = append(values[k], item)
+		}
+	}
+	for _, line := range strings.Split(fm, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if m := frontmatterKeyLinePattern.FindStringSubmatch(line); m != nil {
+			key = ""
+			raw := strings.TrimSpace(m[2])
+			switch {
+			case raw == "":
+				key = m[1]
+			case strings.HasPrefix(raw, "[") && strings.HasSuffix(raw, "]"):
+				for _, item := range splitFlowList(raw[1 : len(raw)-1]) {
+					add(m[1], item)
+				}
+			default:
+				if v := splitMetaValue(yamlUnquote(raw)); len(v) > 0 {
+					values[m[1]] = v
+				}
+			}
+			continue
+		}
+		if key != "" && (trimmed == "-" || strings.HasPrefix(trimmed, "- ")) {
+			add(key, strings.TrimPrefix(trimmed, "-"))
+			continue
+		}
+		key = ""
+	}
+	return values
+}
+
+// splitFlowList splits the inside of a YAML flow list at the commas outside
+// quotes.
+func splitFlowList(s string) []string {
+	var items []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			items = append(items, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(items, s[start:])
+}
+
+// yamlUnquote returns the value of a double- or single-quoted YAML scalar, as
+// written by yamlScalar; other strings are returned unchanged.
+func yamlUnquote(s string) string {
+	if len(s) < 2 {
+		return s
+	}
+	switch {
+	case s[0] == '"' && s[len(s)-1] == '"':
+		return strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(s[1 : len(s)-1])
+	case s[0] == '\'' && s[len(s)-1] == '\'':
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	return s
+}
+
+// SplitListValue splits a list field as typed into the create form or the
+// `adr new` wizard, at commas and newlines, trimming each value and dropping
+// blanks.
+func SplitListValue(text string) []string {
+	var out []string
+	for _, v := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
 // splitMetaValue trims a raw field value, drops it entirely if it is a single "{…}"
 // placeholder (checked BEFORE splitting so a placeholder containing a comma isn't
 // mis-split), then comma-splits, trimming each token and dropping empties and any
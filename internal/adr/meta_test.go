@@ -5,6 +5,7 @@ import (
 
 	"github.com/BobMali/adr-helper/internal/adr"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestExtractMetaFields_ScopeTitleBlock(t *testing.T) {
@@ -51,6 +52,35 @@ func TestExtractMetaFields_FrontmatterFields(t *testing.T) {
 	assert.Equal(t, []string{"Eve"}, meta["informed"])
 }
 
+func TestExtractMetaFields_FrontmatterLists(t *testing.T) {
+	content := "---\n" +
+		"decision-makers:\n  - Alice\n  - \"Smith, J.\"\n  - {placeholder}\n" +
+		"# a comment\n" +
+		"consulted: [Carol, 'O''Brien', \"Dave, E.\"]\n" +
+		"informed:\n- Eve\n" +
+		"status: accepted\n" +
+		"---\n\n# 1. Title\n"
+
+	meta := adr.ExtractMetaFields(content)
+	assert.Equal(t, []string{"Alice", "Smith, J."}, meta["decision-makers"], "block list items are kept whole")
+	assert.Equal(t, []string{"Carol", "O'Brien", "Dave, E."}, meta["consulted"])
+	assert.Equal(t, []string{"Eve"}, meta["informed"])
+}
+
+func TestExtractMetaFields_ReadsSetFrontmatterList(t *testing.T) {
+	content := "---\nstatus: accepted\ndecision-makers: {list everyone}\n---\n\n# 1. Title\n"
+	values := []string{"Alice", "Team: Platform", `say "hi"`}
+
+	result, found := adr.SetFrontmatterList(content, "decision-makers", values)
+	require.True(t, found)
+	assert.Equal(t, values, adr.ExtractMetaFields(result)["decision-makers"])
+}
+
+func TestSplitListValue(t *testing.T) {
+	assert.Equal(t, []string{"Alice", "Bob", "Carol"}, adr.SplitListValue(" Alice, Bob,\n\nCarol ,"))
+	assert.Nil(t, adr.SplitListValue(" , \n"))
+}
+
 func TestExtractMetaFields_SkipsWholePlaceholder(t *testing.T) {
 	// The shipped MADR template placeholder must not become a facet value.
 	content := "---\n" +
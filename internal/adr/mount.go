@@ -0,0 +1,65 @@
+package adr
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// Mount loads the config of the project at dir as one of several projects
+// served together under names (see adr-web --project). Its directories are
+// resolved against dir, so they hold wherever the caller runs, and links from
+// other projects into it are labelled with name (see LinkTo). SaveConfig
+// writes only a mounted config's scopes back, keeping the file's directories.
+func Mount(name, dir string) (*Config, error) {
+	if !rootNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("project name %q must be letters, digits, '.', '_' or '-'", name)
+	}
+	cfg, err := LoadConfig(dir)
+	if err != nil {
+		return nil, err
+	}
+	cfg.project, cfg.projectDir = name, dir
+	if cfg.Directory != "" {
+		cfg.Directory = filepath.Join(dir, cfg.Directory)
+	}
+	roots := make([]RootDef, len(cfg.Roots))
+	for i, def := range cfg.Roots {
+		def.Directory = filepath.Join(dir, def.Directory)
+		roots[i] = def
+	}
+	cfg.Roots = roots
+	// Project templates are files below the ADR directory, which LoadConfig
+	// resolved against the working directory.
+	registerProjectMetaFields(cfg)
+	return cfg, nil
+}
+
+// Federate records that the projects of cfgs (see Mount) are served together,
+// so renaming, renumbering or archiving an ADR in one of them also rewrites
+// the links to it in the others. Call it before taking roots (Root, AllRoots)
+// of the configs: a root's config copies the project's.
+func Federate(cfgs ...*Config) {
+	for _, c := range cfgs {
+		c.federation = cfgs
+	}
+}
+
+// ProjectName returns the name c was mounted under, or "" when it wasn't.
+func (c *Config) ProjectName() string {
+	return c.project
+}
+
+// unmountedRoots returns the roots of disk, as read from the project's
+// .adr.json, with the scopes of c's roots.
+func (c *Config) unmountedRoots(disk []RootDef) []RootDef {
+	roots := make([]RootDef, len(disk))
+	for i, def := range disk {
+		for _, mounted := range c.Roots {
+			if mounted.Name == def.Name {
+				def.Scopes = mounted.Scopes
+			}
+		}
+		roots[i] = def
+	}
+	return roots
+}
@@ -0,0 +1,122 @@
+package adr_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// projectConfig is the config of a project with a single root in docs/adr.
+const projectConfig = `{"version": "1", "directory": "docs/adr", "template": "nygard"}`
+
+func TestMount(t *testing.T) {
+	dir := writeRootsConfig(t, "docs/adr")
+	cfg, err := adr.Mount("shop", dir)
+	require.NoError(t, err)
+	assert.Equal(t, "shop", cfg.ProjectName())
+	assert.Equal(t, filepath.Join(dir, "docs", "adr"), cfg.Directory)
+	payments, err := cfg.Root("payments")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "services", "payments", "docs", "adr"), payments.Directory)
+	assert.Equal(t, "shop", payments.ProjectName())
+
+	_, err = adr.Mount("bad:name", dir)
+	assert.Error(t, err)
+}
+
+func TestSaveConfig_Mounted(t *testing.T) {
+	dir := writeRootsConfig(t, "docs/adr")
+	cfg, err := adr.Mount("shop", dir)
+	require.NoError(t, err)
+	payments, err := cfg.Root("payments")
+	require.NoError(t, err)
+	_, err = payments.AddScope("Refunds")
+	require.NoError(t, err)
+	require.NoError(t, adr.SaveConfig(dir, payments))
+
+	saved, err := adr.LoadConfig(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "docs/adr", saved.Directory)
+	assert.Equal(t, "services/payments/docs/adr", saved.Roots[0].Directory)
+	assert.Equal(t, []string{"Checkout", "Refunds"}, saved.Roots[0].Scopes)
+}
+
+func TestConfig_RelateAcrossProjects(t *testing.T) {
+	shopDir := t.TempDir()
+	writeFiles(t, shopDir, map[string]string{
+		adr.ConfigFileName:        projectConfig,
+		"docs/adr/0001-use-go.md": "# 1. Use Go\n\n## Status\n\nAccepted\n",
+	})
+	billingDir := t.TempDir()
+	writeFiles(t, billingDir, map[string]string{
+		adr.ConfigFileName:              projectConfig,
+		"docs/adr/0001-use-postgres.md": "# 1. Use Postgres\n\n## Status\n\nAccepted\n",
+		"docs/adr/0002-use-invoices.md": "# 2. Use invoices\n\n## Status\n\nProposed\n",
+	})
+	shop, err := adr.Mount("shop", shopDir)
+	require.NoError(t, err)
+	billing, err := adr.Mount("billing", billingDir)
+	require.NoError(t, err)
+
+	record, err := shop.Relate(1, billing, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "Use Go", record.Title)
+
+	link := shop.LinkTo("0001-use-go.md", billing, 2, "0002-use-invoices.md")
+	assert.Equal(t, "billing/ADR-0002", link.Label)
+	rel, err := filepath.Rel(filepath.Join(shopDir, "docs", "adr"), filepath.Join(billingDir, "docs", "adr", "0002-use-invoices.md"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.ToSlash(rel), link.Filename)
+
+	content, err := os.ReadFile(filepath.Join(shopDir, "docs", "adr", "0001-use-go.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "[billing/ADR-0002]("+link.Filename+")")
+	content, err = os.ReadFile(filepath.Join(billingDir, "docs", "adr", "0002-use-invoices.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "[shop/ADR-0001](")
+	assert.NoFileExists(t, filepath.Join(shopDir, "docs", "adr", adr.JournalFileName))
+
+	file, ok := shop.LinkPath("0001-use-go.md", link.Filename)
+	require.True(t, ok)
+	_, ok = shop.Locate(file)
+	assert.False(t, ok)
+	id, ok := billing.Locate(file)
+	assert.True(t, ok)
+	assert.Equal(t, adr.ID{Number: 2}, id)
+
+	_, err = shop.Relate(1, billing, 9)
+	assert.ErrorIs(t, err, adr.ErrNotFound)
+	content, err = os.ReadFile(filepath.Join(shopDir, "docs", "adr", "0001-use-go.md"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(content), "billing/"))
+}
+
+func TestFederate_RenameRewritesOtherProjects(t *testing.T) {
+	shopDir, billingDir := t.TempDir(), t.TempDir()
+	writeFiles(t, shopDir, map[string]string{
+		adr.ConfigFileName:        projectConfig,
+		"docs/adr/0001-use-go.md": "# 1. Use Go\n\n## Status\n\nAccepted\n",
+	})
+	writeFiles(t, billingDir, map[string]string{
+		adr.ConfigFileName:              projectConfig,
+		"docs/adr/0002-use-invoices.md": "# 2. Use invoices\n\n## Status\n\nProposed\n",
+	})
+	shop, err := adr.Mount("shop", shopDir)
+	require.NoError(t, err)
+	billing, err := adr.Mount("billing", billingDir)
+	require.NoError(t, err)
+	adr.Federate(shop, billing)
+	_, err = shop.Relate(1, billing, 2)
+	require.NoError(t, err)
+
+	_, err = billing.Repository().Rename(context.Background(), 2, "Use e-invoices")
+	require.NoError(t, err)
+	link := shop.LinkTo("0001-use-go.md", billing, 2, "0002-use-e-invoices.md")
+	assert.Contains(t, readFile(t, filepath.Join(shopDir, "docs/adr/0001-use-go.md")), "["+link.Label+"]("+link.Filename+")")
+}
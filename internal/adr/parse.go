@@ -18,26 +18,34 @@ type Metadata struct {
 	Meta map[string][]string
 }
 
-var numberedHeadingPattern = regexp.MustCompile(`(?m)^# (\d+)\.\s+(.+)$`)
-var plainHeadingPattern = regexp.MustCompile(`(?m)^# (.+)$`)
+var numberedTitlePattern = regexp.MustCompile(`^(\d+)\.\s+(.+)$`)
 var bodyDatePattern = regexp.MustCompile(`(?mi)^[Dd]ate:\s*(.+)$`)
 var frontmatterDatePattern = regexp.MustCompile(`(?m)^date:\s*(.+)$`)
 
-// ExtractMetadata parses an ADR's raw markdown content and returns structured metadata.
+// ExtractMetadata parses an ADR's raw markdown content and returns structured
+// metadata, with the built-in templates' metadata fields (see ExtractMetaFields).
 func ExtractMetadata(content string) Metadata {
-	var m Metadata
+	return extractMetadata(content, builtinMetaFields)
+}
 
-	// Number + Title from heading
-	if matches := numberedHeadingPattern.FindStringSubmatch(content); len(matches) == 3 {
-		// Safe to ignore error — regex guarantees digits
-		n := 0
-		for _, ch := range matches[1] {
-			n = n*10 + int(ch-'0')
+// extractMetadata is ExtractMetadata reading the metadata fields of fields.
+func extractMetadata(content string, fields *metaFieldRegistry) Metadata {
+	var m Metadata
+	content, _ = normalizeText(content)
+
+	// Number + Title from the first "# " heading
+	if h, ok := titleHeading(content); ok {
+		if matches := numberedTitlePattern.FindStringSubmatch(h.Text); matches != nil {
+			// Safe to ignore error — regex guarantees digits
+			n := 0
+			for _, ch := range matches[1] {
+				n = n*10 + int(ch-'0')
+			}
+			m.Number = n
+			m.Title = strings.TrimSpace(matches[2])
+		} else {
+			m.Title = h.Text
 		}
-		m.Number = n
-		m.Title = strings.TrimSpace(matches[2])
-	} else if matches := plainHeadingPattern.FindStringSubmatch(content); len(matches) == 2 {
-		m.Title = strings.TrimSpace(matches[1])
 	}
 
 	// Status
@@ -59,7 +67,7 @@ func ExtractMetadata(content string) Metadata {
 	}
 
 	// Recognized metadata fields (scope, frontmatter fields, …) for filtering/display.
-	m.Meta = ExtractMetaFields(content)
+	m.Meta = fields.extract(content)
 
 	return m
 }
@@ -52,6 +52,35 @@ func TestExtractMetadata_HeadingWithoutNumber(t *testing.T) {
 	assert.Equal(t, "2024-02-01", meta.Date)
 }
 
+func TestExtractMetadata_IgnoresHeadingsInCodeFence(t *testing.T) {
+	content := "```markdown\n# 9. Example\n\n## Status\n\nRejected\n```\n\n# 7. Use Fences\n\n## Status\n\nAccepted\n"
+
+	meta := ExtractMetadata(content)
+
+	assert.Equal(t, 7, meta.Number)
+	assert.Equal(t, "Use Fences", meta.Title)
+	assert.Equal(t, "Accepted", meta.Status)
+}
+
+func TestExtractMetadata_IgnoresHeadingsInHTMLComment(t *testing.T) {
+	content := "<!--\n# NUMBER. TITLE\n-->\n# 8. Use Comments\n\n<!--\n## Status\n-->\n## Status\n\nProposed\n"
+
+	meta := ExtractMetadata(content)
+
+	assert.Equal(t, 8, meta.Number)
+	assert.Equal(t, "Use Comments", meta.Title)
+	assert.Equal(t, "Proposed", meta.Status)
+}
+
+func TestExtractMetadata_SetextHeadings(t *testing.T) {
+	content := "Use Setext\n==========\n\nStatus\n------\n\nDeprecated\n"
+
+	meta := ExtractMetadata(content)
+
+	assert.Equal(t, "Use Setext", meta.Title)
+	assert.Equal(t, "Deprecated", meta.Status)
+}
+
 func TestExtractMetadata_SupersededWithLink(t *testing.T) {
 	content := "# 4. Old Approach\n\nDate: 2024-01-01\n\n## Status\n\nSuperseded by [ADR-0005](0005-foo.md)\n\n## Context\n\nOld context.\n"
 
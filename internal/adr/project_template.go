@@ -0,0 +1,539 @@
+package adr
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/BobMali/adr-helper/internal/markdown"
+)
+
+// ErrUnknownTemplate is returned by LoadProjectTemplate for a name that is
+// neither a built-in nor one of the project's templates.
+var ErrUnknownTemplate = errors.New("unknown template")
+
+// ProjectTemplate is a template resolved for a project: a built-in, a template
+// declared in Config.Templates, or a file in Config.TemplatesDir.
+type ProjectTemplate struct {
+	Name    string
+	Content string
+	// Sections is the full section schema, including "frontmatter" fields.
+	Sections []TemplateSectionDef
+	Builtin  bool
+	// Path is the file Content was read from, relative to the ADR directory;
+	// empty for a built-in served from the embedded copy.
+	Path string
+	// Partials holds the project's shared partials (Config.PartialsDir) by
+	// name, for use as {{template "name" .}} (see Execute).
+	Partials map[string]string
+}
+
+// EditableSections returns the section defs the create form and the `adr new`
+// wizard can fill in: a copy of Sections (see ApplySections).
+func (t *ProjectTemplate) EditableSections() []TemplateSectionDef {
+	return slices.Clone(t.Sections)
+}
+
+var (
+	templateMetaLinePattern   = regexp.MustCompile(`^([A-Z][A-Za-z -]*):[ \t]*(.*)$`)
+	frontmatterKeyLinePattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_-]*):[ \t]*(.*)$`)
+	htmlCommentPattern        = regexp.MustCompile(`<!--.*?-->`)
+)
+
+// LoadProjectTemplate resolves the template called name for cfg. Templates
+// declared in cfg.Templates come first, then cfg.TemplatesDir/<name>.md, then
+// the built-ins. A cfg.Template that is none of these names the project's own
+// template file (cfg.TemplateFile), whose schema is derived from its headings.
+// Declared templates without Sections get a derived schema too.
+//
+// A built-in is read from the project's copy when there is one: the File of a
+// cfg.Templates entry with the built-in's name, or cfg.TemplateFile (written by
+// `adr init`) for the built-in named by cfg.Template. A missing copy is an
+// error, as it is for `adr new`. When the copy differs from the embedded
+// template its schema is derived from it, keeping the built-in definitions for
+// sections it still has (see overlayBuiltinSections).
+//
+// Every template gets the project's partials from cfg.PartialsDir.
+func LoadProjectTemplate(cfg *Config, name string) (*ProjectTemplate, error) {
+	t, err := resolveProjectTemplate(cfg, name)
+	if err != nil {
+		return nil, err
+	}
+	if t.Partials, err = loadPartials(cfg); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func resolveProjectTemplate(cfg *Config, name string) (*ProjectTemplate, error) {
+	if builtin, ok := templateSections[TemplateName(name)]; ok {
+		return loadBuiltinTemplate(cfg, name, builtin)
+	}
+
+	if def, ok := cfg.Templates[name]; ok {
+		path := def.File
+		if path == "" {
+			path = filepath.Join(cfg.TemplatesDir, name+".md")
+		}
+		content, err := readProjectTemplateFile(cfg, name, path)
+		if err != nil {
+			return nil, err
+		}
+		sections := append([]TemplateSectionDef(nil), def.Sections...)
+		if len(sections) == 0 {
+			sections = DeriveTemplateSections(content)
+		}
+		return &ProjectTemplate{Name: name, Content: content, Sections: sections, Path: path}, nil
+	}
+
+	if cfg.TemplatesDir != "" && validTemplateName(name) == nil {
+		path := filepath.Join(cfg.TemplatesDir, name+".md")
+		content, err := readProjectTemplateFile(cfg, name, path)
+		if err == nil {
+			return &ProjectTemplate{Name: name, Content: content, Sections: DeriveTemplateSections(content), Path: path}, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	if name != "" && name == cfg.Template {
+		content, err := readProjectTemplateFile(cfg, name, cfg.TemplateFile)
+		if err != nil {
+			return nil, err
+		}
+		return &ProjectTemplate{Name: name, Content: content, Sections: DeriveTemplateSections(content), Path: cfg.TemplateFile}, nil
+	}
+
+	return nil, fmt.Errorf("%w %q, valid templates: %v", ErrUnknownTemplate, name, ProjectTemplateNames(cfg))
+}
+
+// EmbeddedTemplate returns the built-in template called name as embedded in
+// the binary, ignoring any project copy, with the project's partials. It
+// fails with ErrUnknownTemplate for other names.
+func EmbeddedTemplate(cfg *Config, name string) (*ProjectTemplate, error) {
+	builtin, ok := templateSections[TemplateName(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownTemplate, name)
+	}
+	t, err := loadBuiltinTemplate(&Config{}, name, builtin)
+	if err != nil {
+		return nil, err
+	}
+	if t.Partials, err = loadPartials(cfg); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func loadBuiltinTemplate(cfg *Config, name string, builtin []TemplateSectionDef) (*ProjectTemplate, error) {
+	content, err := TemplateContent(name)
+	if err != nil {
+		return nil, err
+	}
+	t := &ProjectTemplate{
+		Name:     name,
+		Content:  content,
+		Sections: append([]TemplateSectionDef(nil), builtin...),
+		Builtin:  true,
+	}
+
+	var path string
+	if def, ok := cfg.Templates[name]; ok {
+		path = def.File
+		if path == "" {
+			path = filepath.Join(cfg.TemplatesDir, name+".md")
+		}
+	} else if name == cfg.Template {
+		path = cfg.TemplateFile
+	}
+	if path == "" {
+		return t, nil
+	}
+
+	onDisk, err := readProjectTemplateFile(cfg, name, path)
+	if err != nil {
+		return nil, err
+	}
+	t.Path = path
+	if onDisk != content {
+		t.Content = onDisk
+		t.Sections = overlayBuiltinSections(DeriveTemplateSections(onDisk), builtin)
+	}
+	return t, nil
+}
+
+// TemplateChoices returns the templates offered when creating an ADR, the
+// default (cfg.Template) first. A project that declares templates (in
+// cfg.Templates or cfg.TemplatesDir) is offered those; otherwise every
+// built-in is offered.
+func TemplateChoices(cfg *Config) []string {
+	declared := templatesDirNames(cfg)
+	for n := range cfg.Templates {
+		declared = append(declared, n)
+	}
+	sort.Strings(declared)
+	if len(declared) == 0 {
+		declared = ValidTemplateNames()
+	}
+
+	choices := []string{cfg.Template}
+	seen := map[string]bool{cfg.Template: true}
+	for _, n := range declared {
+		if !seen[n] {
+			seen[n] = true
+			choices = append(choices, n)
+		}
+	}
+	return choices
+}
+
+// KnownSections returns the section definitions of every template offered for
+// cfg (see TemplateChoices), the default template's first, for matching an
+// ADR's headings to section keys (see ParseDocument). load resolves a template
+// name, LoadProjectTemplate when nil; templates that fail to load are skipped.
+func KnownSections(cfg *Config, load func(name string) (*ProjectTemplate, error)) []TemplateSectionDef {
+	if load == nil {
+		load = func(name string) (*ProjectTemplate, error) { return LoadProjectTemplate(cfg, name) }
+	}
+	var defs []TemplateSectionDef
+	for _, name := range TemplateChoices(cfg) {
+		if tmpl, err := load(name); err == nil {
+			defs = append(defs, tmpl.Sections...)
+		}
+	}
+	return defs
+}
+
+// overlayBuiltinSections returns the sections derived from a customized copy
+// of a built-in template, with each one the built-in also defines replaced by
+// the built-in definition (curated placeholder and optional flag).
+func overlayBuiltinSections(derived, builtin []TemplateSectionDef) []TemplateSectionDef {
+	byKey := make(map[string]TemplateSectionDef, len(builtin))
+	for _, s := range builtin {
+		byKey[s.Key] = s
+	}
+	result := make([]TemplateSectionDef, len(derived))
+	for i, s := range derived {
+		if b, ok := byKey[s.Key]; ok {
+			s = b
+		}
+		result[i] = s
+	}
+	return result
+}
+
+// ProjectTemplateNames returns the built-in template names followed by the
+// project's own templates (declared, then found in TemplatesDir), sorted.
+func ProjectTemplateNames(cfg *Config) []string {
+	names := ValidTemplateNames()
+	seen := make(map[string]bool, len(names))
+	for _, n := range names {
+		seen[n] = true
+	}
+
+	var custom []string
+	add := func(n string) {
+		if !seen[n] {
+			seen[n] = true
+			custom = append(custom, n)
+		}
+	}
+	for n := range cfg.Templates {
+		add(n)
+	}
+	for _, n := range templatesDirNames(cfg) {
+		add(n)
+	}
+	if cfg.Template != "" {
+		add(cfg.Template)
+	}
+	sort.Strings(custom)
+	return append(names, custom...)
+}
+
+// templatesDirNames returns the names of the <name>.md templates in
+// cfg.TemplatesDir, in directory order.
+func templatesDirNames(cfg *Config) []string {
+	if cfg.TemplatesDir == "" {
+		return nil
+	}
+	entries, _ := os.ReadDir(filepath.Join(cfg.templateDir(), cfg.TemplatesDir))
+	var names []string
+	for _, e := range entries {
+		n := strings.TrimSuffix(e.Name(), ".md")
+		if e.IsDir() || n == e.Name() || validTemplateName(n) != nil {
+			continue
+		}
+		names = append(names, n)
+	}
+	return names
+}
+
+// registerProjectMetaFields registers the metadata fields of every project
+// template (including customized copies of built-ins) with cfg, so its
+// repository and the filter facets treat them like the built-ins. It is
+// best-effort: a template that fails to load here reports its error when it is
+// actually used.
+func registerProjectMetaFields(cfg *Config) {
+	for _, name := range ProjectTemplateNames(cfg) {
+		if t, err := LoadProjectTemplate(cfg, name); err == nil {
+			cfg.RegisterMetaFieldDefs(t.Sections)
+		}
+	}
+}
+
+func readProjectTemplateFile(cfg *Config, name, path string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(cfg.templateDir(), path))
+	if err != nil {
+		return "", fmt.Errorf("reading template %q: %w", name, err)
+	}
+	return string(data), nil
+}
+
+// DeriveTemplateSections builds a section schema from template content:
+//   - every "##"/"###" heading except Status and "{…}" placeholders becomes an "h2"/"h3" section,
+//     optional when it is "###" or preceded by an HTML comment mentioning
+//     "optional" (the MADR convention);
+//   - every "Label: value" line between the "# " heading and the first section
+//     becomes a "meta" field (Date excepted), a Scope line drawing from the
+//     project's scope vocabulary;
+//   - every frontmatter key except status and date becomes an optional
+//     "frontmatter" field.
+//
+// Placeholders are the text under each heading (or after each label) with
+// HTML comments and "{…}" braces removed. Keys are the slugified headings.
+func DeriveTemplateSections(content string) []TemplateSectionDef {
+	content, _ = templateActions(content)
+	content, _ = normalizeText(content)
+	var defs []TemplateSectionDef
+	seen := make(map[string]bool)
+	add := func(d TemplateSectionDef) {
+		// Frontmatter keys are matched verbatim (see ExtractMetaFields), so
+		// only headings and labels are slugified.
+		if d.Kind != "frontmatter" {
+			key, err := Slugify(d.Key)
+			if err != nil {
+				return
+			}
+			d.Key = key
+		}
+		if seen[d.Key] {
+			return
+		}
+		seen[d.Key] = true
+		defs = append(defs, d)
+	}
+
+	for _, line := range strings.Split(extractFrontmatter(content), "\n") {
+		m := frontmatterKeyLinePattern.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if m == nil {
+			continue
+		}
+		key := strings.ToLower(m[1])
+		if key == "status" || key == "date" {
+			continue
+		}
+		add(TemplateSectionDef{
+			Key:         key,
+			Heading:     headingFromKey(key),
+			Kind:        "frontmatter",
+			Optional:    true,
+			Placeholder: templatePlaceholder(stripQuotes(strings.TrimSpace(m[2]))),
+		})
+	}
+
+	body := bodyAfterFrontmatter(content)
+	lines := strings.Split(body, "\n")
+	headings := markdown.Headings(body)
+	headingAt := make(map[int]int, len(headings)) // first line -> index in headings
+	for j, h := range headings {
+		first, _ := headingLines(body, h)
+		headingAt[first] = j
+	}
+	inTitleBlock, inFence := false, false
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if j, ok := headingAt[i]; ok {
+			h := headings[j]
+			first, last := headingLines(body, h)
+			i = last // past a setext underline
+			switch {
+			case h.Level == 1:
+				inTitleBlock = !hasBodySection(defs)
+			case h.Level == 2 || h.Level == 3:
+				inTitleBlock = false
+				// "### {title of option 1}" style headings are example content
+				// inside a section, not sections of their own.
+				if strings.EqualFold(h.Text, "status") || isPlaceholder(h.Text) {
+					continue
+				}
+				add(TemplateSectionDef{
+					Key:         h.Text,
+					Heading:     h.Text,
+					Kind:        fmt.Sprintf("h%d", h.Level),
+					Optional:    h.Level == 3 || markedOptional(lines, first),
+					Placeholder: sectionPlaceholder(body, headings, j),
+				})
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || !inTitleBlock {
+			continue
+		}
+
+		m := templateMetaLinePattern.FindStringSubmatch(trimmed)
+		if m == nil {
+			continue
+		}
+		label := strings.TrimSpace(m[1])
+		if strings.EqualFold(label, "date") || strings.EqualFold(label, "status") {
+			continue
+		}
+		add(TemplateSectionDef{
+			Key:         label,
+			Heading:     label,
+			Kind:        "meta",
+			Vocabulary:  strings.EqualFold(label, "scope"),
+			Placeholder: templatePlaceholder(m[2]),
+		})
+	}
+	return defs
+}
+
+func hasBodySection(defs []TemplateSectionDef) bool {
+	for _, d := range defs {
+		if d.Kind == "h2" || d.Kind == "h3" {
+			return true
+		}
+	}
+	return false
+}
+
+// markedOptional reports whether the last non-blank line before lines[i] is an
+// HTML comment mentioning "optional".
+func markedOptional(lines []string, i int) bool {
+	for j := i - 1; j >= 0; j-- {
+		prev := strings.TrimSpace(lines[j])
+		if prev == "" {
+			continue
+		}
+		return strings.HasPrefix(prev, "<!--") && strings.Contains(strings.ToLower(prev), "optional")
+	}
+	return false
+}
+
+// sectionPlaceholder returns the template text under headings[j] up to the
+// next heading of level 3 or higher, cleaned by templatePlaceholder.
+func sectionPlaceholder(body string, headings []markdown.Heading, j int) string {
+	end := len(body)
+	for _, h := range headings[j+1:] {
+		if h.Level <= 3 {
+			end = h.Start
+			break
+		}
+	}
+	return templatePlaceholder(body[headings[j].End:end])
+}
+
+// templatePlaceholder strips HTML comments and surrounding whitespace, and the
+// braces of a single "{…}" placeholder.
+func templatePlaceholder(s string) string {
+	s = strings.TrimSpace(htmlCommentPattern.ReplaceAllString(s, ""))
+	if isPlaceholder(s) && !strings.Contains(s, "\n") {
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	return s
+}
+
+// headingFromKey turns a frontmatter key like "decision-makers" into the
+// display heading "Decision Makers".
+func headingFromKey(key string) string {
+	words := strings.FieldsFunc(key, func(r rune) bool { return r == '-' || r == '_' })
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// validateTemplateDefs checks the project template declarations in cfg.
+func validateTemplateDefs(cfg *Config) error {
+	if cfg.TemplatesDir != "" {
+		if err := validateRelativePath(cfg.TemplatesDir); err != nil {
+			return fmt.Errorf("templatesDir: %v: %w", err, ErrConfigInvalid)
+		}
+	}
+	if cfg.PartialsDir != "" {
+		if err := validateRelativePath(cfg.PartialsDir); err != nil {
+			return fmt.Errorf("partialsDir: %v: %w", err, ErrConfigInvalid)
+		}
+	}
+	for name, def := range cfg.Templates {
+		if err := validTemplateName(name); err != nil {
+			return fmt.Errorf("template %q: %v: %w", name, err, ErrConfigInvalid)
+		}
+		if _, ok := templateSections[TemplateName(name)]; ok && len(def.Sections) > 0 {
+			return fmt.Errorf("template %q is built in; only its file can be set: %w", name, ErrConfigInvalid)
+		}
+		if def.File == "" && cfg.TemplatesDir == "" {
+			return fmt.Errorf("template %q needs a file or a templatesDir: %w", name, ErrConfigInvalid)
+		}
+		if def.File != "" {
+			if err := validateRelativePath(def.File); err != nil {
+				return fmt.Errorf("template %q: %v: %w", name, err, ErrConfigInvalid)
+			}
+			if filepath.Ext(def.File) != ".md" {
+				return fmt.Errorf("template %q: file must have .md extension: %w", name, ErrConfigInvalid)
+			}
+		}
+		keys := make(map[string]bool, len(def.Sections))
+		for _, s := range def.Sections {
+			switch {
+			case s.Key == "" || s.Heading == "":
+				return fmt.Errorf("template %q: sections need a key and a heading: %w", name, ErrConfigInvalid)
+			case keys[s.Key]:
+				return fmt.Errorf("template %q: duplicate section key %q: %w", name, s.Key, ErrConfigInvalid)
+			}
+			switch s.Kind {
+			case "h2", "h3", "meta", "frontmatter":
+			default:
+				return fmt.Errorf("template %q: section %q has unknown kind %q: %w", name, s.Key, s.Kind, ErrConfigInvalid)
+			}
+			keys[s.Key] = true
+		}
+	}
+	return nil
+}
+
+// validTemplateName reports whether name can be used as a template name (and
+// so as a <name>.md file in TemplatesDir).
+func validTemplateName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("name must not be empty")
+	}
+	if strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return fmt.Errorf("name must not contain path separators")
+	}
+	return nil
+}
+
+// validateRelativePath rejects absolute paths and paths escaping the ADR directory.
+func validateRelativePath(p string) error {
+	if filepath.IsAbs(p) {
+		return fmt.Errorf("path %q must be relative", p)
+	}
+	clean := filepath.Clean(p)
+	if clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("path %q must stay inside the ADR directory", p)
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+package adr_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rfcTemplate = `---
+status: proposed
+date: {YYYY-MM-DD}
+owner: {team that owns the decision}
+---
+
+# Title
+
+Date:
+
+Scope:
+
+Reviewer: {who signed off}
+
+## Status
+
+Proposed
+
+## Problem
+
+{What problem are we solving?}
+
+## Proposal
+
+Describe the proposal.
+
+<!-- This is an optional element. Feel free to remove. -->
+## Alternatives
+
+* Option 1
+
+### Risks
+
+` + "```" + `
+## Not a heading
+` + "```" + `
+`
+
+func TestDeriveTemplateSections(t *testing.T) {
+	defs := adr.DeriveTemplateSections(rfcTemplate)
+
+	assert.Equal(t, []adr.TemplateSectionDef{
+		{Key: "owner", Heading: "Owner", Kind: "frontmatter", Optional: true, Placeholder: "team that owns the decision"},
+		{Key: "scope", Heading: "Scope", Kind: "meta", Vocabulary: true},
+		{Key: "reviewer", Heading: "Reviewer", Kind: "meta", Placeholder: "who signed off"},
+		{Key: "problem", Heading: "Problem", Kind: "h2", Placeholder: "What problem are we solving?"},
+		{Key: "proposal", Heading: "Proposal", Kind: "h2", Placeholder: "Describe the proposal."},
+		{Key: "alternatives", Heading: "Alternatives", Kind: "h2", Optional: true, Placeholder: "* Option 1"},
+		{Key: "risks", Heading: "Risks", Kind: "h3", Optional: true, Placeholder: "```\n## Not a heading\n```"},
+	}, defs)
+}
+
+func TestDeriveTemplateSections_MatchesBuiltinNygardKeys(t *testing.T) {
+	content, err := adr.TemplateContent("nygard")
+	require.NoError(t, err)
+	builtin, err := adr.TemplateSections("nygard")
+	require.NoError(t, err)
+
+	derived := adr.DeriveTemplateSections(content)
+	require.Len(t, derived, len(builtin))
+	for i := range builtin {
+		assert.Equal(t, builtin[i].Key, derived[i].Key)
+		assert.Equal(t, builtin[i].Kind, derived[i].Kind)
+	}
+}
+
+func setupProjectTemplates(t *testing.T, cfgJSON string, files map[string]string) *adr.Config {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, "docs/adr", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(root, adr.ConfigFileName), []byte(cfgJSON), 0o644))
+	cfg, err := adr.LoadConfig(root)
+	require.NoError(t, err)
+	cfg.Directory = filepath.Join(root, cfg.Directory)
+	return cfg
+}
+
+func TestLoadProjectTemplate_DeclaredWithSchema(t *testing.T) {
+	cfg := setupProjectTemplates(t, `{
+		"version": "1", "directory": "docs/adr", "template": "rfc",
+		"templates": {"rfc": {"file": "rfc.md", "sections": [
+			{"key": "problem", "heading": "Problem", "kind": "h2", "placeholder": "Why?"}
+		]}}
+	}`, map[string]string{"rfc.md": rfcTemplate})
+
+	tmpl, err := adr.LoadProjectTemplate(cfg, "rfc")
+	require.NoError(t, err)
+	assert.False(t, tmpl.Builtin)
+	assert.Equal(t, rfcTemplate, tmpl.Content)
+	assert.Equal(t, []adr.TemplateSectionDef{{Key: "problem", Heading: "Problem", Kind: "h2", Placeholder: "Why?"}}, tmpl.EditableSections())
+}
+
+func TestLoadProjectTemplate_DeclaredWithoutSchemaIsDerived(t *testing.T) {
+	cfg := setupProjectTemplates(t, `{
+		"version": "1", "directory": "docs/adr", "template": "nygard",
+		"templates": {"rfc": {"file": "rfc.md"}}
+	}`, map[string]string{"rfc.md": rfcTemplate})
+
+	tmpl, err := adr.LoadProjectTemplate(cfg, "rfc")
+	require.NoError(t, err)
+	assert.Equal(t, adr.DeriveTemplateSections(rfcTemplate), tmpl.Sections)
+	assert.Equal(t, tmpl.Sections, tmpl.EditableSections())
+}
+
+func TestLoadProjectTemplate_TemplatesDir(t *testing.T) {
+	cfg := setupProjectTemplates(t, `{
+		"version": "1", "directory": "docs/adr", "template": "nygard", "templatesDir": "templates"
+	}`, map[string]string{"templates/rfc.md": rfcTemplate})
+
+	tmpl, err := adr.LoadProjectTemplate(cfg, "rfc")
+	require.NoError(t, err)
+	assert.Equal(t, "rfc", tmpl.Name)
+	assert.Contains(t, adr.ProjectTemplateNames(cfg), "rfc")
+}
+
+func TestLoadProjectTemplate_Builtin(t *testing.T) {
+	cfg := setupProjectTemplates(t, `{"version": "1", "directory": "docs/adr", "template": "nygard"}`, nil)
+
+	tmpl, err := adr.LoadProjectTemplate(cfg, "madr-minimal")
+	require.NoError(t, err)
+	assert.True(t, tmpl.Builtin)
+	builtin, _ := adr.TemplateSections("madr-minimal")
+	assert.Equal(t, builtin, tmpl.EditableSections())
+}
+
+func TestLoadProjectTemplate_CustomTemplateFile(t *testing.T) {
+	cfg := setupProjectTemplates(t, `{
+		"version": "1", "directory": "docs/adr", "template": "company", "templateFile": "company.md"
+	}`, map[string]string{"company.md": rfcTemplate})
+
+	tmpl, err := adr.LoadProjectTemplate(cfg, "company")
+	require.NoError(t, err)
+	assert.NotEmpty(t, tmpl.EditableSections())
+}
+
+func TestLoadProjectTemplate_Unknown(t *testing.T) {
+	cfg := setupProjectTemplates(t, `{"version": "1", "directory": "docs/adr", "template": "nygard"}`, nil)
+
+	_, err := adr.LoadProjectTemplate(cfg, "nope")
+	assert.ErrorContains(t, err, `unknown template "nope"`)
+}
+
+func TestLoadConfig_InvalidTemplateDefs(t *testing.T) {
+	tests := map[string]string{
+		"builtin schema": `{"nygard": {"file": "x.md", "sections": [{"key": "a", "heading": "A", "kind": "h2"}]}}`,
+		"no file":        `{"rfc": {}}`,
+		"escapes dir":    `{"rfc": {"file": "../x.md"}}`,
+		"not markdown":   `{"rfc": {"file": "x.txt"}}`,
+		"bad kind":       `{"rfc": {"file": "x.md", "sections": [{"key": "a", "heading": "A", "kind": "h4"}]}}`,
+		"duplicate key":  `{"rfc": {"file": "x.md", "sections": [{"key": "a", "heading": "A", "kind": "h2"}, {"key": "a", "heading": "B", "kind": "h2"}]}}`,
+	}
+	for name, templates := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			cfgJSON := `{"version": "1", "directory": "docs/adr", "template": "nygard", "templates": ` + templates + `}`
+			require.NoError(t, os.WriteFile(filepath.Join(dir, adr.ConfigFileName), []byte(cfgJSON), 0o644))
+
+			_, err := adr.LoadConfig(dir)
+			assert.ErrorIs(t, err, adr.ErrConfigInvalid)
+		})
+	}
+}
+
+func TestLoadConfig_RegistersProjectMetaFields(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "docs/adr"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "docs/adr/rfc.md"), []byte(rfcTemplate), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, adr.ConfigFileName), []byte(`{
+		"version": "1", "directory": "`+filepath.ToSlash(filepath.Join(root, "docs/adr"))+`", "template": "rfc",
+		"templates": {"rfc": {"file": "rfc.md"}}
+	}`), 0o644))
+
+	cfg, err := adr.LoadConfig(root)
+	require.NoError(t, err)
+
+	content := "---\nowner: Platform\n---\n\n# 1. X\n\nReviewer: Alice, Bob\n\n## Status\n\nAccepted\n"
+	meta := cfg.ExtractMetaFields(content)
+	assert.Equal(t, []string{"Platform"}, meta["owner"])
+	assert.Equal(t, []string{"Alice", "Bob"}, meta["reviewer"])
+
+	keys := make(map[string]bool)
+	for _, d := range cfg.MetaFieldDefs() {
+		keys[d.Key] = true
+	}
+	assert.True(t, keys["reviewer"])
+	assert.True(t, keys["owner"])
+	assert.True(t, keys["scope"], "built-in fields are kept")
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "docs/adr/0001-x.md"), []byte(content), 0o644))
+	records, err := cfg.Repository().List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, []string{"Alice", "Bob"}, records[0].Meta["reviewer"])
+
+	// Other projects, and the package-level built-ins, don't see the fields.
+	assert.Nil(t, adr.ExtractMetaFields(content)["reviewer"])
+	for _, d := range adr.AllMetaFieldDefs() {
+		assert.NotEqual(t, "reviewer", d.Key)
+	}
+	other := setupProjectTemplates(t, `{"version": "1", "directory": "docs/adr", "template": "nygard"}`, nil)
+	assert.Nil(t, other.ExtractMetaFields(content)["reviewer"])
+}
+
+func TestLoadProjectTemplate_ActiveBuiltinUsesCustomizedFile(t *testing.T) {
+	custom := "# Title\n\nDate:\n\n## Status\n\nProposed\n\n## Context\n\nWhy?\n\n## Decision\n\nWhat?\n\n## Legal Review\n\n{Sign-off by legal}\n\n---\nACME Corp confidential\n"
+	cfg := setupProjectTemplates(t, `{"version": "1", "directory": "docs/adr", "template": "nygard"}`,
+		map[string]string{"template.md": custom})
+
+	tmpl, err := adr.LoadProjectTemplate(cfg, "nygard")
+	require.NoError(t, err)
+	assert.True(t, tmpl.Builtin)
+	assert.Equal(t, "template.md", tmpl.Path)
+	assert.Equal(t, custom, tmpl.Content)
+
+	builtin, _ := adr.TemplateSections("nygard")
+	sections := tmpl.EditableSections()
+	require.Len(t, sections, 3)
+	assert.Equal(t, builtin[0], sections[0], "sections the built-in defines keep its definition")
+	assert.Equal(t, builtin[1], sections[1])
+	assert.Equal(t, adr.TemplateSectionDef{Key: "legal-review", Heading: "Legal Review", Kind: "h2", Placeholder: "{Sign-off by legal}\n\n---\nACME Corp confidential"}, sections[2])
+}
+
+func TestLoadProjectTemplate_ActiveBuiltinUnchangedKeepsSchema(t *testing.T) {
+	content, err := adr.TemplateContent("madr-full")
+	require.NoError(t, err)
+	cfg := setupProjectTemplates(t, `{"version": "1", "directory": "docs/adr", "template": "madr-full"}`,
+		map[string]string{"template.md": content})
+
+	tmpl, err := adr.LoadProjectTemplate(cfg, "madr-full")
+	require.NoError(t, err)
+	builtin, _ := adr.TemplateSections("madr-full")
+	assert.Equal(t, builtin, tmpl.EditableSections())
+}
+
+func TestLoadProjectTemplate_ActiveBuiltinMissingFile(t *testing.T) {
+	cfg := setupProjectTemplates(t, `{"version": "1", "directory": "docs/adr", "template": "nygard"}`, nil)
+
+	_, err := adr.LoadProjectTemplate(cfg, "nygard")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestTemplateChoices_AllBuiltinsWhenNoneDeclared(t *testing.T) {
+	cfg := &adr.Config{Directory: t.TempDir(), Template: "madr-full"}
+
+	assert.Equal(t, []string{"madr-full", "nygard", "nygard-scoped", "madr-minimal"}, adr.TemplateChoices(cfg))
+}
+
+func TestTemplateChoices_DeclaredSet(t *testing.T) {
+	cfg := setupProjectTemplates(t, `{
+		"version": "1", "directory": "docs/adr", "template": "nygard-scoped", "templatesDir": "templates",
+		"templates": {"madr-full": {"file": "big.md"}, "nygard-scoped": {"file": "small.md"}}
+	}`, map[string]string{"templates/rfc.md": rfcTemplate})
+
+	assert.Equal(t, []string{"nygard-scoped", "madr-full", "rfc"}, adr.TemplateChoices(cfg))
+}
+
+func TestLoadProjectTemplate_DeclaredBuiltinCopy(t *testing.T) {
+	content, err := adr.TemplateContent("madr-full")
+	require.NoError(t, err)
+	cfg := setupProjectTemplates(t, `{
+		"version": "1", "directory": "docs/adr", "template": "nygard-scoped",
+		"templates": {"madr-full": {"file": "big.md"}}
+	}`, map[string]string{"big.md": content + "\n## Sign-off\n\n{Who approved}\n"})
+
+	tmpl, err := adr.LoadProjectTemplate(cfg, "madr-full")
+	require.NoError(t, err)
+	assert.True(t, tmpl.Builtin)
+	assert.Equal(t, "big.md", tmpl.Path)
+	sections := tmpl.EditableSections()
+	assert.Equal(t, "sign-off", sections[len(sections)-1].Key)
+}
+
+func TestLoadProjectTemplate_UnknownIsErrUnknownTemplate(t *testing.T) {
+	cfg := &adr.Config{Directory: t.TempDir(), Template: "nygard"}
+
+	_, err := adr.LoadProjectTemplate(cfg, "nope")
+	assert.ErrorIs(t, err, adr.ErrUnknownTemplate)
+}
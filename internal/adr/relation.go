@@ -2,38 +2,29 @@ package adr
 
 import (
 	"fmt"
+	"path"
+	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
-)
 
-var relationsSectionPattern = regexp.MustCompile(`(?m)^## Relations[s]?[ \t]*$`)
+	"github.com/BobMali/adr-helper/internal/markdown"
+)
 
 // hasRelationsSection checks for a ## Relations heading.
 func hasRelationsSection(content string) bool {
-	return relationsSectionPattern.MatchString(content)
+	_, _, ok := findSection(content, 2, "Relations")
+	return ok
 }
 
 // extractRelationsSectionContent returns the text between ## Relations heading and the next ## heading (or EOF).
 func extractRelationsSectionContent(content string) string {
-	loc := relationsSectionPattern.FindStringIndex(content)
-	if loc == nil {
+	h, end, ok := findSection(content, 2, "Relations")
+	if !ok {
 		return ""
 	}
-
-	afterHeading := loc[1]
-	restAfterHeading := content[afterHeading:]
-
-	nextHeadingPattern := regexp.MustCompile(`(?m)\n\n## `)
-	nextLoc := nextHeadingPattern.FindStringIndex(restAfterHeading)
-
-	var sectionBody string
-	if nextLoc != nil {
-		sectionBody = restAfterHeading[:nextLoc[0]]
-	} else {
-		sectionBody = restAfterHeading
-	}
-
-	return strings.Trim(sectionBody, "\n")
+	return strings.Trim(content[h.End:end], "\n")
 }
 
 // insertRelationsSection inserts a new ## Relations section with the given initial line.
@@ -42,67 +33,41 @@ func extractRelationsSectionContent(content string) string {
 func insertRelationsSection(content, initialLine string) (string, error) {
 	section := "## Relations\n\n" + initialLine
 
-	if hasStatusSection(content) {
-		// Find ## Status
-		loc := statusSectionPattern.FindStringIndex(content)
-		afterStatus := loc[1]
-		rest := content[afterStatus:]
-
-		// Find the next ## heading after Status
-		nextHeadingPattern := regexp.MustCompile(`(?m)\n\n## `)
-		nextLoc := nextHeadingPattern.FindStringIndex(rest)
-
-		if nextLoc != nil {
-			// Insert between Status section and next heading
-			insertPoint := afterStatus + nextLoc[0]
-			return content[:insertPoint] + "\n\n" + section + content[insertPoint:], nil
-		}
-		// Status is the last section — append at end
-		return strings.TrimRight(content, "\n") + "\n\n" + section + "\n", nil
-	}
-
-	if hasFrontmatterStatus(content) {
-		// Find the first ## heading after the title line (# ...)
-		titlePattern := regexp.MustCompile(`(?m)^# .+$`)
-		titleLoc := titlePattern.FindStringIndex(content)
-		if titleLoc == nil {
+	insertAt := -1
+	switch {
+	case hasStatusSection(content):
+		_, insertAt, _ = findSection(content, 2, "Status")
+	case hasFrontmatterStatus(content):
+		hs := bodyHeadings(content)
+		title := slices.IndexFunc(hs, func(h markdown.Heading) bool { return h.Level == 1 })
+		if title < 0 {
 			return "", fmt.Errorf("no recognized ADR format: missing title")
 		}
-		afterTitle := content[titleLoc[1]:]
-		headingPattern := regexp.MustCompile(`(?m)\n\n## `)
-		headingLoc := headingPattern.FindStringIndex(afterTitle)
-		if headingLoc != nil {
-			insertPoint := titleLoc[1] + headingLoc[0]
-			return content[:insertPoint] + "\n\n" + section + content[insertPoint:], nil
+		insertAt = len(content)
+		for _, h := range hs[title+1:] {
+			if h.Level == 2 {
+				insertAt = h.Start
+				break
+			}
 		}
-		// No ## heading after title — append at end
-		return strings.TrimRight(content, "\n") + "\n\n" + section + "\n", nil
+	default:
+		return "", fmt.Errorf("no recognized ADR format: expected ## Status heading or status: in YAML frontmatter")
 	}
 
-	return "", fmt.Errorf("no recognized ADR format: expected ## Status heading or status: in YAML frontmatter")
+	if insertAt == len(content) {
+		// The section goes last — append at end
+		return strings.TrimRight(content, "\n") + "\n\n" + section + "\n", nil
+	}
+	return strings.TrimRight(content[:insertAt], "\n") + "\n\n" + section + "\n\n" + content[insertAt:], nil
 }
 
 // appendToRelationsSection appends a line to the existing ## Relations section.
 func appendToRelationsSection(content, line string) string {
-	existing := extractRelationsSectionContent(content)
-	newContent := existing + "\n" + line
-
-	// Replace the Relations section content
-	loc := relationsSectionPattern.FindStringIndex(content)
-	if loc == nil {
+	h, end, ok := findSection(content, 2, "Relations")
+	if !ok {
 		return content
 	}
-
-	afterHeading := loc[1]
-	rest := content[afterHeading:]
-
-	nextHeadingPattern := regexp.MustCompile(`(?m)\n\n## `)
-	nextLoc := nextHeadingPattern.FindStringIndex(rest)
-
-	if nextLoc != nil {
-		return content[:afterHeading] + "\n\n" + newContent + content[afterHeading+nextLoc[0]:]
-	}
-	return content[:afterHeading] + "\n\n" + newContent + "\n"
+	return replaceSectionBody(content, h, end, extractRelationsSectionContent(content)+"\n"+line)
 }
 
 // AddRelation adds a "Relates to [ADR-NNNN](filename)" line to the ## Relations section.
@@ -110,14 +75,127 @@ func appendToRelationsSection(content, line string) string {
 // Idempotent: skips if the link already exists.
 func AddRelation(content string, link ADRLink) (string, error) {
 	line := "Relates to " + formatADRLink(link) + "  "
+	text, style := normalizeText(content)
 
-	if hasRelationsSection(content) {
-		existing := extractRelationsSectionContent(content)
+	if hasRelationsSection(text) {
+		existing := extractRelationsSectionContent(text)
 		if strings.Contains(existing, formatADRLink(link)) {
 			return content, nil
 		}
-		return appendToRelationsSection(content, line), nil
+		return style.restore(appendToRelationsSection(text, line)), nil
+	}
+
+	updated, err := insertRelationsSection(text, line)
+	if err != nil {
+		return "", err
+	}
+	return style.restore(updated), nil
+}
+
+// RewriteADRLinks retargets every markdown link pointing at from.Filename so it
+// points at to.Filename, keeping any "./" prefix and "#fragment". A link
+// labelled from's label (e.g. "ADR-0003") is relabelled with to's; any other
+// label is left as written. Returns the updated content and the number of
+// links rewritten.
+func RewriteADRLinks(content string, from, to ADRLink) (string, int) {
+	pattern := regexp.MustCompile(`\[([^\]]*)\]\((\./)?` + regexp.QuoteMeta(from.Filename) + `(#[^)\s]*)?\)`)
+	oldLabel, newLabel := from.label(), to.label()
+
+	count := 0
+	result := pattern.ReplaceAllStringFunc(content, func(match string) string {
+		m := pattern.FindStringSubmatch(match)
+		label := m[1]
+		if label == oldLabel {
+			label = newLabel
+		}
+		count++
+		return "[" + label + "](" + m[2] + to.Filename + m[3] + ")"
+	})
+	return result, count
+}
+
+// RelationKind names how an ADR links to another.
+type RelationKind string
+
+const (
+	RelationSupersedes   RelationKind = "supersedes"
+	RelationSupersededBy RelationKind = "superseded-by"
+	RelationRelatesTo    RelationKind = "relates-to"
+)
+
+// Relation is a link from an ADR to another one, by number.
+type Relation struct {
+	Kind   RelationKind
+	Number int
+	// Path is the link's destination as written, without its fragment:
+	// relative to the linking ADR's file, and leading out of its root for an
+	// ADR in another root or project (see FileRepository.Relations).
+	Path string
+}
+
+// adrLinkNumberPattern matches a link to an ADR file whose label ends in the
+// ADR's number, e.g. "[ADR-0012](0012-x.md)" or "[API-007](API-007-x.md#status)",
+// capturing the number and the path.
+var adrLinkNumberPattern = regexp.MustCompile(`\[(?:[^\[\]]*[^\[\]\d])?(\d+)\]\(([^)\s]*\.md)(?:#[^)\s]*)?\)`)
+
+// ExtractRelations returns the links an ADR's content makes to other ADRs:
+// "Supersedes" and "Superseded by" links in its status (## Status section or
+// frontmatter status) and the links of its ## Relations section. Links are
+// recognized as written by this tool: a label ending in the ADR number, such
+// as "[ADR-0012]", pointing at a markdown file. Duplicates
+// are dropped; the order is as written.
+func ExtractRelations(content string) []Relation {
+	content, _ = normalizeText(content)
+	var status string
+	if hasStatusSection(content) {
+		status = extractStatusSectionContent(content)
+	} else if hasFrontmatterStatus(content) {
+		status = getFrontmatterStatusValue(content)
+	}
+
+	var relations []Relation
+	seen := make(map[Relation]bool)
+	add := func(kind RelationKind, digits, path string) {
+		n, err := strconv.Atoi(digits)
+		rel := Relation{Kind: kind, Number: n, Path: path}
+		if err != nil || seen[rel] {
+			return
+		}
+		seen[rel] = true
+		relations = append(relations, rel)
 	}
 
-	return insertRelationsSection(content, line)
+	// A link's kind is set by the closest "supersedes"/"superseded by" before
+	// it on its line, e.g. "accepted, supersedes [ADR-0001](…)".
+	for _, line := range strings.Split(status, "\n") {
+		lower := strings.ToLower(line)
+		for _, loc := range adrLinkNumberPattern.FindAllStringSubmatchIndex(line, -1) {
+			before := lower[:loc[0]]
+			by, plain := strings.LastIndex(before, "superseded by"), strings.LastIndex(before, "supersedes")
+			switch {
+			case by >= 0 && by > plain:
+				add(RelationSupersededBy, line[loc[2]:loc[3]], line[loc[4]:loc[5]])
+			case plain >= 0:
+				add(RelationSupersedes, line[loc[2]:loc[3]], line[loc[4]:loc[5]])
+			}
+		}
+	}
+	for _, m := range adrLinkNumberPattern.FindAllStringSubmatch(extractRelationsSectionContent(content), -1) {
+		add(RelationRelatesTo, m[1], m[2])
+	}
+	return relations
+}
+
+// Relations returns the relations (see ExtractRelations) of content, the ADR
+// stored in file, to other ADRs in r. Links leading out of r's directory, to
+// an ADR of another root ("[payments:ADR-0001](../../services/…)") or mounted
+// project, are left out: their numbers belong to another sequence.
+func (r *FileRepository) Relations(file, content string) []Relation {
+	var local []Relation
+	for _, rel := range ExtractRelations(content) {
+		if filepath.IsLocal(filepath.FromSlash(path.Join(path.Dir(file), rel.Path))) {
+			local = append(local, rel)
+		}
+	}
+	return local
 }
@@ -82,3 +82,79 @@ func TestAddRelation_StatusIsLastSection(t *testing.T) {
 	require.NoError(t, err)
 	assert.Contains(t, result, "## Status\n\nAccepted\n\n## Relations\n\nRelates to [ADR-0003](0003-use-chi.md)  \n")
 }
+
+func TestAddRelation_IgnoresRelationsHeadingInCodeFence(t *testing.T) {
+	content := "# 1. Use Go\n\n## Status\n\nAccepted\n\n## Context\n\n```\n## Relations\n```\n"
+	link := ADRLink{Number: 3, Filename: "0003-use-chi.md"}
+
+	result, err := AddRelation(content, link)
+	require.NoError(t, err)
+	assert.Equal(t, "# 1. Use Go\n\n## Status\n\nAccepted\n\n## Relations\n\nRelates to [ADR-0003](0003-use-chi.md)  \n\n## Context\n\n```\n## Relations\n```\n", result)
+	assert.Len(t, ExtractRelations(result), 1)
+}
+
+func TestRewriteADRLinks_RetargetsAndRelabels(t *testing.T) {
+	content := "Supersedes [ADR-0012](0012-old.md)  \nSee [notes](./0012-old.md#decision) and [ADR-0003](0003-x.md).\n"
+
+	result, n := RewriteADRLinks(content,
+		ADRLink{Number: 12, Filename: "0012-old.md"},
+		ADRLink{Number: 13, Filename: "0013-old.md"})
+
+	assert.Equal(t, 2, n)
+	assert.Contains(t, result, "Supersedes [ADR-0013](0013-old.md)")
+	assert.Contains(t, result, "[notes](./0013-old.md#decision)")
+	assert.Contains(t, result, "[ADR-0003](0003-x.md)")
+}
+
+func TestRewriteADRLinks_NoMatch(t *testing.T) {
+	content := "[ADR-0012](0012-old.md-backup)\n"
+
+	result, n := RewriteADRLinks(content,
+		ADRLink{Number: 12, Filename: "0012-old.md"},
+		ADRLink{Number: 12, Filename: "0012-new.md"})
+
+	assert.Equal(t, 0, n)
+	assert.Equal(t, content, result)
+}
+
+func TestExtractRelations_Nygard(t *testing.T) {
+	content := "# 3. C\n\n## Status\n\nSuperseded by [ADR-0005](0005-e.md)  \n\nSupersedes [ADR-0001](0001-a.md)  \nSupersedes [ADR-0002](0002-b.md)  \n\n## Relations\n\nRelates to [ADR-0004](0004-d.md) (amended by)  \nRelates to [ADR-0004](0004-d.md)  \n\n## Context\n\nSee [ADR-0009](0009-i.md).\n"
+	assert.Equal(t, []Relation{
+		{Kind: RelationSupersededBy, Number: 5, Path: "0005-e.md"},
+		{Kind: RelationSupersedes, Number: 1, Path: "0001-a.md"},
+		{Kind: RelationSupersedes, Number: 2, Path: "0002-b.md"},
+		{Kind: RelationRelatesTo, Number: 4, Path: "0004-d.md"},
+	}, ExtractRelations(content))
+}
+
+func TestExtractRelations_Frontmatter(t *testing.T) {
+	content := "---\nstatus: \"accepted, supersedes [ADR-0001](0001-a.md)\"\n---\n\n# 2. B\n"
+	assert.Equal(t, []Relation{{Kind: RelationSupersedes, Number: 1, Path: "0001-a.md"}}, ExtractRelations(content))
+	assert.Empty(t, ExtractRelations("# 1. A\n\n## Status\n\nAccepted\n"))
+}
+
+func TestFileRepository_Relations(t *testing.T) {
+	content := "# 2. B\n\n## Status\n\nAccepted\n\nSupersedes [payments:ADR-0001](../../../services/pay/adr/0001-a.md)  \n\n" +
+		"## Relations\n\nRelates to [ADR-0001](../0001-a.md)  \nRelates to [billing/ADR-0003](../../../../billing/docs/adr/0003-c.md)  \n"
+	assert.Equal(t, []Relation{{Kind: RelationRelatesTo, Number: 1, Path: "../0001-a.md"}},
+		NewFileRepository(t.TempDir()).Relations("platform/0002-b.md", content))
+}
+
+func TestExtractRelations_ConfiguredLabels(t *testing.T) {
+	content := "# 3. C\n\n## Status\n\nAccepted\n\nSupersedes [API-001](API-001-a.md)  \n\n" +
+		"## Relations\n\nRelates to [Decision 4](2024-05-01-d.md#context)  \nSee [RFC 7231](https://www.rfc-editor.org/rfc/rfc7231)  \n"
+	assert.Equal(t, []Relation{
+		{Kind: RelationSupersedes, Number: 1, Path: "API-001-a.md"},
+		{Kind: RelationRelatesTo, Number: 4, Path: "2024-05-01-d.md"},
+	}, ExtractRelations(content))
+}
+
+func TestRewriteADRLinks_ConfiguredLabels(t *testing.T) {
+	naming := Naming{Prefix: "API-", NumberWidth: 3}
+	content := "Supersedes [API-012](API-012-old.md)  \nSee [ADR-0012](API-012-old.md).\n"
+
+	result, n := RewriteADRLinks(content, naming.Link(12, "API-012-old.md"), naming.Link(13, "API-013-old.md"))
+
+	assert.Equal(t, 2, n)
+	assert.Equal(t, "Supersedes [API-013](API-013-old.md)  \nSee [ADR-0012](API-013-old.md).\n", result)
+}
@@ -3,11 +3,13 @@ package adr
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
+
+	"github.com/BobMali/adr-helper/internal/markdown"
 )
 
 var (
-	headingPattern   = regexp.MustCompile(`(?m)^# .*$`)
 	dateUpperPattern = regexp.MustCompile(`(?m)^Date:.*$`)
 	dateLowerPattern = regexp.MustCompile(`(?m)^date:.*$`)
 	metaValueNewline = regexp.MustCompile(`[\r\n]+`)
@@ -30,115 +32,201 @@ func metaFieldPattern(label string) *regexp.Regexp {
 // title-block field. Returns (result, found); found is false when no matching
 // line exists (e.g. a template without that field).
 func ReplaceMetaField(content, label, value string) (string, bool) {
-	sanitized := strings.TrimSpace(metaValueNewline.ReplaceAllString(value, " "))
+	sanitized := singleLine(value)
 	pattern := metaFieldPattern(label)
+	text, style := normalizeText(content)
 
 	found := false
-	result := pattern.ReplaceAllStringFunc(content, func(match string) string {
+	result := pattern.ReplaceAllStringFunc(text, func(match string) string {
 		if found {
 			return match
 		}
 		found = true
 		return label + ": " + sanitized
 	})
-	return result, found
+	if !found {
+		return content, false
+	}
+	return style.restore(result), true
 }
 
-// ReplaceSectionContent replaces the body text under the first matching
-// heading (## or ###) with newBody. Returns (result, found).
-// The heading match is case-insensitive on the heading text.
-func ReplaceSectionContent(content, heading, newBody string) (string, bool) {
-	lines := strings.Split(content, "\n")
-	lowerHeading := strings.ToLower(strings.TrimSpace(heading))
-
-	// Find the heading line
-	headingIdx := -1
-	headingLevel := 0
-	for i, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		level, text := parseHeadingLine(trimmed)
-		if level > 0 && strings.ToLower(text) == lowerHeading {
-			headingIdx = i
-			headingLevel = level
-			break
+// SetFrontmatterField sets the top-level YAML frontmatter key to value,
+// replacing its line (and the indented lines of a block value under it) or
+// adding it at the end of the block. Newlines in value are collapsed to single
+// spaces, and the value is double-quoted when YAML would not read it back as
+// the same plain string. Returns (result, found); found is false when content
+// has no frontmatter.
+func SetFrontmatterField(content, key, value string) (string, bool) {
+	return setFrontmatterLines(content, key, []string{key + ": " + yamlScalar(singleLine(value))})
+}
+
+// SetFrontmatterList is the list counterpart of SetFrontmatterField and of
+// ExtractMetaFields' frontmatter reading: one value is written as a scalar,
+// several as a block list ("key:" followed by "  - value" lines). Blank values
+// are dropped; with none left the key is set to "".
+func SetFrontmatterList(content, key string, values []string) (string, bool) {
+	var items []string
+	for _, v := range values {
+		if v = singleLine(v); v != "" {
+			items = append(items, v)
 		}
 	}
+	if len(items) <= 1 {
+		return SetFrontmatterField(content, key, strings.Join(items, ""))
+	}
+	lines := []string{key + ":"}
+	for _, v := range items {
+		lines = append(lines, "  - "+yamlScalar(v))
+	}
+	return setFrontmatterLines(content, key, lines)
+}
 
-	if headingIdx < 0 {
+// setFrontmatterLines replaces the line of the top-level frontmatter key, and
+// the indented or "- " list lines under it, with repl; a missing key is added
+// at the end of the block.
+func setFrontmatterLines(content, key string, repl []string) (string, bool) {
+	text, style := normalizeText(content)
+	body := bodyAfterFrontmatter(text)
+	if len(body) == len(text) {
 		return content, false
 	}
+	fm := text[:len(text)-len(body)]
 
-	// Find the end of this section: next heading of equal or higher level (lower number), or EOF
-	bodyStart := headingIdx + 1
-	bodyEnd := len(lines)
-	for i := bodyStart; i < len(lines); i++ {
-		trimmed := strings.TrimSpace(lines[i])
-		level, _ := parseHeadingLine(trimmed)
-		if level > 0 && level <= headingLevel {
-			bodyEnd = i
-			break
+	lines := strings.Split(fm, "\n")
+	// lines[0] is the opening "---"; the closing one is the last non-empty line.
+	closing := len(lines) - 1
+	for closing > 0 && strings.TrimSpace(lines[closing]) != "---" {
+		closing--
+	}
+	for i := 1; i < closing; i++ {
+		m := frontmatterKeyLinePattern.FindStringSubmatch(lines[i])
+		if m == nil || m[1] != key {
+			continue
+		}
+		end := i + 1
+		for end < closing && isFrontmatterContinuation(lines[end]) {
+			end++
 		}
+		lines = slices.Replace(lines, i, end, repl...)
+		return style.restore(strings.Join(lines, "\n") + body), true
 	}
+	lines = slices.Insert(lines, closing, repl...)
+	return style.restore(strings.Join(lines, "\n") + body), true
+}
 
-	// Build the result: heading line + blank line + newBody + blank line + rest
-	var b strings.Builder
-	for i := 0; i <= headingIdx; i++ {
-		b.WriteString(lines[i])
-		b.WriteString("\n")
-	}
-	b.WriteString("\n")
-	b.WriteString(newBody)
-	b.WriteString("\n")
-	if bodyEnd < len(lines) {
-		b.WriteString("\n")
-		for i := bodyEnd; i < len(lines); i++ {
-			b.WriteString(lines[i])
-			if i < len(lines)-1 {
-				b.WriteString("\n")
-			}
+// isFrontmatterContinuation reports whether line belongs to the value of the
+// key above it: it is indented, or a block list item at the key's indentation.
+func isFrontmatterContinuation(line string) bool {
+	trimmed := strings.TrimRight(line, "\r")
+	return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") ||
+		trimmed == "-" || strings.HasPrefix(trimmed, "- ")
+}
+
+// singleLine trims v and collapses its newlines to single spaces.
+func singleLine(v string) string {
+	return strings.TrimSpace(metaValueNewline.ReplaceAllString(v, " "))
+}
+
+// yamlScalar returns v as a YAML scalar: plain when YAML reads it back as the
+// same string, double-quoted otherwise.
+func yamlScalar(v string) string {
+	if v != "" && !strings.ContainsAny(v[:1], "-?:,[]{}#&*!|>'\"%@`") &&
+		!strings.Contains(v, ": ") && !strings.Contains(v, " #") && !strings.HasSuffix(v, ":") {
+		switch strings.ToLower(v) {
+		case "true", "false", "yes", "no", "on", "off", "null", "~":
+		default:
+			return v
 		}
 	}
-
-	return b.String(), true
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v) + `"`
 }
 
-// parseHeadingLine returns (level, text) for a markdown heading line.
-// e.g. "## Context" -> (2, "Context"), "### Foo" -> (3, "Foo").
-// Returns (0, "") if not a heading.
-func parseHeadingLine(line string) (int, string) {
-	if !strings.HasPrefix(line, "#") {
-		return 0, ""
+// ReplaceSectionContent replaces the body text under the first matching
+// heading (## or ###) with newBody, up to the next heading of any level, so
+// the subsections of a "##" section are kept. Returns (result, found).
+// The heading match is case-insensitive on the heading text; headings in code
+// blocks and HTML comments are not matched (see bodyHeadings).
+func ReplaceSectionContent(content, heading, newBody string) (string, bool) {
+	text, style := normalizeText(content)
+	hs := bodyHeadings(text)
+	i := slices.IndexFunc(hs, func(h markdown.Heading) bool {
+		return strings.EqualFold(h.Text, strings.TrimSpace(heading))
+	})
+	if i < 0 {
+		return content, false
 	}
-	level := 0
-	for _, ch := range line {
-		if ch == '#' {
-			level++
-		} else {
-			break
+	end := len(text)
+	if i+1 < len(hs) {
+		end = hs[i+1].Start
+	}
+	return style.restore(replaceSectionBody(text, hs[i], end, normalizeLineEndings(newBody))), true
+}
+
+// ApplySections writes user-provided section values into rendered template
+// content. values is keyed by TemplateSectionDef.Key; defs with no value (or a
+// blank one) are skipped so the template's placeholder text stays in place, as
+// are defs whose heading or label the template doesn't contain. "meta" kinds are
+// title-block lines (e.g. Scope), "frontmatter" kinds YAML keys taking a
+// comma- or newline-separated list (see SplitListValue); everything else is a
+// "## Heading" body section.
+func ApplySections(content string, defs []TemplateSectionDef, values map[string]string) string {
+	for _, def := range defs {
+		text, ok := values[def.Key]
+		if !ok || strings.TrimSpace(text) == "" {
+			continue
+		}
+		var replaced string
+		var found bool
+		switch def.Kind {
+		case "meta":
+			replaced, found = ReplaceMetaField(content, def.Heading, text)
+		case "frontmatter":
+			replaced, found = SetFrontmatterList(content, def.Key, SplitListValue(text))
+		default:
+			replaced, found = ReplaceSectionContent(content, def.Heading, text)
+		}
+		if found {
+			content = replaced
 		}
 	}
-	if level > 6 {
-		return 0, ""
+	return content
+}
+
+// ReplaceHeading replaces the first top-level "# " heading (or setext "==="
+// heading) with the canonical "# N. Title" form. YAML frontmatter is skipped,
+// so a "# comment" line in it is left alone, as are "# " lines in code blocks.
+// Returns (result, found); found is false when the content has no top-level
+// heading.
+func ReplaceHeading(content string, number int, title string) (string, bool) {
+	text, style := normalizeText(content)
+	h, ok := titleHeading(text)
+	if !ok {
+		return content, false
+	}
+	heading := fmt.Sprintf("# %d. %s", number, title)
+	if strings.HasSuffix(text[:h.End], "\n") {
+		heading += "\n"
 	}
-	text := strings.TrimSpace(line[level:])
-	return level, text
+	return style.restore(text[:h.Start] + heading + text[h.End:]), true
+}
+
+// titleHeading returns the first level-1 heading of content's body.
+func titleHeading(content string) (markdown.Heading, bool) {
+	for _, h := range bodyHeadings(content) {
+		if h.Level == 1 {
+			return h, true
+		}
+	}
+	return markdown.Heading{}, false
 }
 
 // RenderTemplate replaces the first top-level heading and date lines in template content
 // with values from the given ADR record.
 func RenderTemplate(content string, record *ADR) string {
-	heading := fmt.Sprintf("# %d. %s", record.Number, record.Title)
 	dateStr := record.Date.Format("2006-01-02")
+	content, style := normalizeText(content)
 
-	// Replace only the first top-level heading
-	replaced := false
-	result := headingPattern.ReplaceAllStringFunc(content, func(match string) string {
-		if !replaced {
-			replaced = true
-			return heading
-		}
-		return match
-	})
+	result, _ := ReplaceHeading(content, record.Number, record.Title)
 
 	// Replace Date: and date: lines
 	result = dateUpperPattern.ReplaceAllString(result, "Date: "+dateStr)
@@ -151,5 +239,5 @@ func RenderTemplate(content string, record *ADR) string {
 		result = replaceFrontmatterStatus(result, strings.ToLower(record.Status.String()))
 	}
 
-	return result
+	return style.restore(result)
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/BobMali/adr-helper/internal/adr"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRenderTemplate_NygardFormat(t *testing.T) {
@@ -107,6 +108,59 @@ func TestReplaceSectionContent_ReplacesH3Subsection(t *testing.T) {
 	assert.Contains(t, result, "Some confirmation.")
 }
 
+func TestReplaceSectionContent_KeepsSubsectionsOfH2(t *testing.T) {
+	content := "## Decision Outcome\n\nChosen option.\n\n### Consequences\n\nOld consequences.\n\n## More Information\n\nNone.\n"
+	result, found := adr.ReplaceSectionContent(content, "Decision Outcome", "Chosen option: Go.")
+	assert.True(t, found)
+	assert.Equal(t, "## Decision Outcome\n\nChosen option: Go.\n\n### Consequences\n\nOld consequences.\n\n## More Information\n\nNone.\n", result)
+}
+
+func TestReplaceSectionContent_IgnoresHeadingsInCodeFence(t *testing.T) {
+	content := "# Title\n\n## Context\n\n```\n## Decision\n```\n\n## Decision\n\nOld decision.\n"
+	result, found := adr.ReplaceSectionContent(content, "Decision", "New decision.")
+	assert.True(t, found)
+	assert.Equal(t, "# Title\n\n## Context\n\n```\n## Decision\n```\n\n## Decision\n\nNew decision.\n", result)
+}
+
+func TestSetFrontmatterField(t *testing.T) {
+	content := "---\nstatus: \"accepted\"\ndecision-makers:\n  - Alice\n  - Bob\ninformed: {placeholder}\n---\n\n# 1. A\n\ninformed: body text\n"
+
+	result, found := adr.SetFrontmatterField(content, "decision-makers", "Carol,\nDave")
+	assert.True(t, found)
+	assert.Equal(t, "---\nstatus: \"accepted\"\ndecision-makers: Carol, Dave\ninformed: {placeholder}\n---\n\n# 1. A\n\ninformed: body text\n", result)
+
+	result, found = adr.SetFrontmatterField(result, "informed", "Team: Platform")
+	assert.True(t, found)
+	assert.Contains(t, result, "\ninformed: \"Team: Platform\"\n---\n")
+	assert.Contains(t, result, "\ninformed: body text\n")
+
+	result, found = adr.SetFrontmatterField(result, "consulted", "yes")
+	assert.True(t, found)
+	assert.Contains(t, result, "\ninformed: \"Team: Platform\"\nconsulted: \"yes\"\n---\n")
+
+	_, found = adr.SetFrontmatterField("# 1. A\n", "consulted", "Eve")
+	assert.False(t, found)
+}
+
+func TestSetFrontmatterList(t *testing.T) {
+	content := "---\nstatus: accepted\ndecision-makers: {placeholder}\ninformed:\n- Old\n---\n\n# 1. A\n"
+
+	result, found := adr.SetFrontmatterList(content, "decision-makers", []string{"Alice", " ", "- Bob"})
+	assert.True(t, found)
+	assert.Equal(t, "---\nstatus: accepted\ndecision-makers:\n  - Alice\n  - \"- Bob\"\ninformed:\n- Old\n---\n\n# 1. A\n", result)
+
+	result, found = adr.SetFrontmatterList(result, "informed", []string{"Eve"})
+	assert.True(t, found)
+	assert.Contains(t, result, "\n  - \"- Bob\"\ninformed: Eve\n---\n")
+
+	result, found = adr.SetFrontmatterList(result, "decision-makers", nil)
+	assert.True(t, found)
+	assert.Equal(t, "---\nstatus: accepted\ndecision-makers: \"\"\ninformed: Eve\n---\n\n# 1. A\n", result)
+
+	_, found = adr.SetFrontmatterList("# 1. A\n", "informed", []string{"Eve", "Frank"})
+	assert.False(t, found)
+}
+
 func TestReplaceSectionContent_ReturnsFalseWhenNotFound(t *testing.T) {
 	content := "# Title\n\n## Context\n\nSome text.\n"
 	result, found := adr.ReplaceSectionContent(content, "Nonexistent", "New text.")
@@ -189,3 +243,74 @@ func TestReplaceMetaField_NotFound(t *testing.T) {
 	assert.False(t, found)
 	assert.Equal(t, content, result)
 }
+
+func TestApplySections_WritesMetaAndBodySections(t *testing.T) {
+	content := "# 1. T\n\nScope:\n\n## Context\n\nplaceholder\n\n## Decision\n\nkeep me\n"
+	defs := []adr.TemplateSectionDef{
+		{Key: "scope", Heading: "Scope", Kind: "meta"},
+		{Key: "context", Heading: "Context", Kind: "h2"},
+		{Key: "decision", Heading: "Decision", Kind: "h2"},
+	}
+
+	result := adr.ApplySections(content, defs, map[string]string{
+		"scope":    "API",
+		"context":  "Real context",
+		"decision": "   ",
+		"unknown":  "ignored",
+	})
+
+	assert.Contains(t, result, "Scope: API")
+	assert.Contains(t, result, "## Context\n\nReal context\n\n## Decision")
+	assert.Contains(t, result, "## Decision\n\nkeep me\n")
+	assert.NotContains(t, result, "ignored")
+}
+
+func TestApplySections_WritesFrontmatterLists(t *testing.T) {
+	content, err := adr.TemplateContent("madr-full")
+	require.NoError(t, err)
+	defs, err := adr.TemplateSections("madr-full")
+	require.NoError(t, err)
+
+	result := adr.ApplySections(content, defs, map[string]string{
+		"decision-makers": "Alice, Bob",
+		"informed":        "Everyone",
+	})
+
+	assert.Contains(t, result, "\ndecision-makers:\n  - Alice\n  - Bob\nconsulted: {")
+	assert.Contains(t, result, "\ninformed: Everyone\n---\n")
+	meta := adr.ExtractMetaFields(result)
+	assert.Equal(t, []string{"Alice", "Bob"}, meta["decision-makers"])
+	assert.NotContains(t, meta, "consulted")
+}
+
+func TestReplaceHeading_ReplacesFirstTopLevelHeadingOnly(t *testing.T) {
+	content := "# 3. Old\n\n## Context\n\n# Not this one\n"
+
+	result, found := adr.ReplaceHeading(content, 2, "New Title")
+
+	assert.True(t, found)
+	assert.Equal(t, "# 2. New Title\n\n## Context\n\n# Not this one\n", result)
+}
+
+func TestReplaceHeading_SkipsFrontmatterComment(t *testing.T) {
+	content := "---\n# optional metadata\nstatus: accepted\n---\n\n# {short title}\n"
+
+	result, found := adr.ReplaceHeading(content, 4, "Use Chi")
+
+	assert.True(t, found)
+	assert.Equal(t, "---\n# optional metadata\nstatus: accepted\n---\n\n# 4. Use Chi\n", result)
+}
+
+func TestReplaceHeading_SkipsFencedHeading(t *testing.T) {
+	content := "```\n# not a title\n```\n\n# NUMBER. TITLE\n"
+	result, ok := adr.ReplaceHeading(content, 3, "Use Fences")
+	assert.True(t, ok)
+	assert.Equal(t, "```\n# not a title\n```\n\n# 3. Use Fences\n", result)
+}
+
+func TestReplaceHeading_NoHeading(t *testing.T) {
+	result, found := adr.ReplaceHeading("## Context\n", 1, "T")
+
+	assert.False(t, found)
+	assert.Equal(t, "## Context\n", result)
+}
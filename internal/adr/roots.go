@@ -0,0 +1,386 @@
+package adr
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrUnknownRoot is returned for a root name the config doesn't define.
+	ErrUnknownRoot = errors.New("unknown root")
+	// ErrRootRequired is returned when several roots are configured and none
+	// was chosen.
+	ErrRootRequired = errors.New("root required")
+)
+
+// rootNamePattern is what root names look like: they qualify ADR IDs
+// ("payments:12"), so they can't contain a colon.
+var rootNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// RootDef declares one of several named ADR directories in a project, e.g. one
+// per service of a monorepo. Each root has its own number sequence; Template,
+// TemplateFile and Scopes replace the top-level settings for its ADRs, and
+// everything else (naming, discovery, project templates) is shared. Template
+// files, TemplatesDir and PartialsDir are therefore resolved against the
+// top-level Directory, or the project directory when there is none, for every
+// root alike.
+type RootDef struct {
+	Name      string `json:"name"`
+	Directory string `json:"directory"`
+	// Template and TemplateFile default to the top-level ones.
+	Template     string `json:"template,omitempty"`
+	TemplateFile string `json:"templateFile,omitempty"`
+	// Scopes is the root's own scope vocabulary.
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// validateRoots reports unnamed, misnamed and duplicate roots, and roots
+// whose directory is, contains or lies inside another root's or the top-level
+// Directory: a root's ADRs would otherwise be listed, numbered and rewritten
+// by two roots.
+func validateRoots(c *Config) error {
+	names := make(map[string]bool, len(c.Roots))
+	var dirs []string
+	if c.Directory != "" {
+		dirs = append(dirs, filepath.Clean(c.Directory))
+	}
+	for _, def := range c.Roots {
+		if !rootNamePattern.MatchString(def.Name) {
+			return fmt.Errorf("root name %q must be letters, digits, '.', '_' or '-'", def.Name)
+		}
+		if names[def.Name] {
+			return fmt.Errorf("root %q is defined twice", def.Name)
+		}
+		names[def.Name] = true
+		if def.Directory == "" {
+			return fmt.Errorf("root %q: directory must not be empty", def.Name)
+		}
+		dir := filepath.Clean(def.Directory)
+		for _, other := range dirs {
+			if nestedDir(dir, other) || nestedDir(other, dir) {
+				return fmt.Errorf("root %q: directory %q overlaps another root's %q", def.Name, def.Directory, other)
+			}
+		}
+		dirs = append(dirs, dir)
+	}
+	return nil
+}
+
+// nestedDir reports whether dir is parent or a directory below it.
+func nestedDir(dir, parent string) bool {
+	rel, err := filepath.Rel(parent, dir)
+	return err == nil && filepath.IsLocal(rel)
+}
+
+// Root returns the config of the root with the given name: a copy of the
+// project config with the root's directory, template and scopes. An empty name
+// selects the default root, which is the top-level Directory, or the only root
+// when there is no top-level Directory. On a root's config, Root looks up the
+// project's roots.
+func (c *Config) Root(name string) (*Config, error) {
+	if c.parent != nil {
+		return c.parent.Root(name)
+	}
+	if name == "" {
+		switch {
+		case c.Directory != "":
+			return c, nil
+		case len(c.Roots) == 1:
+			return c.Root(c.Roots[0].Name)
+		default:
+			return nil, fmt.Errorf("several ADR roots are configured (%s): %w", strings.Join(c.RootNames(), ", "), ErrRootRequired)
+		}
+	}
+	for _, def := range c.Roots {
+		if def.Name != name {
+			continue
+		}
+		root := *c
+		root.Directory = def.Directory
+		root.Scopes = def.Scopes
+		if def.Template != "" {
+			root.Template = def.Template
+		}
+		if def.TemplateFile != "" {
+			root.TemplateFile = def.TemplateFile
+		}
+		root.root, root.parent = name, c
+		return &root, nil
+	}
+	return nil, fmt.Errorf("root %q: %w", name, ErrUnknownRoot)
+}
+
+// templateDir returns the directory project templates, partials and template
+// files are resolved against: the top-level Directory, which for a root's
+// config is its project's, or the project directory when all ADRs live in
+// roots.
+func (c *Config) templateDir() string {
+	project := c
+	if c.parent != nil {
+		project = c.parent
+	}
+	if project.Directory != "" {
+		return project.Directory
+	}
+	return project.projectDir
+}
+
+// RootName returns the name of the root c is the config of, or "" for the
+// default root.
+func (c *Config) RootName() string {
+	return c.root
+}
+
+// RootNames returns the names of the project's roots, in config order.
+func (c *Config) RootNames() []string {
+	if c.parent != nil {
+		return c.parent.RootNames()
+	}
+	names := make([]string, len(c.Roots))
+	for i, def := range c.Roots {
+		names[i] = def.Name
+	}
+	return names
+}
+
+// AllRoots returns the config of every root: the top-level Directory's first,
+// when set, then the named roots in config order.
+func (c *Config) AllRoots() ([]*Config, error) {
+	if c.parent != nil {
+		return c.parent.AllRoots()
+	}
+	var roots []*Config
+	if c.Directory != "" {
+		roots = append(roots, c)
+	}
+	for _, def := range c.Roots {
+		root, err := c.Root(def.Name)
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, root)
+	}
+	return roots, nil
+}
+
+// linkingDirs returns every other ADR directory whose ADRs may link to the
+// ADRs of c's root: the project's other roots and, when federated (see
+// Federate), the roots of the other projects.
+func (c *Config) linkingDirs() ([]linkingDir, error) {
+	own, err := filepath.Abs(c.Directory)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{own: true}
+	var dirs []linkingDir
+	for _, project := range append([]*Config{c}, c.federation...) {
+		roots, err := project.AllRoots()
+		if err != nil {
+			return nil, err
+		}
+		for _, root := range roots {
+			abs, err := filepath.Abs(root.Directory)
+			if err != nil {
+				return nil, err
+			}
+			if seen[abs] {
+				continue
+			}
+			seen[abs] = true
+			dirs = append(dirs, linkingDir{
+				repo: root.Repository(),
+				link: func(from string, number int, filename string) ADRLink {
+					return root.LinkTo(from, c, number, filename)
+				},
+			})
+		}
+	}
+	return dirs, nil
+}
+
+// linkingDir is an ADR directory, another root's or project's, whose ADRs may
+// link to a repository's (see FileRepository.rewriteInboundLinks).
+type linkingDir struct {
+	repo *FileRepository
+	// link returns the link written in repo's ADR stored in from to the
+	// repository's ADR number stored in filename.
+	link func(from string, number int, filename string) ADRLink
+}
+
+// Resolve returns the config of the root id belongs to: c itself for an
+// unqualified ID.
+func (c *Config) Resolve(id ID) (*Config, error) {
+	if id.Root == "" || id.Root == c.root {
+		return c, nil
+	}
+	return c.Root(id.Root)
+}
+
+// LinkTo returns the link written in the ADR stored in from, in c's root, to
+// ADR number stored in filename, in target's root. Within a root it is the
+// naming's link; across roots the label is qualified by the target's root
+// name ("payments:ADR-0012"), and across mounted projects (see Mount) by the
+// target's project name too ("billing/ADR-0003"), with the path leading out
+// of c's directory.
+func (c *Config) LinkTo(from string, target *Config, number int, filename string) ADRLink {
+	if target.root == c.root && target.project == c.project {
+		return c.Naming().Link(number, RelativePath(from, filename))
+	}
+	link := target.Naming().Link(number, filename)
+	if target.root != "" {
+		link.Label = target.root + ":" + link.Label
+	}
+	if target.project != c.project && target.project != "" {
+		link.Label = target.project + "/" + link.Label
+	}
+	source, err := filepath.Abs(filepath.Join(c.Directory, filepath.FromSlash(path.Dir(from))))
+	if err != nil {
+		return link
+	}
+	dest, err := filepath.Abs(filepath.Join(target.Directory, filepath.FromSlash(filename)))
+	if err != nil {
+		return link
+	}
+	if rel, err := filepath.Rel(source, dest); err == nil {
+		link.Filename = filepath.ToSlash(rel)
+	}
+	return link
+}
+
+// LinkPath returns the path of the file a relative link dest, written in the
+// ADR stored in from in c's root, points at. It reports false for URLs and
+// absolute paths.
+func (c *Config) LinkPath(from, dest string) (string, bool) {
+	if dest == "" || strings.Contains(dest, ":") || strings.HasPrefix(dest, "/") {
+		return "", false
+	}
+	return filepath.Join(c.Directory, filepath.FromSlash(path.Dir(from)), filepath.FromSlash(dest)), true
+}
+
+// Locate returns the ADR stored at file, a file path, in whichever of the
+// project's roots holds it. The file must be named like an ADR but needn't
+// exist.
+func (c *Config) Locate(file string) (ID, bool) {
+	target, err := filepath.Abs(file)
+	if err != nil {
+		return ID{}, false
+	}
+	roots, err := c.AllRoots()
+	if err != nil {
+		return ID{}, false
+	}
+	for _, root := range roots {
+		dir, err := filepath.Abs(root.Directory)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(dir, target)
+		if err != nil || !filepath.IsLocal(rel) {
+			continue
+		}
+		if n, ok := root.Repository().FileNumber(filepath.ToSlash(rel)); ok {
+			return ID{Root: root.root, Number: n}, true
+		}
+	}
+	return ID{}, false
+}
+
+// Relate adds bidirectional "Relates to" links between ADR number in c's root
+// and ADR targetNum in target's root, which may be another root or another
+// mounted project's (see LinkTo). It returns the updated ADR number; use
+// FileRepository.AddRelation within a root. Both files are written as one
+// transaction journaled in c's root; recovering it after a crash needs
+// target's root among the directories linking to c's (see Federate).
+func (c *Config) Relate(number int, target *Config, targetNum int) (*ADR, error) {
+	repo := c.Repository()
+	if _, err := repo.Recover(); err != nil {
+		return nil, err
+	}
+	sourceFile, err := repo.FindFile(number)
+	if err != nil {
+		return nil, err
+	}
+	targetFile, err := target.Repository().FindFile(targetNum)
+	if err != nil {
+		return nil, err
+	}
+
+	sourcePath := filepath.Join(c.Directory, filepath.FromSlash(sourceFile))
+	targetPath := filepath.Join(target.Directory, filepath.FromSlash(targetFile))
+	sourceContent, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", sourceFile, err)
+	}
+	targetContent, err := os.ReadFile(targetPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", targetFile, err)
+	}
+
+	updatedSource, err := AddRelation(string(sourceContent), c.LinkTo(sourceFile, target, targetNum, targetFile))
+	if err != nil {
+		return nil, fmt.Errorf("adding relation to ADR %d: %w", number, err)
+	}
+	updatedTarget, err := AddRelation(string(targetContent), target.LinkTo(targetFile, c, number, sourceFile))
+	if err != nil {
+		return nil, fmt.Errorf("adding relation to ADR %d: %w", targetNum, err)
+	}
+
+	targetRel, err := txnPath(c.Directory, targetPath)
+	if err != nil {
+		return nil, err
+	}
+	var txn fileTxn
+	txn.write(targetRel, updatedTarget)
+	txn.write(sourceFile, updatedSource)
+	if err := txn.commit(c.Directory); err != nil {
+		return nil, err
+	}
+
+	record, err := repo.parse(updatedSource, number)
+	if err != nil {
+		return nil, err
+	}
+	record.Content = updatedSource
+	record.Root = c.root
+	return &record, nil
+}
+
+// ID identifies an ADR across a project's roots: its number, qualified by the
+// root's name ("payments:12") for an ADR outside the default root.
+type ID struct {
+	Root   string
+	Number int
+}
+
+// ParseID parses an ADR ID: a positive number, optionally prefixed by a root
+// name and a colon.
+func ParseID(s string) (ID, error) {
+	root, num, qualified := strings.Cut(s, ":")
+	if !qualified {
+		root, num = "", s
+	} else if !rootNamePattern.MatchString(root) {
+		return ID{}, fmt.Errorf("invalid ADR ID %q: %q is not a root name", s, root)
+	}
+	n, err := strconv.Atoi(num)
+	if err != nil {
+		return ID{}, fmt.Errorf("invalid ADR ID %q: must be a number", s)
+	}
+	if n <= 0 {
+		return ID{}, fmt.Errorf("invalid ADR ID %d: must be positive", n)
+	}
+	return ID{Root: root, Number: n}, nil
+}
+
+// String returns the ID as ParseID reads it: "12" or "payments:12".
+func (id ID) String() string {
+	if id.Root == "" {
+		return strconv.Itoa(id.Number)
+	}
+	return id.Root + ":" + strconv.Itoa(id.Number)
+}
@@ -0,0 +1,211 @@
+package adr_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeRootsConfig writes a project config with a payments and a billing root
+// below a new directory and returns the directory.
+func writeRootsConfig(t *testing.T, directory string) string {
+	t.Helper()
+	dir := t.TempDir()
+	data := `{"version": "1", "directory": "` + directory + `", "template": "nygard", "scopes": ["Platform"], "roots": [
+		{"name": "payments", "directory": "services/payments/docs/adr", "template": "madr-minimal", "scopes": ["Checkout"]},
+		{"name": "billing", "directory": "services/billing/docs/adr"}
+	]}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, adr.ConfigFileName), []byte(data), 0o644))
+	return dir
+}
+
+func TestConfig_Root(t *testing.T) {
+	cfg, err := adr.LoadConfig(writeRootsConfig(t, "docs/adr"))
+	require.NoError(t, err)
+
+	def, err := cfg.Root("")
+	require.NoError(t, err)
+	assert.Same(t, cfg, def)
+	assert.Equal(t, []string{"payments", "billing"}, cfg.RootNames())
+
+	payments, err := cfg.Root("payments")
+	require.NoError(t, err)
+	assert.Equal(t, "payments", payments.RootName())
+	assert.Equal(t, "services/payments/docs/adr", payments.Directory)
+	assert.Equal(t, "madr-minimal", payments.Template)
+	assert.Equal(t, []string{"Checkout"}, payments.Scopes)
+
+	billing, err := payments.Root("billing")
+	require.NoError(t, err)
+	assert.Equal(t, "nygard", billing.Template, "templates default to the top-level one")
+	assert.Empty(t, billing.Scopes, "scopes are per root")
+
+	_, err = cfg.Root("shipping")
+	assert.ErrorIs(t, err, adr.ErrUnknownRoot)
+
+	roots, err := cfg.AllRoots()
+	require.NoError(t, err)
+	require.Len(t, roots, 3)
+	assert.Equal(t, []string{"", "payments", "billing"}, []string{roots[0].RootName(), roots[1].RootName(), roots[2].RootName()})
+}
+
+func TestConfig_Root_WithoutTopLevelDirectory(t *testing.T) {
+	cfg, err := adr.LoadConfig(writeRootsConfig(t, ""))
+	require.NoError(t, err)
+
+	_, err = cfg.Root("")
+	assert.ErrorIs(t, err, adr.ErrRootRequired)
+	roots, err := cfg.AllRoots()
+	require.NoError(t, err)
+	assert.Len(t, roots, 2)
+}
+
+func TestSaveConfig_RootScopes(t *testing.T) {
+	dir := writeRootsConfig(t, "docs/adr")
+	cfg, err := adr.LoadConfig(dir)
+	require.NoError(t, err)
+	payments, err := cfg.Root("payments")
+	require.NoError(t, err)
+
+	_, err = payments.AddScope("Refunds")
+	require.NoError(t, err)
+	require.NoError(t, adr.SaveConfig(dir, payments))
+
+	saved, err := adr.LoadConfig(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "docs/adr", saved.Directory)
+	assert.Equal(t, []string{"Platform"}, saved.Scopes)
+	payments, err = saved.Root("payments")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Checkout", "Refunds"}, payments.Scopes)
+}
+
+func TestConfig_LinksAcrossRoots(t *testing.T) {
+	dir := writeRootsConfig(t, "docs/adr")
+	cfg, err := adr.LoadConfig(dir)
+	require.NoError(t, err)
+	cfg.Directory = filepath.Join(dir, cfg.Directory)
+	for i := range cfg.Roots {
+		cfg.Roots[i].Directory = filepath.Join(dir, cfg.Roots[i].Directory)
+	}
+	payments, err := cfg.Root("payments")
+	require.NoError(t, err)
+	billing, err := cfg.Root("billing")
+	require.NoError(t, err)
+
+	link := payments.LinkTo("0001-use-stripe.md", billing, 12, "0012-use-invoices.md")
+	assert.Equal(t, adr.ADRLink{Number: 12, Filename: "../../../billing/docs/adr/0012-use-invoices.md", Label: "billing:ADR-0012"}, link)
+	assert.Equal(t, adr.ADRLink{Number: 3, Filename: "0003-x.md", Label: "ADR-0003"}, payments.LinkTo("0001-use-stripe.md", payments, 3, "0003-x.md"))
+	link = payments.LinkTo("0001-use-stripe.md", cfg, 4, "0004-use-go.md")
+	assert.Equal(t, adr.ADRLink{Number: 4, Filename: "../../../../docs/adr/0004-use-go.md", Label: "ADR-0004"}, link)
+
+	id, ok := resolveLink(payments, "0001-use-stripe.md", "../../../billing/docs/adr/0012-use-invoices.md")
+	assert.True(t, ok)
+	assert.Equal(t, adr.ID{Root: "billing", Number: 12}, id)
+	id, ok = resolveLink(payments, "0001-use-stripe.md", "0002-use-adyen.md")
+	assert.True(t, ok)
+	assert.Equal(t, adr.ID{Root: "payments", Number: 2}, id)
+	id, ok = resolveLink(billing, "0012-use-invoices.md", "../../../../docs/adr/0004-use-go.md")
+	assert.True(t, ok)
+	assert.Equal(t, adr.ID{Number: 4}, id)
+	_, ok = resolveLink(payments, "0001-use-stripe.md", "https://example.com/0002-x.md")
+	assert.False(t, ok)
+}
+
+// resolveLink returns the ADR a link dest, written in the ADR stored in from
+// in cfg's root, points at.
+func resolveLink(cfg *adr.Config, from, dest string) (adr.ID, bool) {
+	file, ok := cfg.LinkPath(from, dest)
+	if !ok {
+		return adr.ID{}, false
+	}
+	return cfg.Locate(file)
+}
+
+func TestFileRepository_RewritesLinksAcrossRoots(t *testing.T) {
+	dir := writeRootsConfig(t, "docs/adr")
+	writeFiles(t, dir, map[string]string{
+		"docs/adr/0001-use-adyen.md": "# 1. Use Adyen\n\n## Status\n\nAccepted\n\n" +
+			"## Relations\n\nRelates to [payments:ADR-0001](../../services/payments/docs/adr/0001-use-stripe.md)  \n",
+		"services/payments/docs/adr/0001-use-stripe.md": "# 1. Use Stripe\n\n## Status\n\nAccepted\n",
+		"services/billing/docs/adr/0001-use-invoices.md": "# 1. Use invoices\n\n## Status\n\nAccepted\n\n" +
+			"## Relations\n\nRelates to [payments:ADR-0001](../../../payments/docs/adr/0001-use-stripe.md)  \n",
+	})
+	cfg, err := adr.Mount("shop", dir)
+	require.NoError(t, err)
+	payments, err := cfg.Root("payments")
+	require.NoError(t, err)
+	repo := payments.Repository()
+	ctx := context.Background()
+	adyen := filepath.Join(dir, "docs/adr/0001-use-adyen.md")
+	invoices := filepath.Join(dir, "services/billing/docs/adr/0001-use-invoices.md")
+
+	_, err = repo.Rename(ctx, 1, "Use Stripe API")
+	require.NoError(t, err)
+	assert.Contains(t, readFile(t, adyen), "[payments:ADR-0001](../../services/payments/docs/adr/0001-use-stripe-api.md)")
+	assert.Contains(t, readFile(t, invoices), "[payments:ADR-0001](../../../payments/docs/adr/0001-use-stripe-api.md)")
+
+	_, err = repo.Renumber(ctx, 1, 7)
+	require.NoError(t, err)
+	assert.Contains(t, readFile(t, adyen), "[payments:ADR-0007](../../services/payments/docs/adr/0007-use-stripe-api.md)")
+
+	_, err = repo.Archive(ctx, 7)
+	require.NoError(t, err)
+	assert.Contains(t, readFile(t, adyen), "[payments:ADR-0007](../../services/payments/docs/adr/archive/0007-use-stripe-api.md)")
+	assert.Contains(t, readFile(t, invoices), "[payments:ADR-0007](../../../payments/docs/adr/archive/0007-use-stripe-api.md)")
+	assert.NoFileExists(t, filepath.Join(payments.Directory, adr.JournalFileName))
+}
+
+func TestParseID(t *testing.T) {
+	id, err := adr.ParseID("12")
+	require.NoError(t, err)
+	assert.Equal(t, adr.ID{Number: 12}, id)
+	assert.Equal(t, "12", id.String())
+
+	id, err = adr.ParseID("payments:12")
+	require.NoError(t, err)
+	assert.Equal(t, adr.ID{Root: "payments", Number: 12}, id)
+	assert.Equal(t, "payments:12", id.String())
+
+	for _, bad := range []string{"", "x", "0", "payments:", ":12", "pay/ments:12", "payments:-1"} {
+		_, err := adr.ParseID(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestConfig_Root_SharesProjectTemplates(t *testing.T) {
+	for _, tc := range []struct {
+		name, directory, templates string
+	}{
+		{"below the top-level directory", "docs/adr", "docs/adr"},
+		{"in the project directory without one", "", "."},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{
+				adr.ConfigFileName: `{"version": "1", "directory": "` + tc.directory + `", "template": "nygard",
+					"templateFile": "template.md", "templatesDir": "templates", "partialsDir": "partials",
+					"roots": [{"name": "payments", "directory": "services/payments/docs/adr"}]}`,
+				tc.templates + "/template.md":        "# Title\n\n## Status\n\n## Context\n",
+				tc.templates + "/templates/rfc.md":   "{{/* adr:template */}}\n# Title\n\n{{template \"footer\" .}}",
+				tc.templates + "/partials/footer.md": "shared\n",
+			})
+			cfg, err := adr.Mount("shop", dir)
+			require.NoError(t, err)
+			payments, err := cfg.Root("payments")
+			require.NoError(t, err)
+
+			tmpl, err := adr.LoadProjectTemplate(payments, "rfc")
+			require.NoError(t, err)
+			assert.Equal(t, "shared\n", tmpl.Partials["footer"])
+			tmpl, err = adr.NewTemplateLoader(payments).Load("nygard")
+			require.NoError(t, err)
+			assert.Equal(t, "# Title\n\n## Status\n\n## Context\n", tmpl.Content)
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package adr
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"regexp"
 	"strings"
+	"unicode"
 )
 
 var (
@@ -11,17 +14,41 @@ var (
 	multipleHyphens   = regexp.MustCompile(`-{2,}`)
 )
 
-// Slugify converts a title into a URL-friendly slug.
-// Returns an error if the resulting slug is empty.
+// Slugify converts a title into a URL-friendly slug of lowercase ASCII
+// letters, digits and hyphens. Accented Latin, Greek and Cyrillic letters are
+// transliterated, so "Überarbeitung der Authentifizierung" becomes
+// "ueberarbeitung-der-authentifizierung". A title whose letters can't be
+// transliterated, such as one in Japanese, gets the slug "adr-" followed by a
+// short hash of the title. Returns an error if the title has no letters or
+// digits at all.
 func Slugify(title string) (string, error) {
-	s := strings.ToLower(strings.TrimSpace(title))
+	s := transliterate(strings.TrimSpace(title))
 	s = strings.ReplaceAll(s, " ", "-")
 	s = nonAlphanumHyphen.ReplaceAllString(s, "")
 	s = multipleHyphens.ReplaceAllString(s, "-")
 	s = strings.Trim(s, "-")
 
 	if s == "" {
-		return "", fmt.Errorf("title %q produces an empty slug", title)
+		if !strings.ContainsFunc(title, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) {
+			return "", fmt.Errorf("title %q produces an empty slug", title)
+		}
+		sum := sha256.Sum256([]byte(strings.TrimSpace(title)))
+		s = "adr-" + hex.EncodeToString(sum[:4])
 	}
 	return s, nil
 }
+
+// truncateSlug shortens slug to at most max bytes, cutting at a hyphen when
+// one is in reach so words stay whole. A max of 0 means no limit.
+func truncateSlug(slug string, max int) string {
+	if max <= 0 || len(slug) <= max {
+		return slug
+	}
+	cut := slug[:max]
+	if slug[max] != '-' {
+		if i := strings.LastIndexByte(cut, '-'); i > 0 {
+			cut = cut[:i]
+		}
+	}
+	return strings.TrimRight(cut, "-")
+}
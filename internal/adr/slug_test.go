@@ -53,3 +53,42 @@ func TestSlugify_AllSpecialChars_ReturnsError(t *testing.T) {
 	_, err := adr.Slugify("!!!")
 	assert.Error(t, err)
 }
+
+func TestSlugify_Transliterates(t *testing.T) {
+	tests := map[string]string{
+		"Überarbeitung der Authentifizierung": "ueberarbeitung-der-authentifizierung",
+		"Straße und Größe":                    "strasse-und-groesse",
+		"Café à la crème":                     "cafe-a-la-creme",
+		"Łódź Żółć":                           "lodz-zolc",
+		"Čeština a Ærø":                       "cestina-a-aero",
+		"Выбор базы данных":                   "vybor-bazy-dannykh",
+		"Щит і їжак":                          "shchit-i-yizhak",
+		"Επιλογή βάσης":                       "epilogi-vasis",
+		"Vietnamese Tiếng Việt":               "vietnamese-tieng-viet",
+		// Decomposed, as macOS writes filenames and pasted text.
+		"U\u0308bersicht Cafe\u0301": "uebersicht-cafe",
+	}
+	for title, want := range tests {
+		slug, err := adr.Slugify(title)
+		require.NoError(t, err, title)
+		assert.Equal(t, want, slug, title)
+	}
+}
+
+func TestSlugify_UntransliterableTitleFallsBackToHash(t *testing.T) {
+	slug, err := adr.Slugify("認証の見直し")
+	require.NoError(t, err)
+	assert.Regexp(t, `^adr-[0-9a-f]{8}$`, slug)
+
+	again, err := adr.Slugify("  認証の見直し ")
+	require.NoError(t, err)
+	assert.Equal(t, slug, again, "the fallback is stable")
+	other, err := adr.Slugify("データベースの選択")
+	require.NoError(t, err)
+	assert.NotEqual(t, slug, other)
+
+	// Transliterable words win over the fallback.
+	slug, err = adr.Slugify("Use 認証 for SSO")
+	require.NoError(t, err)
+	assert.Equal(t, "use-for-sso", slug)
+}
@@ -26,7 +26,8 @@ func SortADRs(records []ADR, field string, desc bool) error {
 func sortLess(records []ADR, field string, desc bool) (func(i, j int) bool, error) {
 	switch field {
 	case "number":
-		// Number is unique, so no tiebreaker is needed.
+		// Number is unique within a root, so no tiebreaker is needed; the stable
+		// sort keeps a combined listing of several roots in root order.
 		return func(i, j int) bool {
 			return applyDir(records[i].Number < records[j].Number, records[i].Number > records[j].Number, desc)
 		}, nil
@@ -10,17 +10,27 @@ import (
 type ADRLink struct {
 	Number   int
 	Filename string
+	// Label is the link text, e.g. "ADR-0012" (see Naming.Label); empty
+	// means the default label.
+	Label string
 }
 
-func formatADRLink(link ADRLink) string {
-	return fmt.Sprintf("[ADR-%04d](%s)", link.Number, link.Filename)
+// label returns the link text, defaulting to the default naming's label.
+func (l ADRLink) label() string {
+	if l.Label != "" {
+		return l.Label
+	}
+	return Naming{}.Label(l.Number)
 }
 
-var statusSectionPattern = regexp.MustCompile(`(?m)^## Status[ \t]*$`)
+func formatADRLink(link ADRLink) string {
+	return "[" + link.label() + "](" + link.Filename + ")"
+}
 
 // hasStatusSection checks for a ## Status heading.
 func hasStatusSection(content string) bool {
-	return statusSectionPattern.MatchString(content)
+	_, _, ok := findSection(content, 2, "Status")
+	return ok
 }
 
 // hasFrontmatterStatus checks for status: within YAML frontmatter.
@@ -47,30 +57,14 @@ func extractFrontmatter(content string) string {
 	return rest[:idx]
 }
 
-// replaceStatusSectionContent replaces the text between ## Status and the next ## heading (or EOF).
+// replaceStatusSectionContent replaces the text between ## Status and the next
+// ## or # heading (or EOF).
 func replaceStatusSectionContent(content, newContent string) string {
-	// Find ## Status heading
-	loc := statusSectionPattern.FindStringIndex(content)
-	if loc == nil {
+	h, end, ok := findSection(content, 2, "Status")
+	if !ok {
 		return content
 	}
-
-	// Find the end of the heading line + blank line separator
-	afterHeading := loc[1]
-
-	// Find next ## heading after status section
-	restAfterHeading := content[afterHeading:]
-	nextHeadingPattern := regexp.MustCompile(`(?m)\n\n## `)
-	nextLoc := nextHeadingPattern.FindStringIndex(restAfterHeading)
-
-	if nextLoc != nil {
-		// Replace content between ## Status heading and next ## heading
-		return content[:afterHeading] + "\n\n" + newContent + content[afterHeading+nextLoc[0]:]
-	}
-
-	// Status is the last section — replace to end of file
-	// Preserve trailing newline
-	return content[:afterHeading] + "\n\n" + newContent + "\n"
+	return replaceSectionBody(content, h, end, newContent)
 }
 
 // replaceFrontmatterStatus replaces the status: line in YAML frontmatter only.
@@ -101,6 +95,7 @@ func replaceFrontmatterStatus(content, newValue string) string {
 // Returns an error if no status section is found.
 func UpdateStatus(content, newStatus string) (string, error) {
 	normalized := strings.ToLower(newStatus)
+	content, style := normalizeText(content)
 	if hasStatusSection(content) {
 		titled := strings.ToUpper(normalized[:1]) + normalized[1:]
 		existing := extractStatusSectionContent(content)
@@ -108,40 +103,27 @@ func UpdateStatus(content, newStatus string) (string, error) {
 			// Preserve content after the status line (supersedes links, etc.)
 			titled = titled + existing[idx:]
 		}
-		return replaceStatusSectionContent(content, titled), nil
+		return style.restore(replaceStatusSectionContent(content, titled)), nil
 	}
 	if hasFrontmatterStatus(content) {
 		currentValue := getFrontmatterStatusValue(content)
 		if idx := strings.Index(currentValue, ", supersedes "); idx >= 0 {
 			normalized = normalized + currentValue[idx:]
 		}
-		return replaceFrontmatterStatus(content, normalized), nil
+		return style.restore(replaceFrontmatterStatus(content, normalized)), nil
 	}
 	return "", fmt.Errorf("no status section found")
 }
 
-// extractStatusSectionContent returns the text between ## Status heading and the next ## heading (or EOF).
-// Returns empty string if the section has no content.
+// extractStatusSectionContent returns the text between ## Status heading and
+// the next ## or # heading (or EOF). Returns empty string if the section has
+// no content.
 func extractStatusSectionContent(content string) string {
-	loc := statusSectionPattern.FindStringIndex(content)
-	if loc == nil {
+	h, end, ok := findSection(content, 2, "Status")
+	if !ok {
 		return ""
 	}
-
-	afterHeading := loc[1]
-	restAfterHeading := content[afterHeading:]
-
-	nextHeadingPattern := regexp.MustCompile(`(?m)\n\n## `)
-	nextLoc := nextHeadingPattern.FindStringIndex(restAfterHeading)
-
-	var sectionBody string
-	if nextLoc != nil {
-		sectionBody = restAfterHeading[:nextLoc[0]]
-	} else {
-		sectionBody = restAfterHeading
-	}
-
-	return strings.TrimSpace(sectionBody)
+	return strings.TrimSpace(content[h.End:end])
 }
 
 // appendToStatusSectionContent appends text below existing status section content.
@@ -178,12 +160,13 @@ func getFrontmatterStatusValue(content string) string {
 func SetSupersededBy(content string, link ADRLink) (string, error) {
 	// Markdown needs two spaces at the end to display new line
 	statusText := "Superseded by " + formatADRLink(link) + "  "
+	content, style := normalizeText(content)
 
 	if hasStatusSection(content) {
-		return replaceStatusSectionContent(content, statusText), nil
+		return style.restore(replaceStatusSectionContent(content, statusText)), nil
 	}
 	if hasFrontmatterStatus(content) {
-		return replaceFrontmatterStatus(content, "superseded by "+formatADRLink(link)+"  "), nil
+		return style.restore(replaceFrontmatterStatus(content, "superseded by "+formatADRLink(link)+"  ")), nil
 	}
 	return "", fmt.Errorf("no status section found: expected ## Status heading or status: in YAML frontmatter")
 }
@@ -191,12 +174,13 @@ func SetSupersededBy(content string, link ADRLink) (string, error) {
 // SetSupersedes updates the content's status with "Supersedes [ADR-N](filename)" entries.
 // Supersedes links are appended below the existing status text, not replacing it.
 func SetSupersedes(content string, links []ADRLink) (string, error) {
+	content, style := normalizeText(content)
 	if hasStatusSection(content) {
 		var lines []string
 		for _, link := range links {
 			lines = append(lines, "Supersedes "+formatADRLink(link)+"  ")
 		}
-		return appendToStatusSectionContent(content, strings.Join(lines, "\n")), nil
+		return style.restore(appendToStatusSectionContent(content, strings.Join(lines, "\n"))), nil
 	}
 	if hasFrontmatterStatus(content) {
 		var refs []string
@@ -205,7 +189,7 @@ func SetSupersedes(content string, links []ADRLink) (string, error) {
 		}
 		currentStatus := getFrontmatterStatusValue(content)
 		newValue := currentStatus + ", supersedes " + strings.Join(refs, ", ") + "  "
-		return replaceFrontmatterStatus(content, newValue), nil
+		return style.restore(replaceFrontmatterStatus(content, newValue)), nil
 	}
 	return "", fmt.Errorf("no status section found: expected ## Status heading or status: in YAML frontmatter")
 }
@@ -25,6 +25,29 @@ func TestUpdateStatus_NygardCaseInsensitive(t *testing.T) {
 	assert.Contains(t, result, "## Status\n\nRejected\n\n## Context")
 }
 
+func TestUpdateStatus_IgnoresStatusHeadingInCodeFence(t *testing.T) {
+	content := "# 1. Use Go\n\n## Context\n\n```markdown\n## Status\n\nExample\n```\n\n## Status\n\nProposed\n"
+
+	result, err := adr.UpdateStatus(content, "accepted")
+	require.NoError(t, err)
+	assert.Equal(t, "# 1. Use Go\n\n## Context\n\n```markdown\n## Status\n\nExample\n```\n\n## Status\n\nAccepted\n", result)
+}
+
+func TestUpdateStatus_FencedStatusOnly_ReturnsError(t *testing.T) {
+	content := "# 1. Use Go\n\n~~~\n## Status\n\nProposed\n~~~\n"
+
+	_, err := adr.UpdateStatus(content, "accepted")
+	assert.Error(t, err)
+}
+
+func TestUpdateStatus_SetextStatusHeading(t *testing.T) {
+	content := "# 1. Use Go\n\nStatus\n------\n\nProposed\n\nContext\n-------\n\nSome context.\n"
+
+	result, err := adr.UpdateStatus(content, "accepted")
+	require.NoError(t, err)
+	assert.Equal(t, "# 1. Use Go\n\nStatus\n------\n\nAccepted\n\nContext\n-------\n\nSome context.\n", result)
+}
+
 func TestUpdateStatus_Frontmatter(t *testing.T) {
 	content := "---\nstatus: \"proposed\"\ndate: 2024-01-01\n---\n\n# 1. Use Go\n\n## Context and Problem Statement\n\nSome context.\n"
 
@@ -3,6 +3,8 @@ package adr
 import (
 	"embed"
 	"fmt"
+	"slices"
+	"sync"
 )
 
 // TemplateName identifies a supported ADR template format.
@@ -57,8 +59,8 @@ var templateSections = map[TemplateName][]TemplateSectionDef{
 	},
 	TemplateMADRFull: {
 		// Frontmatter metadata fields. Key is the exact lowercase YAML key as it
-		// appears in the file; getFrontmatterField matches on Key, not Heading.
-		// Excluded from the create form (see TemplateSections) — filter facets only.
+		// appears in the file; ExtractMetaFields and SetFrontmatterList match on
+		// Key, not Heading.
 		{Key: "decision-makers", Heading: "Decision Makers", Kind: "frontmatter", Optional: true, Placeholder: "list everyone involved in the decision"},
 		{Key: "consulted", Heading: "Consulted", Kind: "frontmatter", Optional: true, Placeholder: "list everyone whose opinions are sought"},
 		{Key: "informed", Heading: "Informed", Kind: "frontmatter", Optional: true, Placeholder: "list everyone who is kept up-to-date on progress"},
@@ -74,59 +76,132 @@ var templateSections = map[TemplateName][]TemplateSectionDef{
 }
 
 // TemplateSections returns the ordered list of user-editable section definitions
-// for the named template. The Status section is excluded (server-managed), and so
-// are "frontmatter" fields — the create form only knows how to write "meta"
-// title-block lines and "h2"/"h3" body sections, so a frontmatter def leaking
-// through would be mishandled (see handleCreateADR). Frontmatter fields are surfaced
-// only as filter facets via AllMetaFieldDefs.
+// for the named template: its "frontmatter" fields, title-block "meta" lines and
+// "h2"/"h3" body sections (see ApplySections). The Status section is not among
+// them (server-managed).
 func TemplateSections(name string) ([]TemplateSectionDef, error) {
 	tn := TemplateName(name)
 	sections, ok := templateSections[tn]
 	if !ok {
 		return nil, fmt.Errorf("unknown template %q", name)
 	}
-	result := make([]TemplateSectionDef, 0, len(sections))
-	for _, s := range sections {
-		if s.Kind == "frontmatter" {
+	return slices.Clone(sections), nil
+}
+
+// builtinMetaFields is the deduped union of the metadata fields (Kind "meta" or
+// "frontmatter") of the built-in templates, and the precompiled extractors for
+// them, in ValidTemplateNames order (templateSections is a map, so we iterate
+// the fixed template-name order to keep facet/JSON ordering stable). It never
+// changes: a project's own templates extend a copy of it held by the project's
+// Config (see Config.MetaFieldDefs), so projects served together don't see each
+// other's fields.
+var builtinMetaFields = newMetaFieldRegistry()
+
+type metaFieldRegistry struct {
+	mu         sync.RWMutex
+	defs       []TemplateSectionDef
+	extractors []metaFieldExtractor
+}
+
+func newMetaFieldRegistry() *metaFieldRegistry {
+	reg := &metaFieldRegistry{}
+	for _, name := range ValidTemplateNames() {
+		reg.add(templateSections[TemplateName(name)])
+	}
+	return reg
+}
+
+// add appends the metadata defs whose Key is not yet known; the first
+// definition of a key wins. Callers must hold mu (or own reg exclusively).
+func (reg *metaFieldRegistry) add(defs []TemplateSectionDef) {
+	for _, s := range defs {
+		if s.Kind != "meta" && s.Kind != "frontmatter" {
+			continue
+		}
+		if reg.has(s.Key) {
 			continue
 		}
-		result = append(result, s)
+		reg.defs = append(reg.defs, s)
+		reg.extractors = append(reg.extractors, newMetaFieldExtractor(s))
 	}
-	return result, nil
 }
 
-// allMetaFieldDefs is the deduped union of every metadata field (Kind "meta" or
-// "frontmatter") across all templates, in ValidTemplateNames order. It is the single
-// source for metadata extraction and the filter-facet API. Built once: templateSections
-// is a map (nondeterministic iteration), so we iterate the fixed template-name order to
-// keep facet/JSON ordering stable.
-var allMetaFieldDefs = buildAllMetaFieldDefs()
-
-func buildAllMetaFieldDefs() []TemplateSectionDef {
-	var result []TemplateSectionDef
-	seen := make(map[string]bool)
-	for _, name := range ValidTemplateNames() {
-		for _, s := range templateSections[TemplateName(name)] {
-			if s.Kind != "meta" && s.Kind != "frontmatter" {
-				continue
-			}
-			if seen[s.Key] {
-				continue
-			}
-			seen[s.Key] = true
-			result = append(result, s)
+func (reg *metaFieldRegistry) has(key string) bool {
+	for _, d := range reg.defs {
+		if d.Key == key {
+			return true
 		}
 	}
-	return result
+	return false
+}
+
+// register adds defs under the lock. Safe for concurrent use.
+func (reg *metaFieldRegistry) register(defs []TemplateSectionDef) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.add(defs)
 }
 
-// AllMetaFieldDefs returns the deduped union of metadata field definitions across all
-// templates (Kind "meta" or "frontmatter"), in a stable order. These are the fields
-// carried in ADR.Meta and exposed as filter facets.
+// all returns a copy of the registered defs. Safe for concurrent use.
+func (reg *metaFieldRegistry) all() []TemplateSectionDef {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	return slices.Clone(reg.defs)
+}
+
+// AllMetaFieldDefs returns the deduped union of metadata field definitions across the
+// built-in templates (Kind "meta" or "frontmatter"), in a stable order. A project's
+// own fields are added by Config.MetaFieldDefs.
 func AllMetaFieldDefs() []TemplateSectionDef {
-	result := make([]TemplateSectionDef, len(allMetaFieldDefs))
-	copy(result, allMetaFieldDefs)
-	return result
+	return builtinMetaFields.all()
+}
+
+// MetaFieldDefs returns the metadata fields of the project's templates: the
+// built-ins' (see AllMetaFieldDefs) followed by those only its own templates
+// declare. These are the fields carried in ADR.Meta by the project's
+// repository and exposed as filter facets.
+func (c *Config) MetaFieldDefs() []TemplateSectionDef {
+	return c.metaFieldRegistry().all()
+}
+
+// RegisterMetaFieldDefs adds the "meta" and "frontmatter" fields of one of the
+// project's templates to those recognized in its ADRs (see MetaFieldDefs).
+// Keys already known (built-in or registered earlier) are left as they are.
+// LoadConfig registers the templates the project declares; safe for
+// concurrent use once registered or after NewTemplateLoader.
+func (c *Config) RegisterMetaFieldDefs(defs []TemplateSectionDef) {
+	c.ownMetaFields().register(defs)
+}
+
+// ExtractMetaFields parses the project's metadata fields (see MetaFieldDefs)
+// from an ADR's raw content, as the package-level ExtractMetaFields does for
+// the built-in ones.
+func (c *Config) ExtractMetaFields(content string) map[string][]string {
+	return c.metaFieldRegistry().extract(content)
+}
+
+// metaFieldRegistry returns the project's registry, or the built-ins when no
+// project template added fields.
+func (c *Config) metaFieldRegistry() *metaFieldRegistry {
+	if c.parent != nil {
+		return c.parent.metaFieldRegistry()
+	}
+	if c.metaFields == nil {
+		return builtinMetaFields
+	}
+	return c.metaFields
+}
+
+// ownMetaFields returns the project's registry, creating it from the built-ins
+// first if needed. Root configs share their project's.
+func (c *Config) ownMetaFields() *metaFieldRegistry {
+	if c.parent != nil {
+		return c.parent.ownMetaFields()
+	}
+	if c.metaFields == nil {
+		c.metaFields = newMetaFieldRegistry()
+	}
+	return c.metaFields
 }
 
 // TemplateContent returns the content of the named template.
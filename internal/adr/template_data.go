@@ -0,0 +1,139 @@
+package adr
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// TemplateData holds the values a template can use through text/template
+// actions, e.g. "{{.Title}}", "{{if .Scopes}}Scope: {{join .Scopes ", "}}{{end}}"
+// or "{{.Vars.team}}". Only templates that start with TemplateActionsMarker
+// are rendered; all others are used unchanged.
+type TemplateData struct {
+	Number int
+	Title  string
+	// Date is the creation date as YYYY-MM-DD.
+	Date   string
+	Status string
+	// Author is the creating user: git's user.name for the CLI, the request
+	// identity for the web server. Empty when unknown.
+	Author     string
+	Scopes     []string
+	Supersedes []ADRLink
+	// Vars holds custom values (`adr new --var key=value`). Missing keys
+	// render as empty strings.
+	Vars map[string]string
+}
+
+// NewTemplateData returns the template data for a new ADR record.
+func NewTemplateData(record *ADR) TemplateData {
+	return TemplateData{
+		Number: record.Number,
+		Title:  record.Title,
+		Date:   record.Date.Format("2006-01-02"),
+		Status: record.Status.String(),
+		Vars:   map[string]string{},
+	}
+}
+
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// TemplateActionsMarker, as the first line of a template, opts the template
+// in to text/template actions. The line itself is not part of the output.
+const TemplateActionsMarker = "{{/* adr:template */}}"
+
+// templateActions returns content without its first line and true when that
+// line is TemplateActionsMarker, and content unchanged otherwise.
+func templateActions(content string) (string, bool) {
+	first, rest, _ := strings.Cut(content, "\n")
+	if strings.TrimSpace(first) != TemplateActionsMarker {
+		return content, false
+	}
+	return rest, true
+}
+
+// Execute runs the template's text/template actions against data, with the
+// project partials available as {{template "name" .}}. Templates that do not
+// start with TemplateActionsMarker are returned as is, so a plain template
+// may contain "{{" anywhere, e.g. in prose or code samples.
+func (t *ProjectTemplate) Execute(data TemplateData) (string, error) {
+	content, ok := templateActions(t.Content)
+	if !ok {
+		return t.Content, nil
+	}
+
+	root := template.New(t.Name).Funcs(templateFuncs).Option("missingkey=zero")
+	names := make([]string, 0, len(t.Partials))
+	for name := range t.Partials {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := root.New(name).Parse(t.Partials[name]); err != nil {
+			return "", fmt.Errorf("parsing partial %q: %w", name, err)
+		}
+	}
+	if _, err := root.Parse(content); err != nil {
+		return "", fmt.Errorf("parsing template %q: %w", t.Name, err)
+	}
+
+	if data.Vars == nil {
+		data.Vars = map[string]string{}
+	}
+	var buf bytes.Buffer
+	if err := root.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("rendering template %q: %w", t.Name, err)
+	}
+	return buf.String(), nil
+}
+
+// VocabularyValues returns the comma-separated values entered for the
+// vocabulary fields (e.g. Scope) among defs, trimmed, in field order.
+func VocabularyValues(defs []TemplateSectionDef, values map[string]string) []string {
+	var out []string
+	for _, d := range defs {
+		if !d.Vocabulary {
+			continue
+		}
+		for _, v := range strings.Split(values[d.Key], ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				out = append(out, v)
+			}
+		}
+	}
+	return out
+}
+
+// loadPartials reads every <name>.md in cfg.PartialsDir.
+func loadPartials(cfg *Config) (map[string]string, error) {
+	if cfg.PartialsDir == "" {
+		return nil, nil
+	}
+	dir := filepath.Join(cfg.templateDir(), cfg.PartialsDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading partials: %w", err)
+	}
+	partials := make(map[string]string)
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), ".md")
+		if e.IsDir() || name == e.Name() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading partial %q: %w", name, err)
+		}
+		partials[name] = string(data)
+	}
+	return partials, nil
+}
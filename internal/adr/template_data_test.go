@@ -0,0 +1,145 @@
+package adr_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectTemplate_Execute_PlainTemplateUnchanged(t *testing.T) {
+	content, err := adr.TemplateContent("nygard")
+	require.NoError(t, err)
+	tmpl := &adr.ProjectTemplate{Name: "nygard", Content: content}
+
+	got, err := tmpl.Execute(adr.TemplateData{Title: "Ignored"})
+	require.NoError(t, err)
+	assert.Equal(t, content, got)
+}
+
+func TestProjectTemplate_Execute_WithoutMarkerKeepsBraces(t *testing.T) {
+	content := "# Title\n\n## Context\n\nHelm values look like {{ .Values.image }}; so does {{.Title\n"
+	tmpl := &adr.ProjectTemplate{Name: "helm", Content: content}
+
+	got, err := tmpl.Execute(adr.TemplateData{Title: "Ignored"})
+	require.NoError(t, err)
+	assert.Equal(t, content, got)
+}
+
+func TestDeriveTemplateSections_IgnoresMarker(t *testing.T) {
+	defs := adr.DeriveTemplateSections(adr.TemplateActionsMarker + "\n---\nowner: {{.Author}}\n---\n# Title\n\n## Context\n\nWhy?\n")
+	require.Len(t, defs, 2)
+	assert.Equal(t, "owner", defs[0].Key)
+	assert.Equal(t, "context", defs[1].Key)
+}
+
+func TestProjectTemplate_Execute_Variables(t *testing.T) {
+	tmpl := &adr.ProjectTemplate{Name: "rfc", Content: "{{/* adr:template */}}\n# {{.Number}}. {{.Title}}\n\n" +
+		"Date: {{.Date}}\nAuthor: {{.Author}}\nTeam: {{.Vars.team}}\nMissing: [{{.Vars.nope}}]\n" +
+		"{{range .Supersedes}}- supersedes [{{.Number}}]({{.Filename}})\n{{end}}"}
+	record := adr.New(7, "Use Kafka")
+	data := adr.NewTemplateData(record)
+	data.Author = "Ada"
+	data.Vars = map[string]string{"team": "payments"}
+	data.Supersedes = []adr.ADRLink{{Number: 3, Filename: "0003-use-rabbitmq.md"}}
+
+	got, err := tmpl.Execute(data)
+	require.NoError(t, err)
+	assert.Equal(t, "# 7. Use Kafka\n\n"+
+		"Date: "+record.Date.Format("2006-01-02")+"\nAuthor: Ada\nTeam: payments\nMissing: []\n"+
+		"- supersedes [3](0003-use-rabbitmq.md)\n", got)
+}
+
+func TestProjectTemplate_Execute_Conditionals(t *testing.T) {
+	tmpl := &adr.ProjectTemplate{Name: "rfc",
+		Content: "{{/* adr:template */}}\n# Title\n{{if .Scopes}}\nScope: {{join .Scopes \", \"}}\n{{end}}{{if not .Author}}\nAuthor: unknown\n{{end}}"}
+
+	got, err := tmpl.Execute(adr.TemplateData{Scopes: []string{"Backend", "Infra"}})
+	require.NoError(t, err)
+	assert.Equal(t, "# Title\n\nScope: Backend, Infra\n\nAuthor: unknown\n", got)
+
+	got, err = tmpl.Execute(adr.TemplateData{Author: "Ada"})
+	require.NoError(t, err)
+	assert.Equal(t, "# Title\n", got)
+}
+
+func TestProjectTemplate_Execute_Partials(t *testing.T) {
+	tmpl := &adr.ProjectTemplate{
+		Name:     "rfc",
+		Content:  "{{/* adr:template */}}\n# Title\n\n{{template \"footer\" .}}",
+		Partials: map[string]string{"footer": "Owned by {{.Vars.team | upper}}\n"},
+	}
+
+	got, err := tmpl.Execute(adr.TemplateData{Vars: map[string]string{"team": "payments"}})
+	require.NoError(t, err)
+	assert.Equal(t, "# Title\n\nOwned by PAYMENTS\n", got)
+}
+
+func TestProjectTemplate_Execute_SyntaxError(t *testing.T) {
+	tmpl := &adr.ProjectTemplate{Name: "rfc", Content: "{{/* adr:template */}}\n# {{.Title"}
+
+	_, err := tmpl.Execute(adr.TemplateData{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"rfc"`)
+}
+
+func TestVocabularyValues(t *testing.T) {
+	defs := []adr.TemplateSectionDef{
+		{Key: "context", Heading: "Context", Kind: "h2"},
+		{Key: "scope", Heading: "Scope", Kind: "meta", Vocabulary: true},
+	}
+	got := adr.VocabularyValues(defs, map[string]string{"context": "a, b", "scope": "Backend, , Infra"})
+	assert.Equal(t, []string{"Backend", "Infra"}, got)
+}
+
+func TestLoadProjectTemplate_LoadsPartials(t *testing.T) {
+	cfg := setupProjectTemplates(t, `{
+		"version": "1", "directory": "docs/adr", "template": "rfc",
+		"templates": {"rfc": {"file": "rfc.md"}}, "partialsDir": "partials"
+	}`, map[string]string{
+		"rfc.md":              rfcTemplate,
+		"partials/footer.md":  "Reviewed by {{.Author}}\n",
+		"partials/README.txt": "not a partial",
+	})
+
+	tmpl, err := adr.LoadProjectTemplate(cfg, "rfc")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"footer": "Reviewed by {{.Author}}\n"}, tmpl.Partials)
+}
+
+func TestLoadConfig_PartialsDirOutsideADRDirectory(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, adr.ConfigFileName),
+		[]byte(`{"version": "1", "directory": "docs/adr", "template": "nygard", "partialsDir": "../shared"}`), 0o644))
+
+	_, err := adr.LoadConfig(root)
+	assert.ErrorIs(t, err, adr.ErrConfigInvalid)
+}
+
+func TestTemplateLoader_ReloadsWhenPartialChanges(t *testing.T) {
+	cfg := setupProjectTemplates(t, `{
+		"version": "1", "directory": "docs/adr", "template": "rfc",
+		"templates": {"rfc": {"file": "rfc.md"}}, "partialsDir": "partials"
+	}`, map[string]string{
+		"rfc.md":             "{{/* adr:template */}}\n# Title\n\n{{template \"footer\" .}}",
+		"partials/footer.md": "v1\n",
+	})
+	loader := adr.NewTemplateLoader(cfg)
+
+	first, err := loader.Load("rfc")
+	require.NoError(t, err)
+	assert.Equal(t, "v1\n", first.Partials["footer"])
+
+	path := filepath.Join(cfg.Directory, "partials", "footer.md")
+	require.NoError(t, os.WriteFile(path, []byte("v2\n"), 0o644))
+	later := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(path, later, later))
+
+	reloaded, err := loader.Load("rfc")
+	require.NoError(t, err)
+	assert.Equal(t, "v2\n", reloaded.Partials["footer"])
+}
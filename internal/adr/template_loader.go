@@ -0,0 +1,107 @@
+package adr
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// TemplateLoader serves project templates (see LoadProjectTemplate) to a
+// long-running process such as the web server. Templates read from a file are
+// cached and reloaded when the size or modification time of the file or of
+// the project's partials changes, so edits show up without a restart. Safe
+// for concurrent use.
+type TemplateLoader struct {
+	cfg   *Config
+	mu    sync.Mutex
+	cache map[string]cachedTemplate
+}
+
+type cachedTemplate struct {
+	tmpl   *ProjectTemplate
+	stamps []fileStamp
+}
+
+// fileStamp records what a cached template was loaded from.
+type fileStamp struct {
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+// NewTemplateLoader returns a TemplateLoader for the templates of cfg. Create
+// it before cfg's repositories, which read the metadata fields the loader
+// registers as templates change.
+func NewTemplateLoader(cfg *Config) *TemplateLoader {
+	cfg.ownMetaFields()
+	return &TemplateLoader{cfg: cfg, cache: make(map[string]cachedTemplate)}
+}
+
+// Load returns the template called name, re-reading it if its file changed
+// since the last call. Callers must not modify the returned template.
+func (l *TemplateLoader) Load(name string) (*ProjectTemplate, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if c, ok := l.cache[name]; ok {
+		if stamps, err := l.stamps(c.tmpl.Path); err == nil && sameStamps(stamps, c.stamps) {
+			return c.tmpl, nil
+		}
+		delete(l.cache, name)
+	}
+
+	tmpl, err := LoadProjectTemplate(l.cfg, name)
+	if err != nil {
+		return nil, err
+	}
+	// A reloaded template may declare new metadata fields.
+	l.cfg.RegisterMetaFieldDefs(tmpl.Sections)
+	// Embedded built-ins are cheap to resolve and may gain a project file
+	// later, so only file-backed templates are cached.
+	if tmpl.Path != "" {
+		if stamps, err := l.stamps(tmpl.Path); err == nil {
+			l.cache[name] = cachedTemplate{tmpl: tmpl, stamps: stamps}
+		}
+	}
+	return tmpl, nil
+}
+
+// stamps stats the template file at path and, when configured, the partials
+// directory (which changes when a partial is added or removed) and its files.
+func (l *TemplateLoader) stamps(path string) ([]fileStamp, error) {
+	paths := []string{filepath.Join(l.cfg.templateDir(), path)}
+	if l.cfg.PartialsDir != "" {
+		dir := filepath.Join(l.cfg.templateDir(), l.cfg.PartialsDir)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, dir)
+		for _, e := range entries {
+			paths = append(paths, filepath.Join(dir, e.Name()))
+		}
+	}
+
+	stamps := make([]fileStamp, 0, len(paths))
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		stamps = append(stamps, fileStamp{path: p, modTime: info.ModTime(), size: info.Size()})
+	}
+	return stamps, nil
+}
+
+func sameStamps(a, b []fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].path != b[i].path || !a[i].modTime.Equal(b[i].modTime) || a[i].size != b[i].size {
+			return false
+		}
+	}
+	return true
+}
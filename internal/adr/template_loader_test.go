@@ -0,0 +1,45 @@
+package adr_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateLoader_CachesUntilFileChanges(t *testing.T) {
+	cfg := setupProjectTemplates(t, `{"version": "1", "directory": "docs/adr", "template": "nygard"}`,
+		map[string]string{"template.md": "# Title\n\n## Context\n\nWhy?\n"})
+	loader := adr.NewTemplateLoader(cfg)
+
+	first, err := loader.Load("nygard")
+	require.NoError(t, err)
+	again, err := loader.Load("nygard")
+	require.NoError(t, err)
+	assert.Same(t, first, again)
+
+	path := filepath.Join(cfg.Directory, "template.md")
+	require.NoError(t, os.WriteFile(path, []byte("# Title\n\n## Context\n\nWhy?\n\n## Risks\n\nWhat could go wrong?\n"), 0o644))
+	later := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(path, later, later))
+
+	reloaded, err := loader.Load("nygard")
+	require.NoError(t, err)
+	assert.Contains(t, reloaded.Content, "## Risks")
+	keys := make([]string, 0)
+	for _, s := range reloaded.EditableSections() {
+		keys = append(keys, s.Key)
+	}
+	assert.Equal(t, []string{"context", "risks"}, keys)
+}
+
+func TestTemplateLoader_UnknownTemplate(t *testing.T) {
+	cfg := setupProjectTemplates(t, `{"version": "1", "directory": "docs/adr", "template": "nygard"}`, nil)
+
+	_, err := adr.NewTemplateLoader(cfg).Load("nope")
+	assert.Error(t, err)
+}
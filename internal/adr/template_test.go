@@ -55,10 +55,10 @@ func TestTemplateSections_MADRMinimalHas4Sections(t *testing.T) {
 	assert.Len(t, sections, 4)
 }
 
-func TestTemplateSections_MADRFullHas8Sections(t *testing.T) {
+func TestTemplateSections_MADRFullHas11Sections(t *testing.T) {
 	sections, err := adr.TemplateSections("madr-full")
 	require.NoError(t, err)
-	assert.Len(t, sections, 8)
+	assert.Len(t, sections, 11)
 }
 
 func TestTemplateSections_NoStatusSection(t *testing.T) {
@@ -122,14 +122,18 @@ func TestValidTemplateNames_IncludesNygardScoped(t *testing.T) {
 	assert.Contains(t, adr.ValidTemplateNames(), "nygard-scoped")
 }
 
-func TestTemplateSections_ExcludesFrontmatterFields(t *testing.T) {
-	// MADR-full declares frontmatter fields (decision-makers, etc.); they must not
-	// leak into the create-form sections, which only handle meta/h2/h3.
+func TestTemplateSections_IncludesFrontmatterFields(t *testing.T) {
+	// MADR-full's frontmatter fields (decision-makers, etc.) are filled in by
+	// the create form and the wizard like any other section.
 	sections, err := adr.TemplateSections("madr-full")
 	require.NoError(t, err)
+	var keys []string
 	for _, s := range sections {
-		assert.NotEqual(t, "frontmatter", s.Kind, "frontmatter field %q leaked into form sections", s.Key)
+		if s.Kind == "frontmatter" {
+			keys = append(keys, s.Key)
+		}
 	}
+	assert.Equal(t, []string{"decision-makers", "consulted", "informed"}, keys)
 }
 
 func TestAllMetaFieldDefs_UnionOfMetaAndFrontmatter(t *testing.T) {
@@ -0,0 +1,56 @@
+package adr
+
+import "strings"
+
+// byteOrderMark is the UTF-8 byte order mark some Windows editors start files
+// with.
+const byteOrderMark = "\ufeff"
+
+// lineStyle is how a file's text is encoded on disk: whether it starts with a
+// byte order mark and whether its lines end in CRLF.
+type lineStyle struct {
+	bom  bool
+	crlf bool
+}
+
+// normalizeText returns content without a leading byte order mark and with
+// CRLF line endings turned into LF, which is what the parsers in this package
+// expect, along with the style to write it back in. A file mixing both line
+// endings is written back in the one most of its lines use (LF on a tie).
+func normalizeText(content string) (string, lineStyle) {
+	var style lineStyle
+	if rest, ok := strings.CutPrefix(content, byteOrderMark); ok {
+		content, style.bom = rest, true
+	}
+	if n := strings.Count(content, "\r\n"); n > 0 {
+		style.crlf = n > strings.Count(content, "\n")-n
+		content = strings.ReplaceAll(content, "\r\n", "\n")
+	}
+	return content, style
+}
+
+// restore returns text, which has LF line endings, in style s.
+func (s lineStyle) restore(text string) string {
+	if s.crlf {
+		text = strings.ReplaceAll(text, "\n", "\r\n")
+	}
+	if s.bom {
+		text = byteOrderMark + text
+	}
+	return text
+}
+
+// normalizeLineEndings turns CRLF line endings into LF, for text inserted into
+// a normalized document.
+func normalizeLineEndings(text string) string {
+	return strings.ReplaceAll(text, "\r\n", "\n")
+}
+
+// MatchLineStyle returns content written in the line style of original: with
+// its byte order mark, if any, and CRLF line endings when original uses them.
+// It is for saving a whole new text (e.g. from an editor) over a file.
+func MatchLineStyle(original, content string) string {
+	_, style := normalizeText(original)
+	content, _ = normalizeText(content)
+	return style.restore(content)
+}
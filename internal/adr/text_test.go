@@ -0,0 +1,217 @@
+package adr_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	lfNygard = "# 1. Use Go\n\nDate: 2024-01-01\n\nScope: Backend\n\n## Status\n\nProposed\n\n" +
+		"## Relations\n\nRelates to [ADR-0002](0002-use-chi.md)  \n\n## Context\n\nSome context.\n"
+	lfMADR = "---\nstatus: \"proposed\"\ndate: 2024-03-15\ndeciders: Alice\n---\n\n# 2. Use Chi\n\n" +
+		"## Context and Problem Statement\n\nNeed a router.\n"
+)
+
+func crlf(s string) string {
+	return strings.ReplaceAll(s, "\n", "\r\n")
+}
+
+// crlfVariants returns lf as CRLF, with a byte order mark, and both.
+func crlfVariants(lf string) map[string]string {
+	return map[string]string{
+		"CRLF":     crlf(lf),
+		"BOM":      "\ufeff" + lf,
+		"BOM+CRLF": "\ufeff" + crlf(lf),
+	}
+}
+
+// restyle writes lf, an LF document, in the style of variant.
+func restyle(variant, lf string) string {
+	if strings.Contains(variant, "\r\n") {
+		lf = crlf(lf)
+	}
+	if strings.HasPrefix(variant, "\ufeff") {
+		lf = "\ufeff" + lf
+	}
+	return lf
+}
+
+func TestMutations_PreserveLineStyle(t *testing.T) {
+	madrFull := func(t *testing.T) *adr.ProjectTemplate { return builtinTemplate(t, "madr-full") }
+	record := &adr.ADR{Number: 7, Title: "Use Go", Status: adr.Accepted, Date: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name    string
+		content string
+		mutate  func(t *testing.T, content string) string
+	}{
+		{"UpdateStatus", lfNygard, func(t *testing.T, c string) string {
+			out, err := adr.UpdateStatus(c, "accepted")
+			require.NoError(t, err)
+			return out
+		}},
+		{"UpdateStatus frontmatter", lfMADR, func(t *testing.T, c string) string {
+			out, err := adr.UpdateStatus(c, "accepted")
+			require.NoError(t, err)
+			return out
+		}},
+		{"SetSupersededBy", lfNygard, func(t *testing.T, c string) string {
+			out, err := adr.SetSupersededBy(c, adr.ADRLink{Number: 3, Filename: "0003-x.md"})
+			require.NoError(t, err)
+			return out
+		}},
+		{"SetSupersedes", lfMADR, func(t *testing.T, c string) string {
+			out, err := adr.SetSupersedes(c, []adr.ADRLink{{Number: 1, Filename: "0001-x.md"}})
+			require.NoError(t, err)
+			return out
+		}},
+		{"AddRelation append", lfNygard, func(t *testing.T, c string) string {
+			out, err := adr.AddRelation(c, adr.ADRLink{Number: 4, Filename: "0004-x.md"})
+			require.NoError(t, err)
+			return out
+		}},
+		{"AddRelation insert", lfMADR, func(t *testing.T, c string) string {
+			out, err := adr.AddRelation(c, adr.ADRLink{Number: 4, Filename: "0004-x.md"})
+			require.NoError(t, err)
+			return out
+		}},
+		{"RewriteADRLinks", lfNygard, func(t *testing.T, c string) string {
+			out, n := adr.RewriteADRLinks(c, adr.ADRLink{Number: 2, Filename: "0002-use-chi.md"}, adr.ADRLink{Number: 5, Filename: "0005-use-chi.md"})
+			assert.Equal(t, 1, n)
+			return out
+		}},
+		{"ReplaceSectionContent", lfNygard, func(t *testing.T, c string) string {
+			out, found := adr.ReplaceSectionContent(c, "Context", "New context.\r\n\r\nMore.")
+			require.True(t, found)
+			return out
+		}},
+		{"ReplaceMetaField", lfNygard, func(t *testing.T, c string) string {
+			out, found := adr.ReplaceMetaField(c, "Scope", "Frontend")
+			require.True(t, found)
+			return out
+		}},
+		{"SetFrontmatterField", lfMADR, func(t *testing.T, c string) string {
+			out, found := adr.SetFrontmatterField(c, "consulted", "Bob")
+			require.True(t, found)
+			return out
+		}},
+		{"SetFrontmatterList", lfMADR, func(t *testing.T, c string) string {
+			out, found := adr.SetFrontmatterList(c, "deciders", []string{"Alice", "Bob"})
+			require.True(t, found)
+			return out
+		}},
+		{"ApplySections", lfMADR, func(t *testing.T, c string) string {
+			defs := []adr.TemplateSectionDef{
+				{Key: "context-and-problem-statement", Heading: "Context and Problem Statement", Kind: "h2"},
+				{Key: "deciders", Heading: "deciders", Kind: "frontmatter"},
+			}
+			return adr.ApplySections(c, defs, map[string]string{"context-and-problem-statement": "Routing.", "deciders": "Alice, Carol"})
+		}},
+		{"ReplaceHeading", lfNygard, func(t *testing.T, c string) string {
+			out, found := adr.ReplaceHeading(c, 1, "Use Rust")
+			require.True(t, found)
+			return out
+		}},
+		{"RenderTemplate", lfNygard, func(t *testing.T, c string) string {
+			return adr.RenderTemplate(c, record)
+		}},
+		{"ConvertContent", lfNygard, func(t *testing.T, c string) string {
+			out, err := adr.ConvertContent(c, 1, madrFull(t))
+			require.NoError(t, err)
+			return out
+		}},
+		{"ReplaceTOCBlock", "# Decisions\n\n" + adr.TOCStartMarker + "\nold\n" + adr.TOCEndMarker + "\n", func(t *testing.T, c string) string {
+			out, err := adr.ReplaceTOCBlock(c, adr.TOCStartMarker+"\nnew\n"+adr.TOCEndMarker+"\n")
+			require.NoError(t, err)
+			return out
+		}},
+		{"Document.String", lfNygard, func(t *testing.T, c string) string {
+			doc := adr.ParseDocument(c, nil)
+			doc.Sections[0].Body = "Accepted"
+			return doc.String()
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.mutate(t, tt.content)
+			require.NotEqual(t, tt.content, want)
+			require.NotContains(t, want, "\r")
+			for variant, content := range crlfVariants(tt.content) {
+				assert.Equal(t, restyle(content, want), tt.mutate(t, content), variant)
+			}
+		})
+	}
+}
+
+func TestReaders_IgnoreLineStyle(t *testing.T) {
+	for _, lf := range []string{lfNygard, lfMADR} {
+		for variant, content := range crlfVariants(lf) {
+			assert.Equal(t, adr.ExtractMetadata(lf), adr.ExtractMetadata(content), variant)
+			assert.Equal(t, adr.ExtractRelations(lf), adr.ExtractRelations(content), variant)
+			assert.Equal(t, adr.ExtractMetaFields(lf), adr.ExtractMetaFields(content), variant)
+			assert.Equal(t, adr.DeriveTemplateSections(lf), adr.DeriveTemplateSections(content), variant)
+			assert.Equal(t, content, adr.ParseDocument(content, nil).String(), variant)
+		}
+	}
+	meta := adr.ExtractMetadata("\ufeff" + crlf(lfMADR))
+	assert.Equal(t, "proposed", meta.Status)
+	assert.Equal(t, "Use Chi", meta.Title)
+	assert.Equal(t, []adr.Relation{{Kind: adr.RelationRelatesTo, Number: 2, Path: "0002-use-chi.md"}}, adr.ExtractRelations(crlf(lfNygard)))
+	scope, ok := adr.ExtractScope(crlf(lfNygard))
+	assert.True(t, ok)
+	assert.Equal(t, "Backend", scope)
+}
+
+func TestReaders_MixedLineEndings(t *testing.T) {
+	mixed := strings.Replace(crlf(lfNygard), "## Status\r\n", "## Status\n", 1)
+	assert.Equal(t, adr.ExtractMetadata(lfNygard), adr.ExtractMetadata(mixed))
+	assert.Equal(t, adr.ExtractRelations(lfNygard), adr.ExtractRelations(mixed))
+	doc := adr.ParseDocument(mixed, nil)
+	assert.Equal(t, mixed, doc.String())
+	doc.Sections[0].Body = "Accepted"
+	assert.Equal(t, crlf(strings.Replace(lfNygard, "Proposed", "Accepted", 1)), doc.String())
+
+	out, err := adr.UpdateStatus(mixed, "rejected")
+	require.NoError(t, err)
+	assert.Contains(t, out, "## Status\r\n\r\nRejected\r\n")
+	assert.NotContains(t, strings.ReplaceAll(out, "\r\n", ""), "\n")
+}
+
+func TestMatchLineStyle(t *testing.T) {
+	assert.Equal(t, "a\r\nb\r\n", adr.MatchLineStyle("x\r\ny\r\n", "a\nb\n"))
+	assert.Equal(t, "\ufeffa\nb\n", adr.MatchLineStyle("\ufeffx\n", "a\r\nb\r\n"))
+	assert.Equal(t, "a\nb\n", adr.MatchLineStyle("x\n", "a\nb\n"))
+	// Mixed line endings are written in the majority style, LF on a tie.
+	assert.Equal(t, "a\r\nb\r\n", adr.MatchLineStyle("x\r\ny\r\nz\n", "a\nb\n"))
+	assert.Equal(t, "a\nb\n", adr.MatchLineStyle("x\r\ny\nz\n", "a\r\nb\n"))
+	assert.Equal(t, "a\nb\n", adr.MatchLineStyle("x\r\ny\n", "a\nb\n"))
+}
+
+func TestFileRepository_KeepsCRLFFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "0001-use-go.md")
+	require.NoError(t, os.WriteFile(path, []byte("\ufeff"+crlf(lfNygard)), 0o644))
+	repo := adr.NewFileRepository(dir)
+
+	record, err := repo.UpdateStatus(context.Background(), 1, "accepted")
+	require.NoError(t, err)
+	assert.Equal(t, adr.Accepted, record.Status)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "\ufeff# 1. Use Go\r\n"))
+	assert.Contains(t, string(data), "## Status\r\n\r\nAccepted\r\n\r\n## Relations")
+
+	_, err = repo.UpdateContent(context.Background(), 1, "# 1. Use Go\n\n## Status\n\nRejected\n")
+	require.NoError(t, err)
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "\ufeff# 1. Use Go\r\n\r\n## Status\r\n\r\nRejected\r\n", string(data))
+}
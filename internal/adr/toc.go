@@ -0,0 +1,188 @@
+package adr
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Markers delimiting the table of contents block maintained by `adr toc`.
+const (
+	TOCStartMarker = "<!-- adr-toc:start -->"
+	TOCEndMarker   = "<!-- adr-toc:end -->"
+)
+
+// TOC grouping modes.
+const (
+	TOCGroupStatus = "status"
+	TOCGroupScope  = "scope"
+	TOCGroupNone   = "none"
+)
+
+// TOCOptions configures GenerateTOC.
+type TOCOptions struct {
+	// GroupBy is TOCGroupStatus (the default), TOCGroupScope or TOCGroupNone.
+	GroupBy string
+	// LinkPrefix is prepended to ADR filenames in links, e.g. "docs/adr/"
+	// for a table of contents outside the ADR directory.
+	LinkPrefix string
+}
+
+// tocEntry is one ADR in the table of contents.
+type tocEntry struct {
+	record       ADR
+	file         string
+	supersededBy []int
+}
+
+// GenerateTOC returns the table of contents block for the ADRs of repo,
+// markers included: a list of links with dates and superseded-by pointers,
+// grouped under a heading per status (in lifecycle order) or scope. Links are
+// labelled by the repository's naming.
+func GenerateTOC(ctx context.Context, repo *FileRepository, opts TOCOptions) (string, error) {
+	switch opts.GroupBy {
+	case "":
+		opts.GroupBy = TOCGroupStatus
+	case TOCGroupStatus, TOCGroupScope, TOCGroupNone:
+	default:
+		return "", fmt.Errorf("invalid grouping %q: expected status, scope, or none", opts.GroupBy)
+	}
+
+	records, err := repo.List(ctx)
+	if err != nil {
+		return "", err
+	}
+	names, err := repo.Filenames()
+	if err != nil {
+		return "", err
+	}
+
+	entries := make(map[int]*tocEntry, len(records))
+	for _, r := range records {
+		entries[r.Number] = &tocEntry{record: r, file: names[r.Number]}
+	}
+	// Superseded-by pointers, from either side of the link.
+	addSuperseder := func(e *tocEntry, n int) {
+		for _, m := range e.supersededBy {
+			if m == n {
+				return
+			}
+		}
+		e.supersededBy = append(e.supersededBy, n)
+	}
+	for _, r := range records {
+		content, err := os.ReadFile(filepath.Join(repo.dir, entries[r.Number].file))
+		if err != nil {
+			return "", fmt.Errorf("reading ADR: %w", err)
+		}
+		for _, rel := range repo.Relations(entries[r.Number].file, string(content)) {
+			target, ok := entries[rel.Number]
+			if !ok || rel.Number == r.Number {
+				continue
+			}
+			switch rel.Kind {
+			case RelationSupersededBy:
+				addSuperseder(entries[r.Number], rel.Number)
+			case RelationSupersedes:
+				addSuperseder(target, r.Number)
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(TOCStartMarker + "\n")
+	b.WriteString("<!-- Generated by `adr toc`; edits between these markers are overwritten. -->\n\n")
+	if len(records) == 0 {
+		b.WriteString("_No ADRs yet._\n\n")
+	}
+	writeList := func(list []ADR) {
+		for _, r := range list {
+			b.WriteString(tocLine(entries[r.Number], entries, opts.LinkPrefix, repo.naming) + "\n")
+		}
+		b.WriteString("\n")
+	}
+	switch opts.GroupBy {
+	case TOCGroupNone:
+		if len(records) > 0 {
+			writeList(records)
+		}
+	case TOCGroupStatus:
+		statuses := AllStatuses()
+		sort.Slice(statuses, func(i, j int) bool { return statuses[i].LifecycleOrder() < statuses[j].LifecycleOrder() })
+		for _, s := range statuses {
+			var list []ADR
+			for _, r := range records {
+				if r.Status == s {
+					list = append(list, r)
+				}
+			}
+			if len(list) > 0 {
+				b.WriteString("## " + s.String() + "\n\n")
+				writeList(list)
+			}
+		}
+	case TOCGroupScope:
+		for _, scope := range MetaValues(records, "scope") {
+			b.WriteString("## " + scope + "\n\n")
+			writeList(FilterByMetaField(records, "scope", []string{scope}, false))
+		}
+		var unscoped []ADR
+		for _, r := range records {
+			if len(r.Meta["scope"]) == 0 {
+				unscoped = append(unscoped, r)
+			}
+		}
+		if len(unscoped) > 0 {
+			b.WriteString("## Unscoped\n\n")
+			writeList(unscoped)
+		}
+	}
+	b.WriteString(TOCEndMarker + "\n")
+	return b.String(), nil
+}
+
+// tocLine formats an entry as
+// "- [ADR-0001: Use MySQL](0001-use-mysql.md) — 2024-01-01, superseded by [ADR-0002](…)",
+// with the labels of naming.
+func tocLine(e *tocEntry, entries map[int]*tocEntry, prefix string, naming Naming) string {
+	title := strings.NewReplacer("[", `\[`, "]", `\]`).Replace(e.record.Title)
+	line := fmt.Sprintf("- [%s: %s](%s%s)", naming.Label(e.record.Number), title, prefix, e.file)
+	var notes []string
+	if !e.record.Date.IsZero() {
+		notes = append(notes, e.record.Date.Format("2006-01-02"))
+	}
+	sort.Ints(e.supersededBy)
+	for _, n := range e.supersededBy {
+		notes = append(notes, "superseded by "+formatADRLink(naming.Link(n, prefix+entries[n].file)))
+	}
+	if len(notes) > 0 {
+		line += " — " + strings.Join(notes, ", ")
+	}
+	return line
+}
+
+// ReplaceTOCBlock returns content with its table of contents block (from
+// TOCStartMarker to TOCEndMarker) replaced by block, which is appended when
+// content has none.
+func ReplaceTOCBlock(content, block string) (string, error) {
+	content, style := normalizeText(content)
+	start := strings.Index(content, TOCStartMarker)
+	if start < 0 {
+		if strings.TrimSpace(content) == "" {
+			return style.restore(block), nil
+		}
+		return style.restore(strings.TrimRight(content, "\n") + "\n\n" + block), nil
+	}
+	end := strings.Index(content[start:], TOCEndMarker)
+	if end < 0 {
+		return "", fmt.Errorf("found %s without a matching %s", TOCStartMarker, TOCEndMarker)
+	}
+	end += start + len(TOCEndMarker)
+	if strings.HasPrefix(content[end:], "\n") {
+		end++
+	}
+	return style.restore(content[:start] + block + content[end:]), nil
+}
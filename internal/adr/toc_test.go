@@ -0,0 +1,106 @@
+package adr_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTOCFixture(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"0001-use-mysql.md":      "# 1. Use MySQL\n\nDate: 2024-01-01\n\nScope: Data\n\n## Status\n\nAccepted\n",
+		"0002-use-postgresql.md": "# 2. Use PostgreSQL\n\nDate: 2024-02-01\n\nScope: Data, Backend\n\n## Status\n\nAccepted\n\nSupersedes [ADR-0001](0001-use-mysql.md)  \n",
+		"0003-try-[x].md":        "# 3. Try [x]\n\n## Status\n\nProposed\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	return dir
+}
+
+func TestGenerateTOC_ByStatus(t *testing.T) {
+	dir := writeTOCFixture(t)
+
+	got, err := adr.GenerateTOC(context.Background(), adr.NewFileRepository(dir), adr.TOCOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, adr.TOCStartMarker+"\n"+
+		"<!-- Generated by `adr toc`; edits between these markers are overwritten. -->\n\n"+
+		"## Proposed\n\n"+
+		"- [ADR-0003: Try \\[x\\]](0003-try-[x].md)\n\n"+
+		"## Accepted\n\n"+
+		"- [ADR-0001: Use MySQL](0001-use-mysql.md) — 2024-01-01, superseded by [ADR-0002](0002-use-postgresql.md)\n"+
+		"- [ADR-0002: Use PostgreSQL](0002-use-postgresql.md) — 2024-02-01\n\n"+
+		adr.TOCEndMarker+"\n", got)
+}
+
+func TestGenerateTOC_ByScopeWithPrefix(t *testing.T) {
+	dir := writeTOCFixture(t)
+
+	got, err := adr.GenerateTOC(context.Background(), adr.NewFileRepository(dir), adr.TOCOptions{GroupBy: adr.TOCGroupScope, LinkPrefix: "docs/adr/"})
+	require.NoError(t, err)
+	assert.Contains(t, got, "## Backend\n\n- [ADR-0002: Use PostgreSQL](docs/adr/0002-use-postgresql.md) — 2024-02-01\n\n## Data\n\n- [ADR-0001")
+	assert.Contains(t, got, "superseded by [ADR-0002](docs/adr/0002-use-postgresql.md)")
+	assert.Contains(t, got, "## Unscoped\n\n- [ADR-0003")
+}
+
+func TestGenerateTOC_ConfiguredNaming(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"API-001-use-mysql.md":      "# 1. Use MySQL\n\n## Status\n\nAccepted\n",
+		"API-002-use-postgresql.md": "# 2. Use PostgreSQL\n\n## Status\n\nAccepted\n\nSupersedes [API-001](API-001-use-mysql.md)  \n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	repo := adr.Naming{Prefix: "API-", NumberWidth: 3}.Repository(dir)
+	got, err := adr.GenerateTOC(context.Background(), repo, adr.TOCOptions{GroupBy: adr.TOCGroupNone})
+	require.NoError(t, err)
+	assert.Contains(t, got, "- [API-001: Use MySQL](API-001-use-mysql.md) — superseded by [API-002](API-002-use-postgresql.md)\n"+
+		"- [API-002: Use PostgreSQL](API-002-use-postgresql.md)\n")
+}
+
+func TestGenerateTOC_IgnoresLinksIntoOtherRoots(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"docs/adr/0001-use-adyen.md": "# 1. Use Adyen\n\n## Status\n\nAccepted\n",
+		"docs/adr/0002-use-kafka.md": "# 2. Use Kafka\n\n## Status\n\nAccepted\n\n" +
+			"Supersedes [payments:ADR-0001](../../services/pay/adr/0001-use-stripe.md)  \n",
+	})
+
+	repo := adr.NewFileRepository(filepath.Join(dir, "docs", "adr"))
+	got, err := adr.GenerateTOC(context.Background(), repo, adr.TOCOptions{GroupBy: adr.TOCGroupNone})
+	require.NoError(t, err)
+	assert.Contains(t, got, "- [ADR-0001: Use Adyen](0001-use-adyen.md)\n")
+}
+
+func TestGenerateTOC_Empty(t *testing.T) {
+	got, err := adr.GenerateTOC(context.Background(), adr.NewFileRepository(t.TempDir()), adr.TOCOptions{GroupBy: adr.TOCGroupNone})
+	require.NoError(t, err)
+	assert.Contains(t, got, "_No ADRs yet._\n\n"+adr.TOCEndMarker)
+
+	_, err = adr.GenerateTOC(context.Background(), adr.NewFileRepository(t.TempDir()), adr.TOCOptions{GroupBy: "colour"})
+	assert.ErrorContains(t, err, `invalid grouping "colour"`)
+}
+
+func TestReplaceTOCBlock(t *testing.T) {
+	block := adr.TOCStartMarker + "\nnew\n" + adr.TOCEndMarker + "\n"
+
+	got, err := adr.ReplaceTOCBlock("# Decisions\n\nIntro.\n\n"+adr.TOCStartMarker+"\nold\n"+adr.TOCEndMarker+"\n\nFooter.\n", block)
+	require.NoError(t, err)
+	assert.Equal(t, "# Decisions\n\nIntro.\n\n"+block+"\nFooter.\n", got)
+
+	got, err = adr.ReplaceTOCBlock("# Decisions\n", block)
+	require.NoError(t, err)
+	assert.Equal(t, "# Decisions\n\n"+block, got)
+
+	_, err = adr.ReplaceTOCBlock(adr.TOCStartMarker+"\nold\n", block)
+	assert.ErrorContains(t, err, "without a matching")
+}
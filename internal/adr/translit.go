@@ -0,0 +1,112 @@
+package adr
+
+import (
+	"strings"
+	"unicode"
+)
+
+// transliterations maps the ASCII spelling Slugify uses for a letter to the
+// lowercase letters written that way, grouped by script. Latin letters lose
+// their diacritics, except the German umlauts and ß; Greek and Cyrillic
+// (Russian, Ukrainian, Belarusian and South Slavic letters) follow common
+// romanizations.
+var transliterations = []map[string]string{
+	// Latin
+	{
+		"a":   "àáâãåāăąǎǟǡǻȁȃȧɐɑɒḁạảấầẩẫậắằẳẵặ",
+		"ae":  "äæǣǽ",
+		"b":   "ƀƃɓḃḅḇ",
+		"c":   "çćĉċčƈȼḉ",
+		"d":   "ðďđƌȡɖɗḋḍḏḑḓ",
+		"dz":  "ǆǳ",
+		"e":   "èéêëēĕėęěǝȅȇȩɇəɛḕḗḙḛḝẹẻẽếềểễệ",
+		"f":   "ƒḟ",
+		"ff":  "ﬀ",
+		"ffi": "ﬃ",
+		"ffl": "ﬄ",
+		"fi":  "ﬁ",
+		"fl":  "ﬂ",
+		"g":   "ĝğġģǥǧǵɣḡ",
+		"h":   "ĥħȟḣḥḧḩḫẖ",
+		"i":   "ìíîïĩīĭįıǐȉȋɨɩḭḯỉị",
+		"ij":  "ĳ",
+		"j":   "ĵǰȷɉ",
+		"k":   "ķĸƙǩḱḳḵ",
+		"l":   "ĺļľŀłƚȴḷḹḻḽ",
+		"lj":  "ǉ",
+		"m":   "ɯḿṁṃ",
+		"n":   "ñńņňŉǹȵɲṅṇṉṋ",
+		"ng":  "ŋ",
+		"nj":  "ǌ",
+		"o":   "òóôõøōŏőơǒǫǭǿȍȏȫȭȯȱɔɵṍṏṑṓọỏốồổỗộớờởỡợ",
+		"oe":  "öœ",
+		"oi":  "ƣ",
+		"ou":  "ȣ",
+		"p":   "ƥṕṗ",
+		"r":   "ŕŗřȑȓɍṙṛṝṟ",
+		"s":   "śŝşšſșȿṡṣṥṧṩẛ",
+		"sh":  "ʃ",
+		"ss":  "ß",
+		"st":  "ﬅﬆ",
+		"t":   "ţťŧƫƭțȶʈṫṭṯṱẗ",
+		"th":  "þ",
+		"u":   "ùúûũūŭůűųưǔǖǘǚǜȕȗʉʊṳṵṷṹṻụủứừửữự",
+		"ue":  "ü",
+		"v":   "ʋṽṿ",
+		"w":   "ŵƿẁẃẅẇẉẘ",
+		"x":   "ẋẍ",
+		"y":   "ýÿŷƴȝȳɏẏẙỳỵỷỹ",
+		"z":   "źżžƶȥẑẓẕ",
+		"zh":  "ǯʒ",
+	},
+	// Greek
+	{
+		"a": "αά", "v": "β", "g": "γ", "d": "δ", "e": "εέ", "z": "ζ", "i": "ηήιίϊΐ",
+		"th": "θ", "k": "κ", "l": "λ", "m": "μ", "n": "ν", "x": "ξ", "o": "οόωώ",
+		"p": "π", "r": "ρ", "s": "σς", "t": "τ", "y": "υύϋΰ", "f": "φ", "ch": "χ", "ps": "ψ",
+	},
+	// Cyrillic
+	{
+		"a": "а", "b": "б", "v": "в", "g": "гґ", "d": "д", "e": "еэ", "yo": "ё", "ye": "є",
+		"zh": "ж", "z": "з", "i": "иі", "yi": "ї", "y": "йы", "j": "ј", "k": "к", "l": "л",
+		"m": "м", "n": "н", "o": "о", "p": "п", "r": "р", "s": "с", "t": "т", "u": "уў",
+		"f": "ф", "kh": "х", "ts": "ц", "ch": "ч", "sh": "ш", "shch": "щ", "yu": "ю",
+		"ya": "я", "dj": "ђ", "gj": "ѓ", "dz": "ѕџ", "lj": "љ", "nj": "њ", "c": "ћ", "kj": "ќ",
+		"": "ъь",
+	},
+}
+
+// transliteration is transliterations indexed by letter.
+var transliteration = func() map[rune]string {
+	m := make(map[rune]string)
+	for _, script := range transliterations {
+		for ascii, letters := range script {
+			for _, r := range letters {
+				m[r] = ascii
+			}
+		}
+	}
+	return m
+}()
+
+// transliterate lowercases s and spells its Latin, Greek and Cyrillic letters
+// in ASCII. Combining marks are dropped, so decomposed letters (as macOS
+// writes them) come out like composed ones: "u" and a combining diaeresis
+// give "ue". Other characters are kept as they are.
+func transliterate(s string) string {
+	var b strings.Builder
+	var last rune
+	for _, r := range strings.ToLower(s) {
+		switch ascii, ok := transliteration[r]; {
+		case ok:
+			b.WriteString(ascii)
+		case r == '\u0308' && strings.ContainsRune("aou", last):
+			b.WriteByte('e')
+		case unicode.Is(unicode.Mn, r):
+		default:
+			b.WriteRune(r)
+		}
+		last = r
+	}
+	return b.String()
+}
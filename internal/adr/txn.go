@@ -0,0 +1,254 @@
+package adr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// JournalFileName is the transaction journal kept in the ADR directory while a
+// multi-file change (rename, renumber, …) is being applied. Its leading dot
+// keeps it out of ADR discovery.
+const JournalFileName = ".adr-journal.json"
+
+// ErrInvalidJournal is returned by RecoverTxn for a journal naming a path
+// outside the directories a change may touch.
+var ErrInvalidJournal = errors.New("invalid journal")
+
+// fileTxn is a multi-file change to an ADR directory: file renames followed by
+// full-content writes. Paths are relative to the directory; a write may lead
+// out of it, into another root's or project's directory whose ADRs link to
+// the changed one (see FileRepository.rewriteInboundLinks). The whole plan is
+// journaled before any file is touched and every step is idempotent, so an
+// interrupted commit is completed by replaying the journal (RecoverTxn) rather
+// than leaving, say, a renamed file with half its inbound links rewritten.
+type fileTxn struct {
+	Renames []txnRename `json:"renames,omitempty"`
+	Writes  []txnWrite  `json:"writes,omitempty"`
+	// Renamed counts the renames done, as the journal is updated after each.
+	Renamed int `json:"renamed,omitempty"`
+}
+
+type txnRename struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type txnWrite struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+func (t *fileTxn) rename(from, to string) {
+	t.Renames = append(t.Renames, txnRename{From: from, To: to})
+}
+
+func (t *fileTxn) write(path, content string) {
+	t.Writes = append(t.Writes, txnWrite{Path: path, Content: content})
+}
+
+// commit journals the transaction in dir, applies it, and removes the journal.
+// It refuses to start while another journal is pending (run RecoverTxn first)
+// and to rename a file onto an existing one.
+func (t *fileTxn) commit(dir string) error {
+	journal := filepath.Join(dir, JournalFileName)
+	if _, err := os.Stat(journal); err == nil {
+		return fmt.Errorf("an interrupted change is pending in %q; recover it first", dir)
+	}
+	if err := t.checkTargets(dir); err != nil {
+		return err
+	}
+	if err := t.journal(dir); err != nil {
+		return err
+	}
+	return t.apply(dir)
+}
+
+// txnPath returns the path of file relative to dir, as a transaction journaled
+// in dir names it; it leads out of dir for a file in another directory.
+func txnPath(dir, file string) (string, error) {
+	base, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(base, abs)
+	if err != nil {
+		return "", fmt.Errorf("locating %q: %w", file, err)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// checkTargets refuses renames onto existing files, which os.Rename would
+// silently replace. A target freed by an earlier rename of the transaction is
+// fine, as is the renamed file itself (a case-only rename on a
+// case-insensitive file system).
+func (t *fileTxn) checkTargets(dir string) error {
+	freed := map[string]bool{}
+	for _, r := range t.Renames {
+		if to, err := os.Stat(filepath.Join(dir, r.To)); err == nil && !freed[r.To] {
+			from, err := os.Stat(filepath.Join(dir, r.From))
+			if err != nil || !os.SameFile(from, to) {
+				return fmt.Errorf("cannot rename %s: %s already exists: %w", r.From, r.To, ErrConflict)
+			}
+		}
+		freed[r.From] = true
+	}
+	return nil
+}
+
+// checkPaths refuses a transaction with a path that leads neither into dir
+// nor into one of the linked directories.
+func (t *fileTxn) checkPaths(dir string, linked []string) error {
+	var allowed []string
+	for _, d := range append([]string{dir}, linked...) {
+		abs, err := filepath.Abs(d)
+		if err != nil {
+			return err
+		}
+		allowed = append(allowed, abs)
+	}
+	check := func(p string) error {
+		if p != "" && !filepath.IsAbs(filepath.FromSlash(p)) {
+			file := filepath.Join(allowed[0], filepath.FromSlash(p))
+			for _, d := range allowed {
+				if rel, err := filepath.Rel(d, file); err == nil && filepath.IsLocal(rel) {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("journal path %q leads out of the ADR directories: %w", p, ErrInvalidJournal)
+	}
+	for _, r := range t.Renames {
+		if err := check(r.From); err != nil {
+			return err
+		}
+		if err := check(r.To); err != nil {
+			return err
+		}
+	}
+	for _, w := range t.Writes {
+		if err := check(w.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply performs every step of the transaction and then removes the journal.
+// The journal counts the renames done, so a replay repeats at most the rename
+// interrupted by a crash, which is then found done when its file is at the
+// new name only. A renamed file that is found at neither name was deleted
+// meanwhile; its rename and writes are skipped rather than failing, which
+// would keep the journal pending for good.
+func (t *fileTxn) apply(dir string) error {
+	deleted := map[string]bool{}
+	for t.Renamed < len(t.Renames) {
+		r := t.Renames[t.Renamed]
+		from, to := filepath.Join(dir, r.From), filepath.Join(dir, r.To)
+		_, ferr := os.Stat(from)
+		_, terr := os.Stat(to)
+		switch {
+		case errors.Is(ferr, os.ErrNotExist) && terr == nil:
+			// renamed before the interruption
+		case errors.Is(ferr, os.ErrNotExist) && errors.Is(terr, os.ErrNotExist):
+			deleted[r.To] = true
+		default:
+			if err := os.MkdirAll(filepath.Dir(to), 0o755); err != nil {
+				return fmt.Errorf("creating directory for %q: %w", r.To, err)
+			}
+			if err := os.Rename(from, to); err != nil {
+				return fmt.Errorf("renaming %q to %q: %w", r.From, r.To, err)
+			}
+		}
+		t.Renamed++
+		if err := t.journal(dir); err != nil {
+			return err
+		}
+	}
+	for _, w := range t.Writes {
+		if deleted[w.Path] {
+			continue
+		}
+		if err := writeFileAtomic(filepath.Join(dir, w.Path), []byte(w.Content)); err != nil {
+			return fmt.Errorf("writing %q: %w", w.Path, err)
+		}
+	}
+	if err := os.Remove(filepath.Join(dir, JournalFileName)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("removing journal: %w", err)
+	}
+	return nil
+}
+
+// journal writes the transaction, with its progress, to its journal in dir.
+func (t *fileTxn) journal(dir string) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("encoding journal: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(dir, JournalFileName), data); err != nil {
+		return fmt.Errorf("writing journal: %w", err)
+	}
+	return nil
+}
+
+// RecoverTxn completes a multi-file change that was interrupted (e.g. by a
+// crash) in dir, by replaying its journal. It reports whether a journal was
+// found; with none pending it is a no-op. The journal's paths must lead into
+// dir or one of the linked directories, whose ADRs a change may rewrite links
+// in (see FileRepository.Recover); a journal leading anywhere else is refused
+// with ErrInvalidJournal and left in place.
+func RecoverTxn(dir string, linked ...string) (bool, error) {
+	data, err := os.ReadFile(filepath.Join(dir, JournalFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("reading journal: %w", err)
+	}
+	var t fileTxn
+	if err := json.Unmarshal(data, &t); err != nil {
+		return true, fmt.Errorf("parsing journal %q: %w", JournalFileName, err)
+	}
+	if t.Renamed < 0 || t.Renamed > len(t.Renames) {
+		return true, fmt.Errorf("journal counts %d of %d renames done: %w", t.Renamed, len(t.Renames), ErrInvalidJournal)
+	}
+	if err := t.checkPaths(dir, linked); err != nil {
+		return true, err
+	}
+	return true, t.apply(dir)
+}
+
+// writeFileAtomic writes data to a temporary file beside path and renames it
+// into place, so readers never observe a partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".adr-tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if err := os.Chmod(tmpName, 0o644); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	return os.Rename(tmpName, path)
+}
@@ -0,0 +1,237 @@
+package adr
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileTxn_CommitAppliesRenamesThenWrites(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "0001-old.md", "old")
+	writeFile(t, dir, "0002-other.md", "link to 0001-old.md")
+
+	var txn fileTxn
+	txn.rename("0001-old.md", "0001-new.md")
+	txn.write("0001-new.md", "new")
+	txn.write("0002-other.md", "link to 0001-new.md")
+	require.NoError(t, txn.commit(dir))
+
+	_, err := os.Stat(filepath.Join(dir, "0001-old.md"))
+	assert.True(t, os.IsNotExist(err))
+	assert.Equal(t, "new", readFile(t, dir, "0001-new.md"))
+	assert.Equal(t, "link to 0001-new.md", readFile(t, dir, "0002-other.md"))
+	_, err = os.Stat(filepath.Join(dir, JournalFileName))
+	assert.True(t, os.IsNotExist(err), "journal should be removed after commit")
+}
+
+func TestRecoverTxn_CompletesInterruptedChange(t *testing.T) {
+	dir := t.TempDir()
+	// Simulate a crash after the rename but before the writes: the journal is
+	// on disk, the file is already at its new name, and the link is stale.
+	writeFile(t, dir, "0001-new.md", "old")
+	writeFile(t, dir, "0002-other.md", "link to 0001-old.md")
+	txn := fileTxn{
+		Renames: []txnRename{{From: "0001-old.md", To: "0001-new.md"}},
+		Writes: []txnWrite{
+			{Path: "0001-new.md", Content: "new"},
+			{Path: "0002-other.md", Content: "link to 0001-new.md"},
+		},
+	}
+	data, err := json.Marshal(txn)
+	require.NoError(t, err)
+	writeFile(t, dir, JournalFileName, string(data))
+
+	recovered, err := RecoverTxn(dir)
+	require.NoError(t, err)
+	assert.True(t, recovered)
+	assert.Equal(t, "new", readFile(t, dir, "0001-new.md"))
+	assert.Equal(t, "link to 0001-new.md", readFile(t, dir, "0002-other.md"))
+	_, err = os.Stat(filepath.Join(dir, JournalFileName))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRecoverTxn_SkipsDeletedFile(t *testing.T) {
+	dir := t.TempDir()
+	// The renamed file was deleted by hand before the journal was replayed.
+	writeFile(t, dir, "0002-other.md", "link to 0001-old.md")
+	txn := fileTxn{
+		Renames: []txnRename{{From: "0001-old.md", To: "0001-new.md"}},
+		Writes: []txnWrite{
+			{Path: "0001-new.md", Content: "new"},
+			{Path: "0002-other.md", Content: "link to 0001-new.md"},
+		},
+	}
+	data, err := json.Marshal(txn)
+	require.NoError(t, err)
+	writeFile(t, dir, JournalFileName, string(data))
+
+	recovered, err := RecoverTxn(dir)
+	require.NoError(t, err)
+	assert.True(t, recovered)
+	_, err = os.Stat(filepath.Join(dir, "0001-new.md"))
+	assert.True(t, os.IsNotExist(err), "a deleted ADR must not be recreated")
+	assert.Equal(t, "link to 0001-new.md", readFile(t, dir, "0002-other.md"))
+	_, err = os.Stat(filepath.Join(dir, JournalFileName))
+	assert.True(t, os.IsNotExist(err))
+
+	var next fileTxn
+	next.write("0003-x.md", "x")
+	assert.NoError(t, next.commit(dir))
+}
+
+func TestRecoverTxn_NoJournalIsNoop(t *testing.T) {
+	recovered, err := RecoverTxn(t.TempDir())
+	require.NoError(t, err)
+	assert.False(t, recovered)
+}
+
+func TestFileTxn_CommitRefusesWhileJournalPending(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, JournalFileName, "{}")
+
+	var txn fileTxn
+	txn.write("0001-x.md", "x")
+	assert.Error(t, txn.commit(dir))
+	_, err := os.Stat(filepath.Join(dir, "0001-x.md"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestFileTxn_CommitRefusesRenameOntoExistingFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "0001-old.md", "old")
+	writeFile(t, dir, "0001-new.md", "taken")
+
+	var txn fileTxn
+	txn.rename("0001-old.md", "0001-new.md")
+	txn.write("0001-new.md", "new")
+	assert.ErrorIs(t, txn.commit(dir), ErrConflict)
+	assert.Equal(t, "taken", readFile(t, dir, "0001-new.md"))
+	assert.Equal(t, "old", readFile(t, dir, "0001-old.md"))
+	assert.NoFileExists(t, filepath.Join(dir, JournalFileName))
+
+	// A target freed by an earlier rename is fine.
+	var chain fileTxn
+	chain.rename("0001-new.md", "0001-newer.md")
+	chain.rename("0001-old.md", "0001-new.md")
+	require.NoError(t, chain.commit(dir))
+	assert.Equal(t, "taken", readFile(t, dir, "0001-newer.md"))
+	assert.Equal(t, "old", readFile(t, dir, "0001-new.md"))
+}
+
+func TestRecoverTxn_ChainedRenamesAfterPartialApply(t *testing.T) {
+	// 0001-b.md moves on to 0001-c.md, freeing its name for 0001-a.md.
+	renames := []txnRename{
+		{From: "0001-b.md", To: "0001-c.md"},
+		{From: "0001-a.md", To: "0001-b.md"},
+	}
+	tests := []struct {
+		name    string
+		files   map[string]string
+		renamed int
+	}{
+		{"interrupted in the first rename", map[string]string{"0001-a.md": "a", "0001-c.md": "b"}, 0},
+		{"interrupted in the second rename", map[string]string{"0001-b.md": "a", "0001-c.md": "b"}, 1},
+		{"interrupted in the writes", map[string]string{"0001-b.md": "a", "0001-c.md": "b"}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				writeFile(t, dir, name, content)
+			}
+			writeFile(t, dir, "0002-other.md", "link to 0001-a.md")
+			txn := fileTxn{
+				Renames: renames,
+				Writes:  []txnWrite{{Path: "0002-other.md", Content: "link to 0001-b.md"}},
+				Renamed: tt.renamed,
+			}
+			data, err := json.Marshal(txn)
+			require.NoError(t, err)
+			writeFile(t, dir, JournalFileName, string(data))
+
+			recovered, err := RecoverTxn(dir)
+			require.NoError(t, err)
+			assert.True(t, recovered)
+			assert.NoFileExists(t, filepath.Join(dir, "0001-a.md"))
+			assert.Equal(t, "a", readFile(t, dir, "0001-b.md"))
+			assert.Equal(t, "b", readFile(t, dir, "0001-c.md"))
+			assert.Equal(t, "link to 0001-b.md", readFile(t, dir, "0002-other.md"))
+			assert.NoFileExists(t, filepath.Join(dir, JournalFileName))
+		})
+	}
+}
+
+func TestRecoverTxn_RefusesPathsOutsideDirectories(t *testing.T) {
+	base := t.TempDir()
+	dir, linked := filepath.Join(base, "docs", "adr"), filepath.Join(base, "services", "adr")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.MkdirAll(linked, 0o755))
+
+	for _, path := range []string{"../evil.md", filepath.Join(base, "evil.md"), "../../services/adr/../../evil.md"} {
+		txn := fileTxn{Writes: []txnWrite{{Path: path, Content: "evil"}}}
+		data, err := json.Marshal(txn)
+		require.NoError(t, err)
+		writeFile(t, dir, JournalFileName, string(data))
+
+		_, err = RecoverTxn(dir, linked)
+		assert.ErrorIs(t, err, ErrInvalidJournal, path)
+		assert.NoFileExists(t, filepath.Join(base, "evil.md"))
+		assert.FileExists(t, filepath.Join(dir, JournalFileName), "a refused journal stays pending")
+	}
+
+	txn := fileTxn{Writes: []txnWrite{{Path: "../../services/adr/0001-x.md", Content: "x"}}}
+	data, err := json.Marshal(txn)
+	require.NoError(t, err)
+	writeFile(t, dir, JournalFileName, string(data))
+	_, err = RecoverTxn(dir)
+	assert.ErrorIs(t, err, ErrInvalidJournal, "a linked directory must be named")
+	_, err = RecoverTxn(dir, linked)
+	require.NoError(t, err)
+	assert.Equal(t, "x", readFile(t, linked, "0001-x.md"))
+}
+
+func readFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestRecoverTxn_CompletesInterruptedRelateAcrossProjects(t *testing.T) {
+	shopDir, billingDir := t.TempDir(), t.TempDir()
+	writeFile(t, shopDir, ConfigFileName, `{"version": "1", "directory": "docs/adr", "template": "nygard"}`)
+	writeFile(t, billingDir, ConfigFileName, `{"version": "1", "directory": "docs/adr", "template": "nygard"}`)
+	shopADRs, billingADRs := filepath.Join(shopDir, "docs", "adr"), filepath.Join(billingDir, "docs", "adr")
+	require.NoError(t, os.MkdirAll(shopADRs, 0o755))
+	require.NoError(t, os.MkdirAll(billingADRs, 0o755))
+	writeFile(t, shopADRs, "0001-use-go.md", "old shop")
+	writeFile(t, billingADRs, "0002-use-invoices.md", "old billing")
+	shop, err := Mount("shop", shopDir)
+	require.NoError(t, err)
+	billing, err := Mount("billing", billingDir)
+	require.NoError(t, err)
+	Federate(shop, billing)
+
+	// Simulate a crash during Config.Relate: the journal in the shop root
+	// names the billing file by its path relative to the shop root.
+	target, err := txnPath(shop.Directory, filepath.Join(billingADRs, "0002-use-invoices.md"))
+	require.NoError(t, err)
+	txn := fileTxn{Writes: []txnWrite{
+		{Path: target, Content: "related billing"},
+		{Path: "0001-use-go.md", Content: "related shop"},
+	}}
+	data, err := json.Marshal(txn)
+	require.NoError(t, err)
+	writeFile(t, shopADRs, JournalFileName, string(data))
+
+	recovered, err := shop.Repository().Recover()
+	require.NoError(t, err)
+	assert.True(t, recovered)
+	assert.Equal(t, "related shop", readFile(t, shopADRs, "0001-use-go.md"))
+	assert.Equal(t, "related billing", readFile(t, billingADRs, "0002-use-invoices.md"))
+}
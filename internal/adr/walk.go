@@ -0,0 +1,240 @@
+package adr
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DefaultArchiveDir is the subdirectory of the ADR directory archived ADRs are
+// moved to when the config doesn't name one.
+const DefaultArchiveDir = "archive"
+
+// Discovery selects the files below the ADR directory that are searched for
+// ADRs; which of them are ADRs is up to the Naming. The zero value searches the
+// directory's own files and its archive directory only.
+type Discovery struct {
+	// Recursive searches subdirectories too, at any depth. Hidden directories
+	// (".git", ".adr-…") are always skipped.
+	Recursive bool
+	// Include, when set, limits the search to files whose path relative to the
+	// directory matches one of its globs. Exclude leaves out the files and
+	// directories matching any of its globs. Globs are slash-separated with
+	// the syntax of path.Match, plus "**" for any number of directories, e.g.
+	// "platform/**" or "**/drafts/*".
+	Include []string
+	Exclude []string
+	// Archive is the slash-separated path, relative to the directory, that
+	// FileRepository.Archive moves inactive ADRs to; DefaultArchiveDir when
+	// empty. It is always searched, at any depth, so archived ADRs keep
+	// resolving by number. Include doesn't apply to it; Exclude does.
+	Archive string
+}
+
+// Discovery returns the project's discovery settings.
+func (c *Config) Discovery() Discovery {
+	return Discovery{Recursive: c.Recursive, Include: c.Include, Exclude: c.Exclude, Archive: c.ArchiveDir}
+}
+
+// archiveDir returns the archive directory, cleaned.
+func (d Discovery) archiveDir() string {
+	if d.Archive == "" {
+		return DefaultArchiveDir
+	}
+	return path.Clean(d.Archive)
+}
+
+// archived reports whether name, relative to the directory, is in the archive
+// directory.
+func (d Discovery) archived(name string) bool {
+	return strings.HasPrefix(name, d.archiveDir()+"/")
+}
+
+// validate reports malformed globs and an archive directory outside the ADR
+// directory.
+func (d Discovery) validate() error {
+	if archive := d.archiveDir(); !filepath.IsLocal(archive) || strings.Contains(archive, `\`) || strings.HasPrefix(archive, ".") {
+		return fmt.Errorf("archive directory %q must be a relative path inside the ADR directory", d.Archive)
+	}
+	for _, globs := range [][]string{d.Include, d.Exclude} {
+		for _, g := range globs {
+			if g == "" || strings.HasPrefix(g, "/") {
+				return fmt.Errorf("glob %q must be a relative path", g)
+			}
+			for _, segment := range strings.Split(g, "/") {
+				if _, err := path.Match(segment, ""); err != nil {
+					return fmt.Errorf("glob %q: %w", g, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// searches reports whether the file at rel, relative to the directory, is
+// searched for ADRs.
+func (d Discovery) searches(rel string) bool {
+	if d.excludes(rel) {
+		return false
+	}
+	if len(d.Include) == 0 {
+		return true
+	}
+	for _, g := range d.Include {
+		if matchGlob(g, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d Discovery) excludes(rel string) bool {
+	for _, g := range d.Exclude {
+		if matchGlob(g, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob reports whether the slash-separated name matches pattern, where
+// "**" matches any number of path elements and other elements match as for
+// path.Match.
+func matchGlob(pattern, name string) bool {
+	return matchElements(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchElements(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchElements(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// adrFile is an ADR markdown file discovered in a directory.
+type adrFile struct {
+	Number int
+	// Name is the slash-separated path relative to the directory: the
+	// filename, for an ADR at the top level.
+	Name string
+	// Archived is set for files in the archive directory.
+	Archived bool
+}
+
+// listADRFiles returns the ADR files below dir that discovery searches and
+// naming matches, parsed and in os.ReadDir order, each directory's files where
+// the directory sorts, followed by the files of the archive directory.
+// Directories, non-ADR names, and files whose number can't be parsed are
+// skipped. When the naming has no {number} placeholder the number comes from
+// the file's "# N. Title" heading, and files without one are skipped too. The
+// raw os.ReadDir error for dir is returned unwrapped so callers can wrap it or
+// check os.IsNotExist; unreadable subdirectories, including a missing archive
+// directory, are skipped.
+//
+// It is deliberately a thin "matches convention + parses to a number" primitive:
+// keep all caller-specific filtering in the callers, not here.
+func listADRFiles(dir string, naming Naming, discovery Discovery) ([]adrFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := discovery.archiveDir()
+	var files []adrFile
+	var walk func(rel string, entries []os.DirEntry, archived bool)
+	walk = func(rel string, entries []os.DirEntry, archived bool) {
+		for _, entry := range entries {
+			name := path.Join(rel, entry.Name())
+			if entry.IsDir() {
+				if !archived && (!discovery.Recursive || name == archive) ||
+					strings.HasPrefix(entry.Name(), ".") || discovery.excludes(name) {
+					continue
+				}
+				if sub, err := os.ReadDir(filepath.Join(dir, filepath.FromSlash(name))); err == nil {
+					walk(name, sub, archived)
+				}
+				continue
+			}
+			if archived && discovery.excludes(name) || !archived && !discovery.searches(name) {
+				continue
+			}
+			n, ok := naming.fileNumber(dir, name)
+			if !ok {
+				continue
+			}
+			files = append(files, adrFile{Number: n, Name: name, Archived: archived})
+		}
+	}
+	walk("", entries, false)
+	if !discovery.excludes(archive) {
+		if sub, err := os.ReadDir(filepath.Join(dir, filepath.FromSlash(archive))); err == nil {
+			walk(archive, sub, true)
+		}
+	}
+	return files, nil
+}
+
+// RelativePath returns the path of the ADR file target as a link from the ADR
+// file from, both slash-separated and relative to the ADR directory: "0002-x.md"
+// between files in the same directory, "../platform/0002-x.md" from a sibling
+// subdirectory.
+func RelativePath(from, target string) string {
+	rel, err := filepath.Rel(filepath.FromSlash(path.Dir(from)), filepath.FromSlash(target))
+	if err != nil {
+		return target
+	}
+	return filepath.ToSlash(rel)
+}
+
+// linkDestinationPattern matches the destination of an inline link or image
+// ("](assets/x.png#y") and of a link reference definition ("[x]: assets/x.png").
+var linkDestinationPattern = regexp.MustCompile(`(?m)\]\(([^)\s]+)|^ {0,3}\[[^\]]+\]:[ \t]*(\S+)`)
+
+// moveLinks rewrites the relative links in content, an ADR moved from the file
+// from to the file to (both relative to the ADR directory), so they still
+// point at the same files: ADRs, images, diagrams or anything else. A link to
+// the file itself follows it. URLs and absolute paths are left as written.
+func moveLinks(content, from, to string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range linkDestinationPattern.FindAllStringSubmatchIndex(content, -1) {
+		start, end := m[2], m[3]
+		if start < 0 {
+			start, end = m[4], m[5]
+		}
+		name, fragment, hasFragment := strings.Cut(content[start:end], "#")
+		if name == "" || strings.Contains(name, ":") || strings.HasPrefix(name, "/") || strings.HasPrefix(name, "<") {
+			continue
+		}
+		target := path.Join(path.Dir(from), name)
+		if target == from {
+			target = to
+		}
+		moved := RelativePath(to, target)
+		if hasFragment {
+			moved += "#" + fragment
+		}
+		b.WriteString(content[last:start])
+		b.WriteString(moved)
+		last = end
+	}
+	b.WriteString(content[last:])
+	return b.String()
+}
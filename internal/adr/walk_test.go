@@ -0,0 +1,204 @@
+package adr_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func nestedTree(t *testing.T) string {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"0001-use-go.md":                    "# 1. Use Go\n\n## Status\n\nAccepted\n",
+		"platform/0002-use-kafka.md":        "# 2. Use Kafka\n\n## Status\n\nAccepted\n",
+		"platform/queues/0005-use-sqs.md":   "# 5. Use SQS\n\n## Status\n\nProposed\n",
+		"frontend/0003-use-vue.md":          "# 3. Use Vue\n\n## Status\n\nAccepted\n",
+		"legacy/0004-use-jquery.md":         "# 4. Use jQuery\n\n## Status\n\nDeprecated\n",
+		"frontend/drafts/0009-use-react.md": "# 9. Use React\n\n## Status\n\nProposed\n",
+		".git/0010-not-an-adr.md":           "# 10. Hidden\n",
+	})
+	return dir
+}
+
+func TestDiscovery_TopLevelByDefault(t *testing.T) {
+	dir := nestedTree(t)
+
+	names, err := (&adr.Config{Directory: dir}).Repository().Filenames()
+	require.NoError(t, err)
+	assert.Equal(t, map[int]string{1: "0001-use-go.md"}, names)
+}
+
+func TestDiscovery_Recursive(t *testing.T) {
+	dir := nestedTree(t)
+	repo := (&adr.Config{Directory: dir, Recursive: true}).Repository()
+
+	names, err := repo.Filenames()
+	require.NoError(t, err)
+	assert.Equal(t, map[int]string{
+		1: "0001-use-go.md",
+		2: "platform/0002-use-kafka.md",
+		3: "frontend/0003-use-vue.md",
+		4: "legacy/0004-use-jquery.md",
+		5: "platform/queues/0005-use-sqs.md",
+		9: "frontend/drafts/0009-use-react.md",
+	}, names)
+
+	next, err := repo.NextNumber(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 10, next, "numbers are unique across subdirectories")
+	record, err := repo.Get(context.Background(), 5)
+	require.NoError(t, err)
+	assert.Equal(t, "Use SQS", record.Title)
+}
+
+func TestDiscovery_IncludeAndExclude(t *testing.T) {
+	dir := nestedTree(t)
+	cfg := &adr.Config{
+		Directory: dir, Recursive: true,
+		Include: []string{"*.md", "platform/**", "frontend/**"},
+		Exclude: []string{"**/drafts", "platform/queues/*.md"},
+	}
+
+	names, err := cfg.Repository().Filenames()
+	require.NoError(t, err)
+	assert.Equal(t, map[int]string{
+		1: "0001-use-go.md",
+		2: "platform/0002-use-kafka.md",
+		3: "frontend/0003-use-vue.md",
+	}, names)
+}
+
+func TestDiscovery_DuplicatesAcrossSubdirectories(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"platform/0002-use-kafka.md": "# 2. Use Kafka\n",
+		"frontend/0002-use-vue.md":   "# 2. Use Vue\n",
+	})
+
+	dups, err := (&adr.Config{Directory: dir, Recursive: true}).Repository().DuplicateNumbers()
+	require.NoError(t, err)
+	assert.Equal(t, map[int][]string{2: {"frontend/0002-use-vue.md", "platform/0002-use-kafka.md"}}, dups)
+}
+
+func TestFileRepository_LinksBetweenSubdirectories(t *testing.T) {
+	dir := nestedTree(t)
+	repo := (&adr.Config{Directory: dir, Recursive: true}).Repository()
+	ctx := context.Background()
+
+	_, err := repo.Supersede(ctx, 2, 5)
+	require.NoError(t, err)
+	assert.Contains(t, readFile(t, filepath.Join(dir, "platform/0002-use-kafka.md")), "Superseded by [ADR-0005](queues/0005-use-sqs.md)")
+	assert.Contains(t, readFile(t, filepath.Join(dir, "platform/queues/0005-use-sqs.md")), "Supersedes [ADR-0002](../0002-use-kafka.md)")
+
+	_, err = repo.AddRelation(ctx, 3, 1)
+	require.NoError(t, err)
+	assert.Contains(t, readFile(t, filepath.Join(dir, "frontend/0003-use-vue.md")), "Relates to [ADR-0001](../0001-use-go.md)")
+	assert.Contains(t, readFile(t, filepath.Join(dir, "0001-use-go.md")), "Relates to [ADR-0003](frontend/0003-use-vue.md)")
+
+	_, err = repo.Rename(ctx, 2, "Use Kafka Streams")
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "platform", "0002-use-kafka-streams.md"))
+	assert.Contains(t, readFile(t, filepath.Join(dir, "platform/queues/0005-use-sqs.md")), "Supersedes [ADR-0002](../0002-use-kafka-streams.md)")
+
+	_, err = repo.Renumber(ctx, 3, 7)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "frontend", "0007-use-vue.md"))
+	assert.Contains(t, readFile(t, filepath.Join(dir, "0001-use-go.md")), "Relates to [ADR-0007](frontend/0007-use-vue.md)")
+}
+
+func TestDiscovery_ArchiveIsAlwaysSearched(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"0001-use-go.md":                    "# 1. Use Go\n\n## Status\n\nAccepted\n",
+		"archive/0002-use-perl.md":          "# 2. Use Perl\n\n## Status\n\nDeprecated\n",
+		"archive/platform/0003-use-amqp.md": "# 3. Use AMQP\n\n## Status\n\nSuperseded\n",
+	})
+	repo := (&adr.Config{Directory: dir}).Repository()
+	ctx := context.Background()
+
+	names, err := repo.Filenames()
+	require.NoError(t, err)
+	assert.Equal(t, map[int]string{1: "0001-use-go.md", 2: "archive/0002-use-perl.md", 3: "archive/platform/0003-use-amqp.md"}, names)
+	next, err := repo.NextNumber(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 4, next, "archived numbers stay taken")
+
+	adrs, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, adrs, 1)
+	assert.Equal(t, 1, adrs[0].Number)
+	adrs, err = repo.ListWithArchived(ctx)
+	require.NoError(t, err)
+	require.Len(t, adrs, 3)
+	assert.False(t, adrs[0].Archived)
+	assert.True(t, adrs[1].Archived)
+
+	record, err := repo.Get(ctx, 2)
+	require.NoError(t, err)
+	assert.True(t, record.Archived)
+	filename, err := adr.FindADRFile(dir, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "archive/platform/0003-use-amqp.md", filename)
+
+	names, err = (&adr.Config{Directory: dir, Exclude: []string{"archive/platform"}}).Repository().Filenames()
+	require.NoError(t, err)
+	assert.Equal(t, map[int]string{1: "0001-use-go.md", 2: "archive/0002-use-perl.md"}, names)
+}
+
+func TestFileRepository_Archive(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"0001-use-go.md": "# 1. Use Go\n\n## Status\n\nAccepted\n\nSupersedes [ADR-0002](0002-use-perl.md)\n",
+		"0002-use-perl.md": "# 2. Use Perl\n\n## Status\n\nSuperseded by [ADR-0001](0001-use-go.md)\n\n" +
+			"## Relations\n\nRelates to [ADR-0003](platform/0003-use-kafka.md)\n",
+		"platform/0003-use-kafka.md": "# 3. Use Kafka\n\n## Status\n\nAccepted\n\nRelates to [ADR-0002](../0002-use-perl.md)\n",
+	})
+	repo := (&adr.Config{Directory: dir, Recursive: true, ArchiveDir: "old"}).Repository()
+	ctx := context.Background()
+
+	record, err := repo.Archive(ctx, 2)
+	require.NoError(t, err)
+	assert.True(t, record.Archived)
+	assert.NoFileExists(t, filepath.Join(dir, "0002-use-perl.md"))
+	archived := readFile(t, filepath.Join(dir, "old/0002-use-perl.md"))
+	assert.Equal(t, archived, record.Content)
+	assert.Contains(t, archived, "Superseded by [ADR-0001](../0001-use-go.md)")
+	assert.Contains(t, archived, "Relates to [ADR-0003](../platform/0003-use-kafka.md)")
+	assert.Contains(t, readFile(t, filepath.Join(dir, "0001-use-go.md")), "Supersedes [ADR-0002](old/0002-use-perl.md)")
+	assert.Contains(t, readFile(t, filepath.Join(dir, "platform/0003-use-kafka.md")), "Relates to [ADR-0002](../old/0002-use-perl.md)")
+
+	_, err = repo.Archive(ctx, 2)
+	assert.ErrorIs(t, err, adr.ErrConflict)
+
+	_, err = repo.Archive(ctx, 3)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "old", "platform", "0003-use-kafka.md"))
+	assert.Contains(t, readFile(t, filepath.Join(dir, "old/0002-use-perl.md")), "Relates to [ADR-0003](platform/0003-use-kafka.md)")
+}
+
+func TestFileRepository_Archive_RewritesOtherLinks(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"platform/0003-use-kafka.md": "# 3. Use Kafka\n\n## Status\n\nAccepted\n\n" +
+			"![topology](../assets/0003-topology.png) [notes](./notes/kafka.txt#sizing) [self](0003-use-kafka.md#status)\n" +
+			"[web](https://example.com/a.png) [top](#status) [spec][spec]\n\n[spec]: ../specs/kafka.pdf\n",
+	})
+	repo := (&adr.Config{Directory: dir, Recursive: true}).Repository()
+
+	record, err := repo.Archive(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, "# 3. Use Kafka\n\n## Status\n\nAccepted\n\n"+
+		"![topology](../../assets/0003-topology.png) [notes](../../platform/notes/kafka.txt#sizing) [self](0003-use-kafka.md#status)\n"+
+		"[web](https://example.com/a.png) [top](#status) [spec][spec]\n\n[spec]: ../../specs/kafka.pdf\n", record.Content)
+}
+
+func TestRelativePath(t *testing.T) {
+	assert.Equal(t, "0002-b.md", adr.RelativePath("0001-a.md", "0002-b.md"))
+	assert.Equal(t, "platform/0002-b.md", adr.RelativePath("0001-a.md", "platform/0002-b.md"))
+	assert.Equal(t, "../0001-a.md", adr.RelativePath("platform/0002-b.md", "0001-a.md"))
+	assert.Equal(t, "../frontend/0003-c.md", adr.RelativePath("platform/0002-b.md", "frontend/0003-c.md"))
+}
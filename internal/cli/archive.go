@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/spf13/cobra"
+)
+
+// NewArchiveCmd creates the archive subcommand, which moves inactive ADRs into
+// the archive directory.
+func NewArchiveCmd() *cobra.Command {
+	var statuses []string
+	var olderThan string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Move inactive ADRs into the archive directory",
+		Long: `Move the ADRs with one of the given statuses into the archive directory
+("archive" below the ADR directory, or the config's "archiveDir"), rewriting
+every link to them. Archived ADRs keep their numbers: "adr show" and the web UI
+still find them, and "adr list --include-archived" lists them.
+
+--older-than limits the move to ADRs dated longer ago than the given age, in
+days (d), weeks (w), months (m) or years (y); undated ADRs are left in place.
+
+Examples:
+  adr archive
+  adr archive --status deprecated --older-than 1y
+  adr archive --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			wanted := make(map[adr.Status]bool, len(statuses))
+			for _, s := range statuses {
+				st, ok := adr.ParseStatus(s)
+				if !ok {
+					return fmt.Errorf("invalid --status %q: valid values are %s", s, strings.Join(adr.AllStatusStrings(), ", "))
+				}
+				wanted[st] = true
+			}
+			var cutoff time.Time
+			if olderThan != "" {
+				age, err := parseAge(olderThan)
+				if err != nil {
+					return err
+				}
+				cutoff = age(time.Now())
+			}
+
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+			repo := cfg.Repository()
+
+			records, err := repo.List(cmd.Context())
+			if err != nil {
+				return err
+			}
+			archived := 0
+			for _, r := range records {
+				if !wanted[r.Status] || !cutoff.IsZero() && (r.Date.IsZero() || !r.Date.Before(cutoff)) {
+					continue
+				}
+				filename, err := repo.FindFile(r.Number)
+				if err != nil {
+					return err
+				}
+				if dryRun {
+					fmt.Fprintf(cmd.OutOrStdout(), "Would archive %s\n", filename)
+				} else {
+					if _, err := repo.Archive(cmd.Context(), r.Number); err != nil {
+						return fmt.Errorf("archiving %s: %w", filename, err)
+					}
+					fmt.Fprintf(cmd.OutOrStdout(), "Archived %s\n", filename)
+				}
+				archived++
+			}
+			if archived == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No ADRs to archive")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&statuses, "status", []string{"superseded", "deprecated"}, "statuses to archive (repeatable or comma-separated)")
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "only archive ADRs dated longer ago than this, e.g. 90d, 6m or 1y")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would be archived without changing files")
+	return cmd
+}
+
+var agePattern = regexp.MustCompile(`^(\d+)([dwmy])$`)
+
+// parseAge parses an --older-than value such as "30d" or "1y" into a function
+// returning the cutoff date that far before a given time.
+func parseAge(s string) (func(time.Time) time.Time, error) {
+	m := agePattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if m == nil {
+		return nil, fmt.Errorf("invalid --older-than %q: expected a number followed by d, w, m or y", s)
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid --older-than %q: %w", s, err)
+	}
+	return func(t time.Time) time.Time {
+		switch m[2] {
+		case "d":
+			return t.AddDate(0, 0, -n)
+		case "w":
+			return t.AddDate(0, 0, -7*n)
+		case "m":
+			return t.AddDate(0, -n, 0)
+		default:
+			return t.AddDate(-n, 0, 0)
+		}
+	}, nil
+}
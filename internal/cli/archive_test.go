@@ -0,0 +1,117 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/BobMali/adr-helper/internal/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeArchivableADRs writes an accepted ADR superseding an old one, and a
+// deprecated ADR from last month.
+func writeArchivableADRs(t *testing.T, dir string) {
+	t.Helper()
+	recent := time.Now().AddDate(0, -1, 0).Format("2006-01-02")
+	files := map[string]string{
+		"0001-use-mysql.md":    "# 1. Use MySQL\n\nDate: 2020-01-01\n\n## Status\n\nSuperseded by [ADR-0002](0002-use-postgres.md)\n",
+		"0002-use-postgres.md": "# 2. Use Postgres\n\nDate: 2021-01-01\n\n## Status\n\nAccepted\n\nSupersedes [ADR-0001](0001-use-mysql.md)\n",
+		"0003-use-soap.md":     "# 3. Use SOAP\n\nDate: " + recent + "\n\n## Status\n\nDeprecated\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+}
+
+func runArchive(t *testing.T, args ...string) string {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	root := cli.NewRootCmd()
+	root.SetOut(buf)
+	root.SetArgs(append([]string{"archive"}, args...))
+	require.NoError(t, root.Execute())
+	return buf.String()
+}
+
+func TestArchiveCmd_MovesInactiveADRsAndRewritesLinks(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	dir := filepath.Join(tmpDir, "docs/adr")
+	writeArchivableADRs(t, dir)
+
+	out := runArchive(t)
+	assert.Contains(t, out, "Archived 0001-use-mysql.md")
+	assert.Contains(t, out, "Archived 0003-use-soap.md")
+	assert.FileExists(t, filepath.Join(dir, "archive", "0001-use-mysql.md"))
+	assert.FileExists(t, filepath.Join(dir, "archive", "0003-use-soap.md"))
+	assert.FileExists(t, filepath.Join(dir, "0002-use-postgres.md"))
+
+	content, err := os.ReadFile(filepath.Join(dir, "0002-use-postgres.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Supersedes [ADR-0001](archive/0001-use-mysql.md)")
+	content, err = os.ReadFile(filepath.Join(dir, "archive", "0001-use-mysql.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Superseded by [ADR-0002](../0002-use-postgres.md)")
+
+	assert.Equal(t, "No ADRs to archive\n", runArchive(t))
+}
+
+func TestArchiveCmd_StatusAndAgeFilters(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	dir := filepath.Join(tmpDir, "docs/adr")
+	writeArchivableADRs(t, dir)
+
+	out := runArchive(t, "--status", "deprecated,superseded", "--older-than", "1y", "--dry-run")
+	assert.Equal(t, "Would archive 0001-use-mysql.md\n", out)
+	assert.FileExists(t, filepath.Join(dir, "0001-use-mysql.md"))
+
+	out = runArchive(t, "--status", "deprecated")
+	assert.Equal(t, "Archived 0003-use-soap.md\n", out)
+	assert.FileExists(t, filepath.Join(dir, "0001-use-mysql.md"))
+}
+
+func TestArchiveCmd_InvalidFlags(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+
+	for _, args := range [][]string{{"--status", "obsolete"}, {"--older-than", "1 year"}} {
+		root := cli.NewRootCmd()
+		root.SetOut(new(bytes.Buffer))
+		root.SetArgs(append([]string{"archive"}, args...))
+		assert.Error(t, root.Execute(), args)
+	}
+}
+
+func TestArchiveCmd_ArchivedADRsStillResolve(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	writeArchivableADRs(t, filepath.Join(tmpDir, "docs/adr"))
+	runArchive(t)
+
+	out := runList(t, "--plain")
+	assert.NotContains(t, out, "Use MySQL")
+	assert.Contains(t, out, "Use Postgres")
+
+	out = runList(t, "--plain", "--include-archived")
+	assert.Contains(t, out, "Superseded (archived)")
+	assert.Contains(t, out, "Use SOAP")
+
+	var records []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(runList(t, "--json", "--include-archived")), &records))
+	require.Len(t, records, 3)
+	assert.Equal(t, true, records[0]["archived"])
+	assert.Nil(t, records[1]["archived"])
+
+	buf := new(bytes.Buffer)
+	root := cli.NewRootCmd()
+	root.SetOut(buf)
+	root.SetArgs([]string{"show", "1"})
+	require.NoError(t, root.Execute())
+	assert.Contains(t, buf.String(), "Use MySQL")
+}
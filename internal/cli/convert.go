@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+)
+
+// NewConvertCmd creates the convert subcommand for moving ADRs to another
+// template format.
+func NewConvertCmd() *cobra.Command {
+	var all, dryRun bool
+	var to string
+
+	cmd := &cobra.Command{
+		Use:   "convert <id>|--all --to <template>",
+		Short: "Convert ADRs to another template format",
+		Long: `Rewrite ADRs in the format of another template, e.g. from nygard to
+madr-full. Sections are mapped between formats (Context → Context and Problem
+Statement, Decision → Decision Outcome, same-named sections as they are), the
+status moves between "## Status" and frontmatter "status:" with its supersede
+links, and fields such as Scope carry over. Content without a counterpart in
+the target format goes to "More Information".
+
+With --dry-run, a diff of every change is printed instead. With --all, all
+ADRs are converted as one crash-safe operation.
+
+Examples:
+  adr convert 12 --to madr-full
+  adr convert --all --to madr-full --dry-run`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if all {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var cfg *adr.Config
+			var numbers []int
+			var err error
+			if all {
+				cfg, err = loadConfig(cmd)
+			} else {
+				var id int
+				cfg, id, err = loadADRConfig(cmd, args[0])
+				numbers = []int{id}
+			}
+			if err != nil {
+				return err
+			}
+			tmpl, err := adr.LoadProjectTemplate(cfg, to)
+			if err != nil {
+				return err
+			}
+			repo := cfg.Repository()
+
+			plan, err := repo.PlanConversions(cmd.Context(), numbers, tmpl)
+			if err != nil {
+				return err
+			}
+
+			changed := 0
+			for _, c := range plan {
+				if !c.Changed() {
+					continue
+				}
+				changed++
+				if dryRun {
+					diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+						A:        difflib.SplitLines(c.Before),
+						B:        difflib.SplitLines(c.After),
+						FromFile: "a/" + c.Filename,
+						ToFile:   "b/" + c.Filename,
+						Context:  3,
+					})
+					if err != nil {
+						return err
+					}
+					fmt.Fprint(cmd.OutOrStdout(), diff)
+				}
+			}
+
+			switch {
+			case changed == 0:
+				fmt.Fprintf(cmd.OutOrStdout(), "Nothing to convert: already in %s format\n", tmpl.Name)
+			case dryRun:
+				fmt.Fprintf(cmd.OutOrStdout(), "Would convert %d ADR(s) to %s\n", changed, tmpl.Name)
+			default:
+				if err := repo.ApplyConversions(cmd.Context(), plan); err != nil {
+					return err
+				}
+				for _, c := range plan {
+					if c.Changed() {
+						fmt.Fprintf(cmd.OutOrStdout(), "Converted %s to %s\n", c.Filename, tmpl.Name)
+					}
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "convert every ADR")
+	cmd.Flags().StringVar(&to, "to", "", "template to convert to")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print a diff of the changes without changing files")
+	_ = cmd.MarkFlagRequired("to")
+	return cmd
+}
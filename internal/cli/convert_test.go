@@ -0,0 +1,105 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/BobMali/adr-helper/internal/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const convertSourceADR = "# 1. Use Go\n\nDate: 2024-01-02\n\n## Status\n\nAccepted\n\n## Context\n\nWhy.\n\n## Decision\n\nGo.\n\n## Consequences\n\nFine.\n"
+
+func TestNewConvertCmd_UseAndShort(t *testing.T) {
+	cmd := cli.NewConvertCmd()
+	assert.Equal(t, "convert <id>|--all --to <template>", cmd.Use)
+	assert.Contains(t, cmd.Short, "template format")
+}
+
+func TestConvertCmd_SingleADR(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	dir := filepath.Join(tmpDir, "docs/adr")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0001-use-go.md"), []byte(convertSourceADR), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0002-other.md"), []byte(convertSourceADR), 0o644))
+
+	buf := new(bytes.Buffer)
+	root := cli.NewRootCmd()
+	root.SetOut(buf)
+	root.SetArgs([]string{"convert", "1", "--to", "madr-full"})
+	require.NoError(t, root.Execute())
+
+	assert.Contains(t, buf.String(), "Converted 0001-use-go.md to madr-full")
+	content, err := os.ReadFile(filepath.Join(dir, "0001-use-go.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "status: \"accepted\"")
+	assert.Contains(t, string(content), "## Context and Problem Statement\n\nWhy.")
+	assert.Contains(t, string(content), "## Decision Outcome\n\nGo.\n\n### Consequences\n\nFine.")
+
+	other, err := os.ReadFile(filepath.Join(dir, "0002-other.md"))
+	require.NoError(t, err)
+	assert.Equal(t, convertSourceADR, string(other))
+}
+
+func TestConvertCmd_AllDryRunPrintsDiff(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	dir := filepath.Join(tmpDir, "docs/adr")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0001-use-go.md"), []byte(convertSourceADR), 0o644))
+
+	buf := new(bytes.Buffer)
+	root := cli.NewRootCmd()
+	root.SetOut(buf)
+	root.SetArgs([]string{"convert", "--all", "--to", "madr-minimal", "--dry-run"})
+	require.NoError(t, root.Execute())
+
+	out := buf.String()
+	assert.Contains(t, out, "--- a/0001-use-go.md\n+++ b/0001-use-go.md\n")
+	assert.Contains(t, out, "-## Context\n+## Context and Problem Statement\n")
+	assert.Contains(t, out, "Would convert 1 ADR(s) to madr-minimal")
+	content, err := os.ReadFile(filepath.Join(dir, "0001-use-go.md"))
+	require.NoError(t, err)
+	assert.Equal(t, convertSourceADR, string(content))
+}
+
+func TestConvertCmd_AlreadyInFormat(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "docs/adr", "0001-use-go.md"), []byte(convertSourceADR), 0o644))
+
+	buf := new(bytes.Buffer)
+	root := cli.NewRootCmd()
+	root.SetOut(buf)
+	root.SetArgs([]string{"convert", "--all", "--to", "nygard"})
+	require.NoError(t, root.Execute())
+
+	assert.Contains(t, buf.String(), "Nothing to convert: already in nygard format")
+}
+
+func TestConvertCmd_RequiresTo(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+
+	root := cli.NewRootCmd()
+	root.SetArgs([]string{"convert", "1"})
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+
+	assert.Error(t, root.Execute())
+}
+
+func TestConvertCmd_UnknownTemplate(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+
+	root := cli.NewRootCmd()
+	root.SetArgs([]string{"convert", "--all", "--to", "nope"})
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+
+	assert.ErrorIs(t, root.Execute(), adr.ErrUnknownTemplate)
+}
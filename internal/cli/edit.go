@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/spf13/cobra"
+)
+
+// editProblems holds the validation findings for an ADR after it was edited.
+// Zero values mean "no problem".
+type editProblems struct {
+	// invalidStatus is the unparseable status line.
+	invalidStatus string
+	// headingNumber is the "# N." heading number when it differs from the ADR ID.
+	headingNumber int
+	// staleFilename is the filename the new title slugifies to, set only when
+	// the title changed during the edit and no longer matches the filename.
+	staleFilename string
+}
+
+func (p editProblems) ok() bool {
+	return p.invalidStatus == "" && p.headingNumber == 0 && p.staleFilename == ""
+}
+
+// checkEditedADR re-runs ExtractMetadata/MetadataToADR on the edited content
+// and reports an invalid status, a heading number that no longer matches the
+// ADR ID (the same check as the web content update), and a changed title whose
+// filename slug is now stale.
+func checkEditedADR(naming adr.Naming, id int, filename, before, after string) editProblems {
+	var p editProblems
+
+	meta := adr.ExtractMetadata(after)
+	if _, err := adr.MetadataToADR(meta, id); err != nil {
+		p.invalidStatus = strings.TrimSpace(strings.SplitN(strings.TrimSpace(meta.Status), "\n", 2)[0])
+	}
+	if meta.Number > 0 && meta.Number != id {
+		p.headingNumber = meta.Number
+	}
+	if meta.Title != adr.ExtractMetadata(before).Title {
+		if expected, err := naming.RenamedFilename(filename, id, meta.Title); err == nil && expected != filename {
+			p.staleFilename = expected
+		}
+	}
+	return p
+}
+
+// NewEditCmd creates the edit subcommand, which opens an ADR in the user's
+// editor and validates the result once the editor exits.
+func NewEditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "edit <id>",
+		Short: "Open an ADR in $VISUAL/$EDITOR and validate it afterwards",
+		Long: `Open an ADR in $VISUAL (or $EDITOR, falling back to vi).
+
+After the editor exits the ADR is re-validated. An invalid status, a heading
+number that no longer matches the ADR ID, or a changed title whose filename
+slug is now stale are reported, with the option to fix them automatically or
+re-open the editor.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, id, err := loadADRConfig(cmd, args[0])
+			if err != nil {
+				return err
+			}
+
+			filename, err := cfg.Repository().FindFile(id)
+			if err != nil {
+				return err
+			}
+			filePath := filepath.Join(cfg.Directory, filename)
+
+			before, err := os.ReadFile(filePath)
+			if err != nil {
+				return fmt.Errorf("reading ADR: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			p := newPrompter(cmd)
+			for {
+				if err := runEditor(cmd, filePath); err != nil {
+					return err
+				}
+				after, err := os.ReadFile(filePath)
+				if err != nil {
+					return fmt.Errorf("reading ADR: %w", err)
+				}
+				if string(after) == string(before) {
+					fmt.Fprintf(out, "No changes to %s\n", filename)
+					return nil
+				}
+
+				problems := checkEditedADR(cfg.Naming(), id, filename, string(before), string(after))
+				if problems.ok() {
+					fmt.Fprintf(out, "Saved %s\n", filename)
+					return nil
+				}
+
+				fmt.Fprintln(out, "warning: the edited ADR has problems:")
+				if problems.invalidStatus != "" {
+					fmt.Fprintf(out, "  - invalid status %q (valid: %s)\n",
+						problems.invalidStatus, strings.Join(adr.AllStatusStrings(), ", "))
+				}
+				if problems.headingNumber != 0 {
+					fmt.Fprintf(out, "  - heading number %d does not match ADR %d\n", problems.headingNumber, id)
+				}
+				if problems.staleFilename != "" {
+					fmt.Fprintf(out, "  - title changed; filename %s should be %s\n", filename, problems.staleFilename)
+				}
+
+				choice, _ := p.line("[f]ix, [r]e-open editor, or [i]gnore? ")
+				switch strings.ToLower(choice) {
+				case "f", "fix":
+					return fixEditedADR(cmd, p, cfg, id, filename, string(after), problems)
+				case "r", "re-open", "reopen":
+					continue
+				default:
+					fmt.Fprintf(out, "Saved %s with problems left unfixed\n", filename)
+					return nil
+				}
+			}
+		},
+	}
+	return cmd
+}
+
+// fixEditedADR applies automatic fixes for the given problems: it prompts for a
+// valid status, restores the heading number, and renames the file to match the
+// new title (rewriting inbound links, see FileRepository.Rename).
+func fixEditedADR(cmd *cobra.Command, p *prompter, cfg *adr.Config, id int, filename, content string, problems editProblems) error {
+	out := cmd.OutOrStdout()
+	filePath := filepath.Join(cfg.Directory, filename)
+
+	if problems.invalidStatus != "" {
+		status, err := p.status()
+		if err != nil {
+			return err
+		}
+		updated, err := adr.UpdateStatus(content, status)
+		if err != nil {
+			return err
+		}
+		content = updated
+		fmt.Fprintf(out, "Set status to %s\n", status)
+	}
+	if problems.headingNumber != 0 {
+		content, _ = adr.ReplaceHeading(content, id, adr.ExtractMetadata(content).Title)
+		fmt.Fprintf(out, "Restored heading number %d\n", id)
+	}
+	if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("writing ADR: %w", err)
+	}
+
+	if problems.staleFilename != "" {
+		// Rename through the repository so inbound links in other ADRs follow.
+		if _, err := cfg.Repository().Rename(cmd.Context(), id, adr.ExtractMetadata(content).Title); err != nil {
+			return fmt.Errorf("renaming ADR: %w", err)
+		}
+		fmt.Fprintf(out, "Renamed %s to %s\n", filename, problems.staleFilename)
+		filename = problems.staleFilename
+	}
+
+	fmt.Fprintf(out, "Saved %s\n", filename)
+	return nil
+}
@@ -0,0 +1,162 @@
+package cli_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/BobMali/adr-helper/internal/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEditor installs a shell script as $EDITOR that, on its nth invocation,
+// overwrites the edited file with contents[n-1].
+func fakeEditor(t *testing.T, contents ...string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as $EDITOR")
+	}
+	dir := t.TempDir()
+	for i, c := range contents {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("edit-%d.md", i+1)), []byte(c), 0o644))
+	}
+	script := fmt.Sprintf(`#!/bin/sh
+n=$(cat %[1]s/count 2>/dev/null || echo 0)
+n=$((n+1))
+echo $n > %[1]s/count
+cp %[1]s/edit-$n.md "$1"
+`, dir)
+	path := filepath.Join(dir, "editor.sh")
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o755))
+	t.Setenv("VISUAL", path)
+}
+
+const editOriginal = "# 2. Use Go\n\nDate: 2024-01-01\n\n## Status\n\nProposed\n\n## Context\n\nOld.\n"
+
+func setupEditWorkspace(t *testing.T) string {
+	t.Helper()
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "docs/adr", "0002-use-go.md"), []byte(editOriginal), 0o644))
+	return filepath.Join(tmpDir, "docs/adr")
+}
+
+func runEdit(t *testing.T, input string) (string, error) {
+	t.Helper()
+	out := new(bytes.Buffer)
+	root := cli.NewRootCmd()
+	root.SetOut(out)
+	root.SetIn(strings.NewReader(input))
+	root.SetArgs([]string{"edit", "2"})
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestNewEditCmd_UseAndShort(t *testing.T) {
+	cmd := cli.NewEditCmd()
+	assert.Equal(t, "edit <id>", cmd.Use)
+	assert.Contains(t, cmd.Short, "EDITOR")
+}
+
+func TestEditCmd_ValidEditSaves(t *testing.T) {
+	dir := setupEditWorkspace(t)
+	fakeEditor(t, strings.Replace(editOriginal, "Old.", "New context.", 1))
+
+	out, err := runEdit(t, "")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Saved 0002-use-go.md")
+	assert.NotContains(t, out, "warning")
+
+	content, err := os.ReadFile(filepath.Join(dir, "0002-use-go.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "New context.")
+}
+
+func TestEditCmd_NoChanges(t *testing.T) {
+	setupEditWorkspace(t)
+	fakeEditor(t, editOriginal)
+
+	out, err := runEdit(t, "")
+	require.NoError(t, err)
+	assert.Contains(t, out, "No changes to 0002-use-go.md")
+}
+
+func TestEditCmd_NotFound(t *testing.T) {
+	setupEditWorkspace(t)
+	fakeEditor(t, editOriginal)
+
+	root := cli.NewRootCmd()
+	root.SetArgs([]string{"edit", "9"})
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+	assert.Error(t, root.Execute())
+}
+
+func TestEditCmd_WarnsAndIgnores(t *testing.T) {
+	dir := setupEditWorkspace(t)
+	broken := "# 3. Use Go Modules\n\nDate: 2024-01-01\n\n## Status\n\nAcepted\n\n## Context\n\nOld.\n"
+	fakeEditor(t, broken)
+
+	out, err := runEdit(t, "i\n")
+	require.NoError(t, err)
+	assert.Contains(t, out, `invalid status "Acepted"`)
+	assert.Contains(t, out, "heading number 3 does not match ADR 2")
+	assert.Contains(t, out, "filename 0002-use-go.md should be 0002-use-go-modules.md")
+	assert.Contains(t, out, "problems left unfixed")
+
+	content, err := os.ReadFile(filepath.Join(dir, "0002-use-go.md"))
+	require.NoError(t, err)
+	assert.Equal(t, broken, string(content))
+}
+
+func TestEditCmd_FixRepairsStatusHeadingAndFilename(t *testing.T) {
+	dir := setupEditWorkspace(t)
+	fakeEditor(t, "# 3. Use Go Modules\n\nDate: 2024-01-01\n\n## Status\n\nAcepted\n\n## Context\n\nOld.\n")
+
+	// "f" to fix, then "2" selects accepted in the status menu.
+	out, err := runEdit(t, "f\n2\n")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Renamed 0002-use-go.md to 0002-use-go-modules.md")
+
+	_, err = os.Stat(filepath.Join(dir, "0002-use-go.md"))
+	assert.True(t, os.IsNotExist(err))
+	content, err := os.ReadFile(filepath.Join(dir, "0002-use-go-modules.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "# 2. Use Go Modules")
+	assert.Contains(t, string(content), "## Status\n\nAccepted\n")
+}
+
+func TestEditCmd_ReopenEditor(t *testing.T) {
+	dir := setupEditWorkspace(t)
+	fixed := strings.Replace(editOriginal, "Proposed", "Accepted", 1)
+	fakeEditor(t, strings.Replace(editOriginal, "Proposed", "Bogus", 1), fixed)
+
+	out, err := runEdit(t, "r\n")
+	require.NoError(t, err)
+	assert.Contains(t, out, `invalid status "Bogus"`)
+	assert.Contains(t, out, "Saved 0002-use-go.md")
+
+	content, err := os.ReadFile(filepath.Join(dir, "0002-use-go.md"))
+	require.NoError(t, err)
+	assert.Equal(t, fixed, string(content))
+}
+
+func TestEditCmd_UnchangedTitleDoesNotFlagPreexistingSlugMismatch(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	dir := filepath.Join(tmpDir, "docs/adr")
+	original := strings.Replace(editOriginal, "Use Go", "Use Golang", 1)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0002-use-go.md"), []byte(original), 0o644))
+	fakeEditor(t, strings.Replace(original, "Old.", "Changed.", 1))
+
+	out, err := runEdit(t, "")
+	require.NoError(t, err)
+	assert.NotContains(t, out, "should be")
+}
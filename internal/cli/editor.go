@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultEditor is used when neither $VISUAL nor $EDITOR is set.
+const defaultEditor = "vi"
+
+// editorCommand returns the user's editor command line, preferring $VISUAL over
+// $EDITOR. The value is split on whitespace so settings like "code --wait" work;
+// shell quoting is not interpreted.
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{defaultEditor}
+}
+
+// runEditor opens path in the user's editor and waits for it to exit. The
+// editor inherits the command's stdout/stderr, and its stdin when that is a
+// real file (the terminal) so terminal editors work. Any other reader is left
+// alone: exec would drain it, swallowing answers meant for later prompts.
+func runEditor(cmd *cobra.Command, path string) error {
+	argv := append(editorCommand(), path)
+	c := exec.Command(argv[0], argv[1:]...)
+	if in, ok := cmd.InOrStdin().(*os.File); ok {
+		c.Stdin = in
+	}
+	c.Stdout = cmd.OutOrStdout()
+	c.Stderr = cmd.ErrOrStderr()
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("running editor %q: %w", argv[0], err)
+	}
+	return nil
+}
+
+// editText opens initial in the user's editor via a temporary markdown file and
+// returns the edited text with surrounding whitespace trimmed.
+func editText(cmd *cobra.Command, initial string) (string, error) {
+	f, err := os.CreateTemp("", "adr-*.md")
+	if err != nil {
+		return "", fmt.Errorf("creating temp file: %w", err)
+	}
+	path := f.Name()
+	defer os.Remove(path)
+
+	if _, err := f.WriteString(initial); err != nil {
+		f.Close()
+		return "", fmt.Errorf("writing temp file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("writing temp file: %w", err)
+	}
+
+	if err := runEditor(cmd, path); err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading temp file: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/BobMali/adr-helper/internal/site"
+	"github.com/spf13/cobra"
+)
+
+// NewExportCmd creates the export subcommand, which groups the export formats.
+func NewExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export ADRs for readers outside the repository",
+	}
+	cmd.AddCommand(newExportHTMLCmd())
+	return cmd
+}
+
+func newExportHTMLCmd() *cobra.Command {
+	var title string
+	var search string
+	var scopes []string
+	var scopeMatch string
+	var sortField string
+	var sortOrder string
+
+	cmd := &cobra.Command{
+		Use:   "html <outdir>",
+		Short: "Export ADRs as a static HTML site",
+		Args:  cobra.ExactArgs(1),
+		Long: `Render every ADR to a self-contained static HTML site in outdir: an index
+page with status badges, scope facets and search, a page per ADR with a sidebar
+of the ADRs it supersedes, is superseded by and relates to, a page per scope,
+and a graph of those links. The site needs no server, so it can be opened from
+disk or published on any static host.
+
+--search and --scope select the ADRs to export, and --sort and --order the
+index order, as for "adr list".
+
+Examples:
+  adr export html site
+  adr export html public --title "Payments decisions" --scope payments`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			matchAll, err := parseScopeMatch(scopeMatch)
+			if err != nil {
+				return err
+			}
+			desc, err := parseSortOrder(sortOrder)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			n, err := site.Export(cmd.Context(), cfg.Repository(), args[0], site.Options{
+				Title:          title,
+				Query:          search,
+				Scopes:         scopes,
+				MatchAllScopes: matchAll,
+				SortField:      sortField,
+				SortDesc:       desc,
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Exported %d ADR(s) to %s\n", n, args[0])
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&title, "title", site.DefaultTitle, "site title")
+	cmd.Flags().StringVarP(&search, "search", "s", "", "export only ADRs matching a title or number")
+	cmd.Flags().StringSliceVar(&scopes, "scope", nil, "export only ADRs with a scope (repeatable or comma-separated)")
+	cmd.Flags().StringVar(&scopeMatch, "scope-match", "any", "how multiple --scope values combine: any (union) or all (intersection)")
+	cmd.Flags().StringVar(&sortField, "sort", "number", "index order: number, title, status, or date")
+	cmd.Flags().StringVar(&sortOrder, "order", "asc", "sort direction: asc or desc")
+	return cmd
+}
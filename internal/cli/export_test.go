@@ -0,0 +1,44 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BobMali/adr-helper/internal/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportHTMLCmd_WritesSite(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	dir := filepath.Join(tmpDir, "docs/adr")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0001-use-go.md"), []byte(convertSourceADR), 0o644))
+
+	buf := new(bytes.Buffer)
+	root := cli.NewRootCmd()
+	root.SetOut(buf)
+	root.SetArgs([]string{"export", "html", "site", "--title", "Team decisions"})
+	require.NoError(t, root.Execute())
+
+	assert.Contains(t, buf.String(), "Exported 1 ADR(s) to site")
+	index, err := os.ReadFile(filepath.Join(tmpDir, "site", "index.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(index), "<title>Team decisions</title>")
+	assert.FileExists(t, filepath.Join(tmpDir, "site", "0001-use-go.html"))
+}
+
+func TestExportHTMLCmd_InvalidOrder(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+
+	root := cli.NewRootCmd()
+	root.SetOut(new(bytes.Buffer))
+	root.SetArgs([]string{"export", "html", "site", "--order", "sideways"})
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+	assert.ErrorContains(t, root.Execute(), `invalid --order "sideways"`)
+	assert.NoDirExists(t, filepath.Join(tmpDir, "site"))
+}
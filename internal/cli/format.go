@@ -15,7 +15,8 @@ type FormatOptions struct {
 
 var linkPattern = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
 
-// FormatADR formats ADR markdown content with terminal colors.
+// FormatADR formats ADR markdown content with terminal colors. A byte order
+// mark is dropped and CRLF line endings are printed as LF.
 func FormatADR(content string, opts FormatOptions) string {
 	h1Style := color.New(color.FgCyan, color.Bold)
 	h2Style := color.New(color.Bold)
@@ -43,6 +44,7 @@ func FormatADR(content string, opts FormatOptions) string {
 		}
 	}
 
+	content = strings.TrimPrefix(strings.ReplaceAll(content, "\r\n", "\n"), "\ufeff")
 	lines := strings.Split(content, "\n")
 	var result strings.Builder
 	inFrontmatter := false
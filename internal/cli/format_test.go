@@ -25,6 +25,16 @@ func TestFormatADR_H1_ColorEnabled(t *testing.T) {
 	assert.Contains(t, got, "1. Use Go")
 }
 
+func TestFormatADR_BOMAndCRLF(t *testing.T) {
+	orig := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = orig }()
+
+	got := FormatADR("\ufeff# 1. Use Go\r\n\r\nText\r\n", FormatOptions{NoColor: false})
+	assert.True(t, strings.HasPrefix(got, "\x1b["), got)
+	assert.NotContains(t, got, "\r")
+}
+
 func TestFormatADR_H1_PlainMode(t *testing.T) {
 	input := "# 1. Use Go\n"
 	got := FormatADR(input, FormatOptions{NoColor: true})
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/spf13/cobra"
+)
+
+// NewImportCmd creates the import subcommand for adopting ADRs written with
+// adr-tools or log4brains.
+func NewImportCmd() *cobra.Command {
+	var from string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "import --from adr-tools|log4brains [path]",
+		Short: "Adopt the ADRs of an adr-tools or log4brains project",
+		Args:  cobra.MaximumNArgs(1),
+		Long: `Adopt an existing adr-tools or log4brains project in place, so adr can
+manage its records. path is the project root (default "."), where .adr.json is
+written.
+
+The ADR directory is read from .adr-dir (adr-tools) or .log4brains.yml
+(log4brains). Files are renamed to the NNNN-slug.md convention; log4brains
+records are numbered by date. Status lines and links are rewritten: adr-tools'
+"Supersedes" and "Superseded by" links are kept, other links between ADRs such
+as "Amended by", and log4brains' "## Links", move to a "## Relations" section,
+and links to renamed files are retargeted. A copy of the matching template
+(nygard for adr-tools, madr-full for log4brains) is added.
+
+Examples:
+  adr import --from adr-tools
+  adr import --from log4brains ../website`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := "."
+			if len(args) > 0 {
+				root = args[0]
+			}
+
+			configPath := filepath.Join(root, adr.ConfigFileName)
+			if _, err := os.Stat(configPath); err == nil && !force {
+				return fmt.Errorf("config already exists at %q, use --force to overwrite", configPath)
+			}
+
+			result, err := adr.Import(root, from)
+			if err != nil {
+				return err
+			}
+			cfg := result.Config
+
+			// Best-effort, as in init: never block the import on it.
+			var added, invalid []string
+			raw, derr := adr.DiscoverScopes(filepath.Join(root, cfg.Directory))
+			if derr != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: scope discovery failed: %v\n", derr)
+			} else {
+				added, invalid = cfg.MergeScopes(raw)
+			}
+
+			if err := adr.SaveConfig(root, cfg); err != nil {
+				return fmt.Errorf("writing config: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			for _, f := range result.Files {
+				if f.From == f.To {
+					fmt.Fprintf(out, "Imported %s\n", f.To)
+				} else {
+					fmt.Fprintf(out, "Imported %s as %s\n", f.From, f.To)
+				}
+			}
+			for _, w := range result.Warnings {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: %s\n", w)
+			}
+			for _, v := range invalid {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: skipped invalid scope %q\n", v)
+			}
+			fmt.Fprintf(out, "Imported %d ADR(s) from %s into %s with template: %s\n",
+				len(result.Files), from, cfg.Directory, cfg.Template)
+			if len(added) > 0 {
+				fmt.Fprintf(out, "Discovered %d scope(s) from existing ADRs: %s\n",
+					len(added), strings.Join(added, ", "))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "",
+		fmt.Sprintf("tool the ADRs were written with (%s)", strings.Join(adr.ImportSources(), ", ")))
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "overwrite an existing config")
+	_ = cmd.MarkFlagRequired("from")
+
+	return cmd
+}
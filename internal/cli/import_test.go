@@ -0,0 +1,67 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/BobMali/adr-helper/internal/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewImportCmd_UseAndShort(t *testing.T) {
+	cmd := cli.NewImportCmd()
+	assert.Equal(t, "import --from adr-tools|log4brains [path]", cmd.Use)
+	assert.Contains(t, cmd.Short, "adr-tools or log4brains")
+}
+
+func TestImportCmd_ADRTools(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	dir := filepath.Join(tmpDir, "doc/adr")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "1-use-go.md"),
+		[]byte("# 1. Use Go\n\nDate: 2020-01-01\n\nScope: Backend\n\n## Status\n\nAccepted\n\n## Context\n\nC.\n"), 0o644))
+
+	buf := new(bytes.Buffer)
+	root := cli.NewRootCmd()
+	root.SetOut(buf)
+	root.SetArgs([]string{"import", "--from", "adr-tools"})
+	require.NoError(t, root.Execute())
+
+	out := buf.String()
+	assert.Contains(t, out, "Imported 1-use-go.md as 0001-use-go.md")
+	assert.Contains(t, out, "Imported 1 ADR(s) from adr-tools into doc/adr with template: nygard")
+	assert.Contains(t, out, "Discovered 1 scope(s) from existing ADRs: Backend")
+
+	cfg, err := adr.LoadConfig(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, "doc/adr", cfg.Directory)
+	assert.Equal(t, "nygard", cfg.Template)
+	assert.FileExists(t, filepath.Join(dir, "0001-use-go.md"))
+}
+
+func TestImportCmd_ExistingConfig(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "doc/adr", "nygard")
+
+	root := cli.NewRootCmd()
+	root.SetOut(new(bytes.Buffer))
+	root.SetArgs([]string{"import", "--from", "adr-tools"})
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+	assert.ErrorContains(t, root.Execute(), "use --force to overwrite")
+}
+
+func TestImportCmd_RequiresFrom(t *testing.T) {
+	chdirTemp(t)
+
+	root := cli.NewRootCmd()
+	root.SetOut(new(bytes.Buffer))
+	root.SetArgs([]string{"import"})
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+	assert.ErrorContains(t, root.Execute(), `"from" not set`)
+}
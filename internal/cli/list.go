@@ -13,10 +13,14 @@ import (
 )
 
 type listJSON struct {
+	// Root is only set in a combined listing of several roots.
+	Root   string     `json:"root,omitempty"`
 	Number int        `json:"number"`
 	Title  string     `json:"title"`
 	Status adr.Status `json:"status"`
 	Date   string     `json:"date"`
+	// Archived is only set with --include-archived.
+	Archived bool `json:"archived,omitempty"`
 }
 
 // NewListCmd creates the list subcommand for displaying all ADRs.
@@ -29,6 +33,7 @@ func NewListCmd() *cobra.Command {
 	var scopeMatch string
 	var sortField string
 	var sortOrder string
+	var includeArchived bool
 
 	cmd := &cobra.Command{
 		Use:   "list",
@@ -45,17 +50,39 @@ func NewListCmd() *cobra.Command {
 				return err
 			}
 
-			cfg, err := adr.LoadConfig(".")
+			project, err := adr.LoadConfig(".")
 			if err != nil {
 				return err
 			}
-
-			repo := adr.NewFileRepository(cfg.Directory)
-			records, err := repo.List(cmd.Context())
+			// Without --root, a project with roots lists all of them.
+			root, _ := cmd.Flags().GetString("root")
+			combined := root == "" && len(project.Roots) > 0
+			var roots []*adr.Config
+			if combined {
+				roots, err = project.AllRoots()
+			} else {
+				var cfg *adr.Config
+				cfg, err = project.Root(root)
+				roots = []*adr.Config{cfg}
+			}
 			if err != nil {
 				return err
 			}
 
+			var records []adr.ADR
+			for _, cfg := range roots {
+				repo := cfg.Repository()
+				list := repo.List
+				if includeArchived {
+					list = repo.ListWithArchived
+				}
+				rootRecords, err := list(cmd.Context())
+				if err != nil {
+					return err
+				}
+				records = append(records, rootRecords...)
+			}
+
 			if search != "" {
 				records = adr.FilterByQuery(records, search)
 			}
@@ -120,10 +147,12 @@ func NewListCmd() *cobra.Command {
 						dateStr = r.Date.Format("2006-01-02")
 					}
 					result[i] = listJSON{
-						Number: r.Number,
-						Title:  r.Title,
-						Status: r.Status,
-						Date:   dateStr,
+						Root:     r.Root,
+						Number:   r.Number,
+						Title:    r.Title,
+						Status:   r.Status,
+						Date:     dateStr,
+						Archived: r.Archived,
 					}
 				}
 				return json.NewEncoder(cmd.OutOrStdout()).Encode(result)
@@ -145,13 +174,22 @@ func NewListCmd() *cobra.Command {
 			}
 
 			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+			if combined {
+				fmt.Fprint(w, "Root\t")
+			}
 			fmt.Fprintln(w, "ID\tDate\tTitle\tStatus")
 			for _, r := range records {
+				if combined {
+					fmt.Fprintf(w, "%s\t", r.Root)
+				}
 				dateStr := ""
 				if !r.Date.IsZero() {
 					dateStr = r.Date.Format("2006-01-02")
 				}
 				statusStr := statusColor(r.Status.String(), greenStyle, yellowStyle, redStyle)
+				if r.Archived {
+					statusStr += " (archived)"
+				}
 				fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", r.Number, dateStr, r.Title, statusStr)
 			}
 			return w.Flush()
@@ -166,6 +204,7 @@ func NewListCmd() *cobra.Command {
 	cmd.Flags().StringVar(&scopeMatch, "scope-match", "any", "how multiple --scope values combine: any (union) or all (intersection)")
 	cmd.Flags().StringVar(&sortField, "sort", "number", "sort by field: number, title, status, or date")
 	cmd.Flags().StringVar(&sortOrder, "order", "asc", "sort direction: asc or desc")
+	cmd.Flags().BoolVar(&includeArchived, "include-archived", false, "also list ADRs moved to the archive directory")
 	return cmd
 }
 
@@ -1,10 +1,15 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/BobMali/adr-helper/internal/adr"
@@ -13,17 +18,56 @@ import (
 
 // NewNewCmd creates the new subcommand for creating a new ADR.
 func NewNewCmd() *cobra.Command {
-	var supersedes []int
+	var supersedes []string
 	var scopes []string
+	var interactive bool
+	var templateName string
+	var vars []string
+	var fields []string
 
 	cmd := &cobra.Command{
 		Use:   "new <title>",
 		Short: "Create a new ADR",
-		Args:  cobra.ExactArgs(1),
+		Long: `Create a new ADR from the project's default template, or the one named
+by --template (see .adr.json "templates").
+
+With --interactive, a guided wizard walks through each template section in
+order, showing the template's guidance for it. Answers are typed on stdin
+(end a section with a line containing only ".") or, by entering "!edit",
+written in $VISUAL/$EDITOR. Scope-style fields offer the project's scope
+vocabulary as a picker, and the wizard finally offers to supersede or relate
+existing ADRs. The title argument is optional in this mode.
+
+Each --field key=value fills a template field: a section (keyed by its
+slugified heading, e.g. context), a title-block line or a frontmatter key.
+Frontmatter fields take a comma-separated list, e.g.
+--field decision-makers="Alice, Bob".
+
+Templates may use text/template actions such as {{.Title}}, {{.Author}}
+(git's user.name), {{if .Scopes}}...{{end}} or {{.Vars.key}}, where each
+--var key=value sets a custom value.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if interactive {
+				return cobra.MaximumNArgs(1)(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			title := args[0]
+			var title string
+			if len(args) > 0 {
+				title = args[0]
+			}
+
+			templateVars, err := parseKeyValues("--var", vars)
+			if err != nil {
+				return err
+			}
+			fieldValues, err := parseKeyValues("--field", fields)
+			if err != nil {
+				return err
+			}
 
-			cfg, err := adr.LoadConfig(".")
+			cfg, err := loadConfig(cmd)
 			if err != nil {
 				return err
 			}
@@ -32,144 +76,253 @@ func NewNewCmd() *cobra.Command {
 				return fmt.Errorf("ADR directory %q not found: %w", cfg.Directory, err)
 			}
 
-			templatePath := filepath.Join(cfg.Directory, cfg.TemplateFile)
-			templateContent, err := os.ReadFile(templatePath)
+			if templateName == "" {
+				templateName = cfg.Template
+			}
+			tmpl, err := adr.LoadProjectTemplate(cfg, templateName)
 			if err != nil {
-				return fmt.Errorf("reading template %q: %w", templatePath, err)
+				return err
 			}
 
-			number, err := adr.NextNumber(cfg.Directory)
+			sectionDefs := tmpl.EditableSections()
+			sections, err := resolveFields(cfg, tmpl.Name, sectionDefs, fieldValues)
 			if err != nil {
 				return err
 			}
+			var relatesTo []int
+			if interactive {
+				answers, err := runNewWizard(cmd, cfg, title, sectionDefs)
+				if err != nil {
+					return err
+				}
+				title = answers.Title
+				maps.Copy(sections, answers.Sections)
+				for _, n := range answers.Supersedes {
+					supersedes = append(supersedes, strconv.Itoa(n))
+				}
+				relatesTo = answers.RelatesTo
+			}
 
-			filename, err := adr.FormatFilename(number, title)
+			repo := cfg.Repository()
+			number, err := repo.NextNumber(cmd.Context())
 			if err != nil {
 				return err
 			}
 
 			record := adr.New(number, title)
-			rendered := adr.RenderTemplate(string(templateContent), record)
+			filename, err := cfg.Naming().Filename(number, title, record.Date)
+			if err != nil {
+				return err
+			}
 
-			// Apply scope values (strict: every value must be in the project
+			// Resolve scope values (strict: every value must be in the project
 			// vocabulary; validate before any files are written).
+			var canonicalScopes []string
 			if len(scopes) > 0 {
-				canonical, err := resolveScopes(cfg, scopes)
+				canonicalScopes, err = resolveScopes(cfg, scopes)
 				if err != nil {
 					return err
 				}
-				replaced, found := adr.ReplaceMetaField(rendered, "Scope", strings.Join(canonical, ", "))
-				if !found {
-					return fmt.Errorf("template %q has no Scope field; cannot apply --scope", cfg.TemplateFile)
-				}
-				rendered = replaced
 			}
 
+			// Resolve all superseded ADR files — fail early
+			type mutation struct {
+				path    string
+				content string
+			}
+			var mutations []mutation
+			var links []adr.ADRLink
+
 			if len(supersedes) > 0 {
 				// Validate and deduplicate IDs
-				ids, err := deduplicateIDs(supersedes)
+				ids, err := parseIDs(supersedes)
 				if err != nil {
 					return err
 				}
 
-				// Resolve all superseded ADR files — fail early
-				type mutation struct {
-					path    string
-					content string
-				}
-				var mutations []mutation
-				var links []adr.ADRLink
-
 				for _, id := range ids {
-					oldFilename, err := adr.FindADRFile(cfg.Directory, id)
+					target, err := cfg.Resolve(id)
 					if err != nil {
-						return fmt.Errorf("cannot supersede ADR %04d: %w", id, err)
+						return fmt.Errorf("cannot supersede ADR %s: %w", id, err)
 					}
-					links = append(links, adr.ADRLink{Number: id, Filename: oldFilename})
+					oldFilename, err := target.Repository().FindFile(id.Number)
+					if err != nil {
+						return fmt.Errorf("cannot supersede ADR %s: %w", id, err)
+					}
+					links = append(links, cfg.LinkTo(filename, target, id.Number, oldFilename))
 
 					// Read and compute new content
-					oldPath := filepath.Join(cfg.Directory, oldFilename)
+					oldPath := filepath.Join(target.Directory, oldFilename)
 					oldContent, err := os.ReadFile(oldPath)
 					if err != nil {
-						return fmt.Errorf("reading ADR %04d: %w", id, err)
+						return fmt.Errorf("reading ADR %s: %w", id, err)
 					}
 
-					newLink := adr.ADRLink{Number: number, Filename: filename}
+					newLink := target.LinkTo(oldFilename, cfg, number, filename)
 					updatedContent, err := adr.SetSupersededBy(string(oldContent), newLink)
 					if err != nil {
-						return fmt.Errorf("updating ADR %04d: %w", id, err)
+						return fmt.Errorf("updating ADR %s: %w", id, err)
 					}
 					mutations = append(mutations, mutation{path: oldPath, content: updatedContent})
 				}
+			}
 
-				// Compute new ADR content with supersedes links
-				rendered, err = adr.SetSupersedes(rendered, links)
+			// Check the ADRs to relate to take the link back to the new ADR
+			// before it is written; the relations themselves are added last.
+			for _, n := range relatesTo {
+				id := adr.ID{Number: n}
+				targetFile, err := repo.FindFile(n)
 				if err != nil {
-					return fmt.Errorf("setting supersedes in new ADR: %w", err)
+					return fmt.Errorf("cannot relate to ADR %s: %w", id, err)
 				}
-
-				// All computation succeeded — now write files
-				filePath := filepath.Join(cfg.Directory, filename)
-				if err := os.WriteFile(filePath, []byte(rendered), 0o644); err != nil {
-					return fmt.Errorf("writing ADR: %w", err)
+				targetContent, err := os.ReadFile(filepath.Join(cfg.Directory, targetFile))
+				if err != nil {
+					return fmt.Errorf("reading ADR %s: %w", id, err)
 				}
+				if _, err := adr.AddRelation(string(targetContent), cfg.LinkTo(targetFile, cfg, number, filename)); err != nil {
+					return fmt.Errorf("cannot relate to ADR %s: %w", id, err)
+				}
+			}
 
-				for _, m := range mutations {
-					if err := os.WriteFile(m.path, []byte(m.content), 0o644); err != nil {
-						return fmt.Errorf("writing updated ADR: %w", err)
-					}
-					fmt.Fprintf(cmd.OutOrStdout(), "Superseded %s\n", m.path)
+			data := adr.NewTemplateData(record)
+			data.Author = gitUserName()
+			data.Scopes = canonicalScopes
+			if len(data.Scopes) == 0 {
+				data.Scopes = adr.VocabularyValues(sectionDefs, sections)
+			}
+			data.Supersedes = links
+			data.Vars = templateVars
+			content, err := tmpl.Execute(data)
+			if err != nil {
+				return err
+			}
+			rendered := adr.RenderTemplate(content, record)
+			rendered = adr.ApplySections(rendered, sectionDefs, sections)
+
+			if len(canonicalScopes) > 0 {
+				replaced, found := adr.ReplaceMetaField(rendered, "Scope", strings.Join(canonicalScopes, ", "))
+				switch {
+				case found:
+					rendered = replaced
+				case !strings.Contains(tmpl.Content, ".Scopes"):
+					// A template rendering {{.Scopes}} itself needs no Scope field.
+					return fmt.Errorf("template %q has no Scope field; cannot apply --scope", tmpl.Name)
 				}
+			}
 
-				fmt.Fprintf(cmd.OutOrStdout(), "Created %s\n", filePath)
-				return nil
+			if len(links) > 0 {
+				// Compute new ADR content with supersedes links
+				rendered, err = adr.SetSupersedes(rendered, links)
+				if err != nil {
+					return fmt.Errorf("setting supersedes in new ADR: %w", err)
+				}
 			}
 
+			// All computation succeeded — now write files. Save refuses to
+			// overwrite an existing file, e.g. an ADR with the same title
+			// created the same day under a "{date}-{slug}.md" pattern.
 			filePath := filepath.Join(cfg.Directory, filename)
-			if err := os.WriteFile(filePath, []byte(rendered), 0o644); err != nil {
+			record.Content = rendered
+			if err := repo.Save(cmd.Context(), record); err != nil {
+				if errors.Is(err, adr.ErrConflict) {
+					return fmt.Errorf("%s already exists; choose another title: %w", filename, err)
+				}
 				return fmt.Errorf("writing ADR: %w", err)
 			}
 
+			for _, m := range mutations {
+				if err := os.WriteFile(m.path, []byte(m.content), 0o644); err != nil {
+					return fmt.Errorf("writing updated ADR: %w", err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Superseded %s\n", m.path)
+			}
+
 			fmt.Fprintf(cmd.OutOrStdout(), "Created %s\n", filePath)
+
+			// Relations are added last: they rewrite the new file, which must exist.
+			for _, n := range relatesTo {
+				if _, err := repo.AddRelation(cmd.Context(), number, n); err != nil {
+					return fmt.Errorf("relating to ADR %s: %w", adr.ID{Number: n}, err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Related to %s\n", cfg.Naming().Label(n))
+			}
 			return nil
 		},
 	}
 
-	cmd.Flags().IntSliceVarP(&supersedes, "supersedes", "s", nil,
-		"ID of ADR(s) that this new ADR supersedes")
+	cmd.Flags().StringSliceVarP(&supersedes, "supersedes", "s", nil,
+		`ID of ADR(s) that this new ADR supersedes; "payments:12" names one in another root`)
 	cmd.Flags().StringSliceVar(&scopes, "scope", nil,
-		"scope value(s) from the project vocabulary (repeatable or comma-separated; requires the nygard-scoped template)")
+		"scope value(s) from the project vocabulary (repeatable or comma-separated; requires a template with a Scope field)")
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false,
+		"guide through each template section, then offer to supersede or relate existing ADRs")
+	cmd.Flags().StringVarP(&templateName, "template", "t", "",
+		"template to use instead of the project default")
+	cmd.Flags().StringArrayVar(&vars, "var", nil,
+		"custom template value as key=value, available as {{.Vars.key}} (repeatable)")
+	cmd.Flags().StringArrayVar(&fields, "field", nil,
+		"template field value as key=value, e.g. consulted=\"Alice, Bob\" (repeatable)")
 	return cmd
 }
 
-// resolveScopes validates the given scope values against the project vocabulary,
-// returning them in canonical spelling and order, deduplicated. Empty entries
-// (e.g. from a trailing comma) are ignored. Any value not in the vocabulary is a
-// hard error listing the valid scopes.
-func resolveScopes(cfg *adr.Config, values []string) ([]string, error) {
-	var canonical []string
-	var invalid []string
-	seen := make(map[string]bool)
+// gitUserName returns git's user.name, or "" when git or the setting is
+// unavailable. A variable so tests can stub it.
+var gitUserName = func() string {
+	out, err := exec.Command("git", "config", "user.name").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
 
-	for _, v := range values {
-		if strings.TrimSpace(v) == "" {
-			continue
+// parseKeyValues parses the key=value pairs of a repeatable flag such as --var.
+// Values may contain "="; a repeated key keeps its last value.
+func parseKeyValues(flag string, pairs []string) (map[string]string, error) {
+	vars := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		key, value, ok := strings.Cut(p, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("invalid %s %q: expected key=value", flag, p)
 		}
-		name, ok := cfg.HasScope(v)
-		if !ok {
-			invalid = append(invalid, v)
-			continue
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+// resolveFields maps --field values onto the template's section defs by key,
+// in canonical scope spelling for vocabulary fields. A key the template does
+// not define is an error listing the ones it does.
+func resolveFields(cfg *adr.Config, templateName string, defs []adr.TemplateSectionDef, values map[string]string) (map[string]string, error) {
+	sections := make(map[string]string, len(values))
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		i := slices.IndexFunc(defs, func(d adr.TemplateSectionDef) bool { return d.Key == key })
+		if i < 0 {
+			valid := make([]string, len(defs))
+			for j, d := range defs {
+				valid[j] = d.Key
+			}
+			return nil, fmt.Errorf("template %q has no field %q; valid fields are %s", templateName, key, strings.Join(valid, ", "))
 		}
-		if !seen[name] {
-			seen[name] = true
-			canonical = append(canonical, name)
+		value := values[key]
+		if defs[i].Vocabulary {
+			scopes, err := resolveScopes(cfg, adr.SplitListValue(value))
+			if err != nil {
+				return nil, err
+			}
+			value = strings.Join(scopes, ", ")
 		}
+		sections[key] = value
 	}
+	return sections, nil
+}
 
-	if len(invalid) > 0 {
-		return nil, fmt.Errorf("unknown scope(s) %v; valid scopes are %v", invalid, cfg.Scopes)
-	}
-	return canonical, nil
+// resolveScopes validates the given scope values against the project vocabulary,
+// returning them in canonical spelling and order, deduplicated. Empty entries
+// (e.g. from a trailing comma) are ignored. Any value not in the vocabulary is a
+// hard error listing the valid scopes.
+func resolveScopes(cfg *adr.Config, values []string) ([]string, error) {
+	return adr.CanonicalScopes(cfg.Scopes, values)
 }
 
 // deduplicateIDs validates and deduplicates a slice of ADR IDs.
@@ -188,3 +341,27 @@ func deduplicateIDs(ids []int) ([]int, error) {
 	sort.Ints(result)
 	return result, nil
 }
+
+// parseIDs parses, deduplicates and sorts ADR IDs given on the command line,
+// which may be qualified by a root ("payments:12").
+func parseIDs(args []string) ([]adr.ID, error) {
+	seen := make(map[adr.ID]bool)
+	var result []adr.ID
+	for _, arg := range args {
+		id, err := adr.ParseID(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Root != result[j].Root {
+			return result[i].Root < result[j].Root
+		}
+		return result[i].Number < result[j].Number
+	})
+	return result, nil
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/BobMali/adr-helper/internal/adr"
 	"github.com/BobMali/adr-helper/internal/cli"
@@ -51,6 +52,93 @@ func TestNewCmd_CreatesFirstADR(t *testing.T) {
 	assert.Contains(t, string(content), "# 1. Use Go for CLI")
 }
 
+func TestNewCmd_TransliteratesAndCapsSlug(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	cfg, err := adr.LoadConfig(tmpDir)
+	require.NoError(t, err)
+	cfg.SlugMaxLength = 24
+	require.NoError(t, adr.SaveConfig(tmpDir, cfg))
+
+	root := cli.NewRootCmd()
+	root.SetArgs([]string{"new", "Überarbeitung der Authentifizierung"})
+	require.NoError(t, root.Execute())
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "docs/adr", "0001-ueberarbeitung-der.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "# 1. Überarbeitung der Authentifizierung")
+}
+
+func TestNewCmd_UsesConfiguredNaming(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	cfg, err := adr.LoadConfig(tmpDir)
+	require.NoError(t, err)
+	cfg.NumberPrefix = "API-"
+	cfg.NumberWidth = 3
+	require.NoError(t, adr.SaveConfig(tmpDir, cfg))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "docs/adr", "API-006-use-grpc.md"),
+		[]byte("# 6. Use gRPC\n\nDate: 2024-01-01\n\n## Status\n\nAccepted\n"), 0o644))
+
+	root := cli.NewRootCmd()
+	root.SetArgs([]string{"new", "--supersedes", "6", "Use Connect"})
+	require.NoError(t, root.Execute())
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "docs/adr", "API-007-use-connect.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "# 7. Use Connect")
+	old, err := os.ReadFile(filepath.Join(tmpDir, "docs/adr", "API-006-use-grpc.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(old), "Superseded by [API-007](API-007-use-connect.md)")
+}
+
+func TestNewCmd_DatePatternCollision_ReturnsConflict(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	cfg, err := adr.LoadConfig(tmpDir)
+	require.NoError(t, err)
+	cfg.FilenamePattern = "{date}-{slug}.md"
+	require.NoError(t, adr.SaveConfig(tmpDir, cfg))
+
+	root := cli.NewRootCmd()
+	root.SetArgs([]string{"new", "Use Go"})
+	require.NoError(t, root.Execute())
+	path := filepath.Join(tmpDir, "docs/adr", time.Now().Format("2006-01-02")+"-use-go.md")
+	first, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	root = cli.NewRootCmd()
+	root.SetArgs([]string{"new", "Use Go"})
+	err = root.Execute()
+	require.ErrorIs(t, err, adr.ErrConflict)
+	after, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(first), string(after))
+}
+
+func TestNewCmd_NumbersAcrossSubdirectories(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	cfg, err := adr.LoadConfig(tmpDir)
+	require.NoError(t, err)
+	cfg.Recursive = true
+	require.NoError(t, adr.SaveConfig(tmpDir, cfg))
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "docs/adr/platform"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "docs/adr/platform", "0004-use-kafka.md"),
+		[]byte("# 4. Use Kafka\n\nDate: 2024-01-01\n\n## Status\n\nAccepted\n"), 0o644))
+
+	root := cli.NewRootCmd()
+	root.SetArgs([]string{"new", "--supersedes", "4", "Use Pulsar"})
+	require.NoError(t, root.Execute())
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "docs/adr", "0005-use-pulsar.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "[ADR-0004](platform/0004-use-kafka.md)")
+	old, err := os.ReadFile(filepath.Join(tmpDir, "docs/adr/platform", "0004-use-kafka.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(old), "Superseded by [ADR-0005](../0005-use-pulsar.md)")
+}
+
 func TestNewCmd_IncrementsFromExisting(t *testing.T) {
 	tmpDir := chdirTemp(t)
 	initWorkspace(t, tmpDir, "docs/adr", "nygard")
@@ -585,3 +673,130 @@ func TestNewCmd_Scope_ValidValueButTemplateHasNoScopeLine_Errors(t *testing.T) {
 	_, statErr := os.Stat(filepath.Join(tmpDir, dir, "0001-drifted.md"))
 	assert.True(t, os.IsNotExist(statErr))
 }
+
+func TestNewCmd_TemplateFlag(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+
+	root := cli.NewRootCmd()
+	root.SetOut(new(bytes.Buffer))
+	root.SetArgs([]string{"new", "--template", "madr-minimal", "Use Kafka"})
+	require.NoError(t, root.Execute())
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "docs/adr", "0001-use-kafka.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "## Considered Options")
+}
+
+func TestNewCmd_UnknownTemplate(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+
+	root := cli.NewRootCmd()
+	root.SetArgs([]string{"new", "--template", "nope", "Use Kafka"})
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+
+	err := root.Execute()
+	assert.ErrorIs(t, err, adr.ErrUnknownTemplate)
+}
+
+func TestNewCmd_TemplateVariables(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	templ := "{{/* adr:template */}}\n# Title\n\nDate:\n{{if .Vars.team}}Team: {{.Vars.team}}\n{{end}}\n" +
+		"## Status\n\nProposed\n\n## Context\n\nWhy? ({{.Vars.ticket}})\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "docs/adr", "template.md"), []byte(templ), 0o644))
+
+	root := cli.NewRootCmd()
+	root.SetOut(new(bytes.Buffer))
+	root.SetArgs([]string{"new", "Use Kafka", "--var", "team=payments", "--var", "ticket=ABC=1"})
+	require.NoError(t, root.Execute())
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "docs/adr", "0001-use-kafka.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "# 1. Use Kafka")
+	assert.Contains(t, string(content), "Team: payments\n")
+	assert.Contains(t, string(content), "## Context\n\nWhy? (ABC=1)\n")
+}
+
+func TestNewCmd_TemplateRendersScopes(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	dir := "docs/adr"
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, dir), 0o755))
+	templ := "{{/* adr:template */}}\n# Title\n\nDate:\n{{if .Scopes}}Areas: {{join .Scopes \" & \"}}\n{{end}}\n## Status\n\nProposed\n\n## Context\n\nWhy?\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, dir, "template.md"), []byte(templ), 0o644))
+	cfg := &adr.Config{Directory: dir, Template: "nygard", TemplateFile: "template.md", Scopes: []string{"Backend", "Infra"}}
+	require.NoError(t, adr.SaveConfig(tmpDir, cfg))
+
+	root := cli.NewRootCmd()
+	root.SetOut(new(bytes.Buffer))
+	root.SetArgs([]string{"new", "Scoped", "--scope", "infra,backend"})
+	require.NoError(t, root.Execute())
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, dir, "0001-scoped.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Areas: Infra & Backend\n")
+}
+
+func TestNewCmd_InvalidVar(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+
+	root := cli.NewRootCmd()
+	root.SetArgs([]string{"new", "Use Kafka", "--var", "team"})
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+
+	err := root.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected key=value")
+}
+
+func TestNewCmd_FieldFlag_WritesFrontmatterListsAndSections(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "madr-full")
+
+	root := cli.NewRootCmd()
+	root.SetArgs([]string{"new", "Use Kafka",
+		"--field", "decision-makers=Alice, Bob",
+		"--field", "informed=Everyone",
+		"--field", "context-and-problem-statement=We need a queue."})
+	require.NoError(t, root.Execute())
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "docs/adr", "0001-use-kafka.md"))
+	require.NoError(t, err)
+	s := string(content)
+	assert.Contains(t, s, "\ndecision-makers:\n  - Alice\n  - Bob\nconsulted: {")
+	assert.Contains(t, s, "\ninformed: Everyone\n---\n")
+	assert.Contains(t, s, "## Context and Problem Statement\n\nWe need a queue.\n")
+}
+
+func TestNewCmd_FieldFlag_VocabularyFieldIsCanonicalized(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initScopedWorkspace(t, tmpDir, []string{"Backend", "API"})
+
+	root := cli.NewRootCmd()
+	root.SetArgs([]string{"new", "Combo", "--field", "scope=api, backend"})
+	require.NoError(t, root.Execute())
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "docs/adr", "0001-combo.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Scope: API, Backend")
+}
+
+func TestNewCmd_FieldFlag_UnknownKey(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+
+	root := cli.NewRootCmd()
+	root.SetArgs([]string{"new", "Use Kafka", "--field", "owners=Alice"})
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+
+	err := root.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `template "nygard" has no field "owners"; valid fields are context, decision, consequences`)
+	_, statErr := os.Stat(filepath.Join(tmpDir, "docs/adr", "0001-use-kafka.md"))
+	assert.True(t, os.IsNotExist(statErr))
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// NewRenameCmd creates the rename subcommand for retitling an ADR.
+func NewRenameCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rename <id> <new title>",
+		Short: "Retitle an ADR, renaming its file and rewriting inbound links",
+		Long: `Retitle an ADR. The "# N. Title" heading is updated, the file is renamed to
+the new title's slug, and every link to the old filename in other ADRs is
+rewritten. The changes are applied as one crash-safe operation.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, id, err := loadADRConfig(cmd, args[0])
+			if err != nil {
+				return err
+			}
+
+			repo := cfg.Repository()
+			oldFile, err := repo.FindFile(id)
+			if err != nil {
+				return err
+			}
+
+			record, err := repo.Rename(cmd.Context(), id, args[1])
+			if err != nil {
+				return err
+			}
+
+			newFile, err := cfg.Naming().RenamedFilename(oldFile, id, record.Title)
+			if err != nil {
+				return err
+			}
+			if newFile == oldFile {
+				fmt.Fprintf(cmd.OutOrStdout(), "Retitled %s to %q\n", oldFile, record.Title)
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Renamed %s to %s\n", oldFile, newFile)
+			return nil
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,69 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BobMali/adr-helper/internal/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRenameCmd_UseAndShort(t *testing.T) {
+	cmd := cli.NewRenameCmd()
+	assert.Equal(t, "rename <id> <new title>", cmd.Use)
+	assert.Contains(t, cmd.Short, "Retitle")
+}
+
+func TestRenameCmd_RenamesAndRewritesLinks(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	dir := filepath.Join(tmpDir, "docs/adr")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0001-use-mysql.md"),
+		[]byte("# 1. Use MySQL\n\nDate: 2024-01-01\n\n## Status\n\nAccepted\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0002-pooling.md"),
+		[]byte("# 2. Pooling\n\nDate: 2024-01-01\n\n## Status\n\nAccepted\n\n## Relations\n\nRelates to [ADR-0001](0001-use-mysql.md)  \n"), 0o644))
+
+	buf := new(bytes.Buffer)
+	root := cli.NewRootCmd()
+	root.SetOut(buf)
+	root.SetArgs([]string{"rename", "1", "Use PostgreSQL"})
+	require.NoError(t, root.Execute())
+
+	assert.Contains(t, buf.String(), "Renamed 0001-use-mysql.md to 0001-use-postgresql.md")
+	content, err := os.ReadFile(filepath.Join(dir, "0001-use-postgresql.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "# 1. Use PostgreSQL")
+
+	other, err := os.ReadFile(filepath.Join(dir, "0002-pooling.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(other), "Relates to [ADR-0001](0001-use-postgresql.md)")
+}
+
+func TestRenameCmd_SameSlugRetitlesOnly(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	dir := filepath.Join(tmpDir, "docs/adr")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0001-use-go.md"),
+		[]byte("# 1. use go\n\n## Status\n\nAccepted\n"), 0o644))
+
+	buf := new(bytes.Buffer)
+	root := cli.NewRootCmd()
+	root.SetOut(buf)
+	root.SetArgs([]string{"rename", "1", "Use Go"})
+	require.NoError(t, root.Execute())
+
+	assert.Contains(t, buf.String(), `Retitled 0001-use-go.md to "Use Go"`)
+}
+
+func TestRenameCmd_InvalidID(t *testing.T) {
+	chdirTemp(t)
+
+	root := cli.NewRootCmd()
+	root.SetArgs([]string{"rename", "abc", "X"})
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+	assert.Error(t, root.Execute())
+}
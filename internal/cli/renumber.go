@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/spf13/cobra"
+)
+
+// NewRenumberCmd creates the renumber subcommand for moving an ADR to a new number.
+func NewRenumberCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "renumber <old id|filename> <new id>",
+		Short: "Move an ADR to a new number, rewriting inbound links",
+		Long: `Move an ADR to a new number. The file is renamed (keeping its slug), its
+"# N." heading is updated, and every link to it in other ADRs is retargeted and
+relabelled. The changes are applied as one crash-safe operation.
+
+When two files share a number (see "adr fix-duplicates"), name the file to move
+instead of its ID.
+
+Examples:
+  adr renumber 12 14
+  adr renumber 0012-use-kafka.md 14`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			newID, err := parseADRID(args[1])
+			if err != nil {
+				return err
+			}
+
+			var cfg *adr.Config
+			var filename string
+			if _, perr := adr.ParseID(args[0]); perr == nil {
+				var oldID int
+				if cfg, oldID, err = loadADRConfig(cmd, args[0]); err != nil {
+					return err
+				}
+				if filename, err = cfg.Repository().FindFile(oldID); err != nil {
+					return err
+				}
+				_, err = cfg.Repository().Renumber(cmd.Context(), oldID, newID)
+			} else {
+				if cfg, err = loadConfig(cmd); err != nil {
+					return err
+				}
+				name := filepath.Base(args[0])
+				if !cfg.Naming().Matches(name) {
+					return fmt.Errorf("invalid ADR ID %q: must be a number or an ADR filename", args[0])
+				}
+				filename = name
+				// A path into the ADR directory picks a file in a subdirectory.
+				if rel, err := filepath.Rel(cfg.Directory, args[0]); err == nil && filepath.IsLocal(rel) {
+					filename = filepath.ToSlash(rel)
+				}
+				_, err = cfg.Repository().RenumberFile(cmd.Context(), filename, newID)
+			}
+			if err != nil {
+				return err
+			}
+
+			repo := cfg.Repository()
+			newFile, err := repo.FindFile(newID)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Renumbered %s to %s\n", filename, newFile)
+			return nil
+		},
+	}
+	return cmd
+}
+
+// NewFixDuplicatesCmd creates the fix-duplicates subcommand, which resolves ADR
+// numbers claimed by more than one file.
+func NewFixDuplicatesCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "fix-duplicates",
+		Short: "Renumber ADRs whose number collides with another file",
+		Long: `Detect ADR numbers claimed by more than one file — typically after merging
+branches that each ran "adr new" — and resolve them. In each collision the
+oldest ADR (by Date, then filename) keeps its number; every newer one moves to
+the next free number with its heading and all inbound links updated.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+			repo := cfg.Repository()
+
+			plan, err := repo.PlanDuplicateFixes(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if len(plan) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No duplicate ADR numbers found")
+				return nil
+			}
+
+			for _, step := range plan {
+				if dryRun {
+					fmt.Fprintf(cmd.OutOrStdout(), "Would renumber %s to %s\n", step.From.Filename, step.To.Filename)
+					continue
+				}
+				if _, err := repo.RenumberFile(cmd.Context(), step.From.Filename, step.To.Number); err != nil {
+					return fmt.Errorf("renumbering %s: %w", step.From.Filename, err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Renumbered %s to %s\n", step.From.Filename, step.To.Filename)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would be renumbered without changing files")
+	return cmd
+}
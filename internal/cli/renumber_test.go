@@ -0,0 +1,114 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BobMali/adr-helper/internal/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRenumberCmd_UseAndShort(t *testing.T) {
+	cmd := cli.NewRenumberCmd()
+	assert.Equal(t, "renumber <old id|filename> <new id>", cmd.Use)
+	assert.Contains(t, cmd.Short, "new number")
+}
+
+func TestRenumberCmd_ByID(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	dir := filepath.Join(tmpDir, "docs/adr")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0001-use-go.md"),
+		[]byte("# 1. Use Go\n\n## Status\n\nAccepted\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0002-tooling.md"),
+		[]byte("# 2. Tooling\n\n## Status\n\nAccepted\n\nRelates to [ADR-0001](0001-use-go.md)  \n"), 0o644))
+
+	buf := new(bytes.Buffer)
+	root := cli.NewRootCmd()
+	root.SetOut(buf)
+	root.SetArgs([]string{"renumber", "1", "5"})
+	require.NoError(t, root.Execute())
+
+	assert.Contains(t, buf.String(), "Renumbered 0001-use-go.md to 0005-use-go.md")
+	other, err := os.ReadFile(filepath.Join(dir, "0002-tooling.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(other), "[ADR-0005](0005-use-go.md)")
+}
+
+func TestRenumberCmd_ByFilename(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	dir := filepath.Join(tmpDir, "docs/adr")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0002-b.md"), []byte("# 2. B\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0002-c.md"), []byte("# 2. C\n"), 0o644))
+
+	buf := new(bytes.Buffer)
+	root := cli.NewRootCmd()
+	root.SetOut(buf)
+	root.SetArgs([]string{"renumber", "0002-c.md", "3"})
+	require.NoError(t, root.Execute())
+
+	assert.Contains(t, buf.String(), "Renumbered 0002-c.md to 0003-c.md")
+	_, err := os.Stat(filepath.Join(dir, "0002-b.md"))
+	assert.NoError(t, err)
+}
+
+func TestRenumberCmd_AmbiguousID(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	dir := filepath.Join(tmpDir, "docs/adr")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0002-b.md"), []byte("# 2. B\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0002-c.md"), []byte("# 2. C\n"), 0o644))
+
+	root := cli.NewRootCmd()
+	root.SetArgs([]string{"renumber", "2", "3"})
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+	err := root.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ambiguous")
+}
+
+func TestFixDuplicatesCmd_NoDuplicates(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+
+	buf := new(bytes.Buffer)
+	root := cli.NewRootCmd()
+	root.SetOut(buf)
+	root.SetArgs([]string{"fix-duplicates"})
+	require.NoError(t, root.Execute())
+	assert.Contains(t, buf.String(), "No duplicate ADR numbers found")
+}
+
+func TestFixDuplicatesCmd_DryRunAndApply(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	dir := filepath.Join(tmpDir, "docs/adr")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0001-old.md"),
+		[]byte("# 1. Old\n\nDate: 2024-01-01\n\n## Status\n\nAccepted\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0001-new.md"),
+		[]byte("# 1. New\n\nDate: 2024-05-01\n\n## Status\n\nAccepted\n"), 0o644))
+
+	buf := new(bytes.Buffer)
+	root := cli.NewRootCmd()
+	root.SetOut(buf)
+	root.SetArgs([]string{"fix-duplicates", "--dry-run"})
+	require.NoError(t, root.Execute())
+	assert.Contains(t, buf.String(), "Would renumber 0001-new.md to 0002-new.md")
+	_, err := os.Stat(filepath.Join(dir, "0001-new.md"))
+	require.NoError(t, err)
+
+	buf.Reset()
+	root = cli.NewRootCmd()
+	root.SetOut(buf)
+	root.SetArgs([]string{"fix-duplicates"})
+	require.NoError(t, root.Execute())
+	assert.Contains(t, buf.String(), "Renumbered 0001-new.md to 0002-new.md")
+	content, err := os.ReadFile(filepath.Join(dir, "0002-new.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "# 2. New")
+}
@@ -1,6 +1,12 @@
 package cli
 
-import "github.com/spf13/cobra"
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/spf13/cobra"
+)
 
 // NewRootCmd creates and returns the root Cobra command for adr-cli.
 func NewRootCmd() *cobra.Command {
@@ -15,5 +21,63 @@ func NewRootCmd() *cobra.Command {
 	cmd.AddCommand(NewShowCmd())
 	cmd.AddCommand(NewListCmd())
 	cmd.AddCommand(NewScopeCmd())
+	cmd.AddCommand(NewEditCmd())
+	cmd.AddCommand(NewRenameCmd())
+	cmd.AddCommand(NewRenumberCmd())
+	cmd.AddCommand(NewFixDuplicatesCmd())
+	cmd.AddCommand(NewArchiveCmd())
+	cmd.AddCommand(NewConvertCmd())
+	cmd.AddCommand(NewImportCmd())
+	cmd.AddCommand(NewExportCmd())
+	cmd.AddCommand(NewTOCCmd())
+	cmd.PersistentFlags().String("root", "", `ADR root to work in, by name (see "roots" in .adr.json)`)
 	return cmd
 }
+
+// loadConfig loads the project config of the root chosen with --root, or of
+// the default root.
+func loadConfig(cmd *cobra.Command) (*adr.Config, error) {
+	cfg, err := adr.LoadConfig(".")
+	if err != nil {
+		return nil, err
+	}
+	root, _ := cmd.Flags().GetString("root")
+	return cfg.Root(root)
+}
+
+// loadADRConfig loads the project config of the root the ADR ID arg belongs
+// to: the one named in a qualified ID ("payments:12"), else the one chosen
+// with --root. It returns the ADR's number in that root.
+func loadADRConfig(cmd *cobra.Command, arg string) (*adr.Config, int, error) {
+	id, err := adr.ParseID(arg)
+	if err != nil {
+		return nil, 0, err
+	}
+	root, _ := cmd.Flags().GetString("root")
+	if id.Root != "" && root != "" && id.Root != root {
+		return nil, 0, fmt.Errorf("ADR %s is not in root %q", id, root)
+	}
+	if id.Root != "" {
+		root = id.Root
+	}
+	cfg, err := adr.LoadConfig(".")
+	if err != nil {
+		return nil, 0, err
+	}
+	if cfg, err = cfg.Root(root); err != nil {
+		return nil, 0, err
+	}
+	return cfg, id.Number, nil
+}
+
+// parseADRID parses a positive ADR ID from a command-line argument.
+func parseADRID(arg string) (int, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ADR ID %q: must be a number", arg)
+	}
+	if id <= 0 {
+		return 0, fmt.Errorf("invalid ADR ID %d: must be positive", id)
+	}
+	return id, nil
+}
@@ -0,0 +1,132 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/BobMali/adr-helper/internal/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// initRootsWorkspace creates a project with a payments and a billing root and
+// no top-level ADR directory, so their shared template file lives in the
+// project directory.
+func initRootsWorkspace(t *testing.T, tmpDir string) {
+	t.Helper()
+	cfg := &adr.Config{Template: "nygard", TemplateFile: "template.md"}
+	content, err := adr.TemplateContent("nygard")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "template.md"), []byte(content), 0o644))
+	for _, name := range []string{"payments", "billing"} {
+		dir := filepath.Join("services", name, "docs/adr")
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, dir), 0o755))
+		cfg.Roots = append(cfg.Roots, adr.RootDef{Name: name, Directory: dir})
+	}
+	require.NoError(t, adr.SaveConfig(tmpDir, cfg))
+}
+
+func runRoot(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	root := cli.NewRootCmd()
+	root.SetOut(buf)
+	root.SetErr(new(bytes.Buffer))
+	root.SetArgs(args)
+	err := root.Execute()
+	return buf.String(), err
+}
+
+func TestRoots_NewNumbersEachRootSeparately(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initRootsWorkspace(t, tmpDir)
+
+	_, err := runRoot(t, "new", "Use Stripe")
+	assert.ErrorIs(t, err, adr.ErrRootRequired)
+
+	for _, args := range [][]string{
+		{"new", "--root", "payments", "Use Stripe"},
+		{"new", "--root", "payments", "Use Adyen"},
+		{"new", "--root", "billing", "Use Invoices"},
+	} {
+		_, err := runRoot(t, args...)
+		require.NoError(t, err, args)
+	}
+	assert.FileExists(t, filepath.Join(tmpDir, "services/payments/docs/adr/0002-use-adyen.md"))
+	assert.FileExists(t, filepath.Join(tmpDir, "services/billing/docs/adr/0001-use-invoices.md"))
+
+	out, err := runRoot(t, "show", "--plain", "billing:1")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Use Invoices")
+	out, err = runRoot(t, "show", "--plain", "--root", "payments", "1")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Use Stripe")
+	_, err = runRoot(t, "show", "--root", "payments", "billing:1")
+	assert.Error(t, err)
+	_, err = runRoot(t, "show", "shipping:1")
+	assert.ErrorIs(t, err, adr.ErrUnknownRoot)
+}
+
+func TestRoots_CombinedListing(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initRootsWorkspace(t, tmpDir)
+	for _, args := range [][]string{
+		{"new", "--root", "payments", "Use Stripe"},
+		{"new", "--root", "billing", "Use Invoices"},
+	} {
+		_, err := runRoot(t, args...)
+		require.NoError(t, err, args)
+	}
+
+	out := runList(t, "--plain")
+	assert.Contains(t, out, "Root")
+	assert.Regexp(t, `payments\s+1\s+\S+\s+Use Stripe`, out)
+	assert.Regexp(t, `billing\s+1\s+\S+\s+Use Invoices`, out)
+
+	var records []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(runList(t, "--json")), &records))
+	require.Len(t, records, 2)
+	assert.Equal(t, "payments", records[0]["root"])
+	assert.Equal(t, "billing", records[1]["root"])
+
+	out = runList(t, "--plain", "--root", "billing")
+	assert.NotContains(t, out, "Root")
+	assert.NotContains(t, out, "Use Stripe")
+	assert.Contains(t, out, "Use Invoices")
+}
+
+func TestRoots_SupersedeAcrossRoots(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initRootsWorkspace(t, tmpDir)
+	_, err := runRoot(t, "new", "--root", "billing", "Bill Monthly")
+	require.NoError(t, err)
+
+	_, err = runRoot(t, "new", "--root", "payments", "-s", "billing:1", "Bill Per Use")
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "services/payments/docs/adr/0001-bill-per-use.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Supersedes [billing:ADR-0001](../../../billing/docs/adr/0001-bill-monthly.md)")
+	content, err = os.ReadFile(filepath.Join(tmpDir, "services/billing/docs/adr/0001-bill-monthly.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Superseded by [payments:ADR-0001](../../../payments/docs/adr/0001-bill-per-use.md)")
+}
+
+func TestRoots_ScopesAreSavedPerRoot(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initRootsWorkspace(t, tmpDir)
+
+	_, err := runRoot(t, "scope", "add", "--root", "payments", "Checkout")
+	require.NoError(t, err)
+
+	cfg, err := adr.LoadConfig(tmpDir)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Scopes)
+	payments, err := cfg.Root("payments")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Checkout"}, payments.Scopes)
+}
@@ -31,7 +31,7 @@ func newScopeAddCmd() *cobra.Command {
 		Short: "Add one or more scopes to the vocabulary",
 		Args:  cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cfg, err := adr.LoadConfig(".")
+			cfg, err := loadConfig(cmd)
 			if err != nil {
 				return err
 			}
@@ -75,7 +75,7 @@ Note: a running web server loads its config once at startup and will not see
 newly-discovered scopes until it is restarted.`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cfg, err := adr.LoadConfig(".")
+			cfg, err := loadConfig(cmd)
 			if err != nil {
 				return err
 			}
@@ -114,7 +114,7 @@ func newScopeListCmd() *cobra.Command {
 		Short: "List the scope vocabulary",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cfg, err := adr.LoadConfig(".")
+			cfg, err := loadConfig(cmd)
 			if err != nil {
 				return err
 			}
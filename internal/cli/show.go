@@ -5,19 +5,19 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strconv"
 
 	"github.com/BobMali/adr-helper/internal/adr"
 	"github.com/spf13/cobra"
 )
 
 type showJSON struct {
-	Number int    `json:"number"`
-	Title  string `json:"title"`
-	Status string `json:"status"`
-	Date   string `json:"date"`
-	File   string `json:"file"`
-	Body   string `json:"body"`
+	Number   int           `json:"number"`
+	Title    string        `json:"title"`
+	Status   string        `json:"status"`
+	Date     string        `json:"date"`
+	File     string        `json:"file"`
+	Body     string        `json:"body"`
+	Sections []adr.Section `json:"sections"`
 }
 
 // NewShowCmd creates the show subcommand for displaying an ADR in the terminal.
@@ -30,20 +30,12 @@ func NewShowCmd() *cobra.Command {
 		Short: "Display an ADR in the terminal",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			id, err := strconv.Atoi(args[0])
-			if err != nil {
-				return fmt.Errorf("invalid ADR ID %q: must be a number", args[0])
-			}
-			if id <= 0 {
-				return fmt.Errorf("invalid ADR ID %d: must be positive", id)
-			}
-
-			cfg, err := adr.LoadConfig(".")
+			cfg, id, err := loadADRConfig(cmd, args[0])
 			if err != nil {
 				return err
 			}
 
-			filename, err := adr.FindADRFile(cfg.Directory, id)
+			filename, err := cfg.Repository().FindFile(id)
 			if err != nil {
 				return err
 			}
@@ -67,6 +59,10 @@ func NewShowCmd() *cobra.Command {
 					File:   filename,
 					Body:   string(content),
 				}
+				out.Sections = adr.ParseDocument(out.Body, adr.KnownSections(cfg, nil)).Sections
+				if out.Sections == nil {
+					out.Sections = []adr.Section{}
+				}
 				return json.NewEncoder(cmd.OutOrStdout()).Encode(out)
 			}
 
@@ -196,6 +196,30 @@ func TestShowCmd_JSON_BodyContainsRawMarkdown(t *testing.T) {
 	assert.Equal(t, adrContent, result["body"])
 }
 
+func TestShowCmd_JSON_Sections(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+
+	adrContent := "# 1. Use Go\n\nDate: 2024-01-01\n\n## Status\n\nAccepted\n\n## Context\n\nSome context.\n\n## Notes\n\nExtra.\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "docs/adr", "0001-use-go.md"), []byte(adrContent), 0o644))
+
+	buf := new(bytes.Buffer)
+	root := cli.NewRootCmd()
+	root.SetOut(buf)
+	root.SetArgs([]string{"show", "1", "--json"})
+	require.NoError(t, root.Execute())
+
+	var result struct {
+		Sections []map[string]interface{} `json:"sections"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	require.Len(t, result.Sections, 3)
+	assert.Equal(t, map[string]interface{}{"level": float64(2), "heading": "Status", "body": "Accepted"}, result.Sections[0])
+	assert.Equal(t, map[string]interface{}{"level": float64(2), "heading": "Context", "key": "context", "body": "Some context."}, result.Sections[1])
+	assert.Equal(t, "Notes", result.Sections[2]["heading"])
+	assert.NotContains(t, result.Sections[2], "key")
+}
+
 func TestShowCmd_JSON_MADRFullFrontmatter(t *testing.T) {
 	tmpDir := chdirTemp(t)
 	initWorkspace(t, tmpDir, "docs/adr", "madr-full")
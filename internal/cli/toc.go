@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/spf13/cobra"
+)
+
+// NewTOCCmd creates the toc subcommand for maintaining an ADR index in a
+// markdown file.
+func NewTOCCmd() *cobra.Command {
+	var groupBy string
+	var check bool
+
+	cmd := &cobra.Command{
+		Use:   "toc [file]",
+		Short: "Write or check the ADR table of contents in a markdown file",
+		Args:  cobra.MaximumNArgs(1),
+		Long: `Write the ADR table of contents into file (default: README.md in the ADR
+directory), between the markers
+
+  ` + adr.TOCStartMarker + `
+  ` + adr.TOCEndMarker + `
+
+The rest of the file is left alone. Without the markers the block is appended,
+and a missing file is created. ADRs are listed with links, dates and
+superseded-by pointers, grouped by status or scope.
+
+With --check nothing is written, and the command fails when the table of
+contents is stale, e.g. to guard it in CI.
+
+Examples:
+  adr toc
+  adr toc README.md --group-by scope
+  adr toc --check`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+			file := filepath.Join(cfg.Directory, "README.md")
+			if len(args) > 0 {
+				file = args[0]
+			}
+
+			prefix := ""
+			if rel, err := filepath.Rel(filepath.Dir(file), cfg.Directory); err == nil && rel != "." {
+				prefix = filepath.ToSlash(rel) + "/"
+			}
+			block, err := adr.GenerateTOC(cmd.Context(), cfg.Repository(), adr.TOCOptions{GroupBy: groupBy, LinkPrefix: prefix})
+			if err != nil {
+				return err
+			}
+
+			existing, err := os.ReadFile(file)
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("reading %s: %w", file, err)
+			}
+			current := string(existing)
+			if os.IsNotExist(err) {
+				current = "# Architecture Decision Records\n"
+			}
+			updated, err := adr.ReplaceTOCBlock(current, block)
+			if err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+
+			if updated == string(existing) {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s is up to date\n", file)
+				return nil
+			}
+			if check {
+				return fmt.Errorf("table of contents in %s is out of date, run \"adr toc\" to update it", file)
+			}
+			if err := os.WriteFile(file, []byte(updated), 0o644); err != nil {
+				return fmt.Errorf("writing %s: %w", file, err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Updated table of contents in %s\n", file)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&groupBy, "group-by", adr.TOCGroupStatus, "group ADRs by: status, scope, or none")
+	cmd.Flags().BoolVar(&check, "check", false, "fail if the table of contents is out of date instead of writing it")
+	return cmd
+}
@@ -0,0 +1,76 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BobMali/adr-helper/internal/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runTOC(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	root := cli.NewRootCmd()
+	root.SetOut(buf)
+	root.SetArgs(append([]string{"toc"}, args...))
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+	err := root.Execute()
+	return buf.String(), err
+}
+
+func TestTOCCmd_CreatesAndUpdates(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	dir := filepath.Join(tmpDir, "docs/adr")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0001-use-go.md"), []byte(convertSourceADR), 0o644))
+
+	out, err := runTOC(t)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Updated table of contents in docs/adr/README.md")
+	readme, err := os.ReadFile(filepath.Join(dir, "README.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(readme), "# Architecture Decision Records\n\n<!-- adr-toc:start -->")
+	assert.Contains(t, string(readme), "- [ADR-0001: Use Go](0001-use-go.md) — 2024-01-02")
+
+	out, err = runTOC(t)
+	require.NoError(t, err)
+	assert.Contains(t, out, "docs/adr/README.md is up to date")
+}
+
+func TestTOCCmd_OtherFileUsesRelativeLinks(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "docs/adr/0001-use-go.md"), []byte(convertSourceADR), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "README.md"), []byte("# Project\n\n## Decisions\n"), 0o644))
+
+	_, err := runTOC(t, "README.md", "--group-by", "none")
+	require.NoError(t, err)
+	readme, err := os.ReadFile(filepath.Join(tmpDir, "README.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(readme), "# Project\n\n## Decisions\n\n<!-- adr-toc:start -->")
+	assert.Contains(t, string(readme), "(docs/adr/0001-use-go.md)")
+}
+
+func TestTOCCmd_Check(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	dir := filepath.Join(tmpDir, "docs/adr")
+
+	_, err := runTOC(t, "--check")
+	assert.ErrorContains(t, err, "out of date")
+	assert.NoFileExists(t, filepath.Join(dir, "README.md"))
+
+	_, err = runTOC(t)
+	require.NoError(t, err)
+	_, err = runTOC(t, "--check")
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0001-use-go.md"), []byte(convertSourceADR), 0o644))
+	_, err = runTOC(t, "--check")
+	assert.ErrorContains(t, err, "table of contents in docs/adr/README.md is out of date")
+}
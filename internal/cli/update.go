@@ -1,7 +1,6 @@
 package cli
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -19,20 +18,12 @@ func NewUpdateCmd() *cobra.Command {
 		Short: "Update the status of an existing ADR",
 		Args:  cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			id, err := strconv.Atoi(args[0])
-			if err != nil {
-				return fmt.Errorf("invalid ADR ID %q: must be a number", args[0])
-			}
-			if id <= 0 {
-				return fmt.Errorf("invalid ADR ID %d: must be positive", id)
-			}
-
-			cfg, err := adr.LoadConfig(".")
+			cfg, id, err := loadADRConfig(cmd, args[0])
 			if err != nil {
 				return err
 			}
 
-			filename, err := adr.FindADRFile(cfg.Directory, id)
+			filename, err := cfg.Repository().FindFile(id)
 			if err != nil {
 				return err
 			}
@@ -93,21 +84,25 @@ func resolveStatus(input string) (string, error) {
 
 // promptStatus displays an interactive menu and reads the user's choice.
 func promptStatus(cmd *cobra.Command) (string, error) {
+	return newPrompter(cmd).status()
+}
+
+// status displays the status menu and reads the user's choice. It is the
+// prompter form of promptStatus, for flows that ask more than one question.
+func (p *prompter) status() (string, error) {
 	allStatuses := adr.AllStatusStrings()
 
-	out := cmd.OutOrStdout()
-	fmt.Fprintln(out, "Select a status:")
+	fmt.Fprintln(p.out, "Select a status:")
 	for i, s := range allStatuses {
-		fmt.Fprintf(out, "  %d) %s\n", i+1, s)
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, s)
 	}
-	fmt.Fprint(out, "Enter choice: ")
+	fmt.Fprint(p.out, "Enter choice: ")
 
-	scanner := bufio.NewScanner(cmd.InOrStdin())
-	if !scanner.Scan() {
+	if !p.in.Scan() {
 		return "", fmt.Errorf("invalid choice: no input")
 	}
 
-	line := strings.TrimSpace(scanner.Text())
+	line := strings.TrimSpace(p.in.Text())
 	choice, err := strconv.Atoi(line)
 	if err != nil || choice < 1 || choice > len(allStatuses) {
 		return "", fmt.Errorf("invalid choice: %q", line)
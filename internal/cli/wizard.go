@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/spf13/cobra"
+)
+
+// editDirective, entered as the first line of a section answer, opens the
+// section in $VISUAL/$EDITOR instead of reading it from stdin.
+const editDirective = "!edit"
+
+// endOfSection terminates a multi-line section answer.
+const endOfSection = "."
+
+// prompter reads line-oriented answers from the command's stdin. A single
+// scanner is shared by every prompt so input buffered for one answer isn't lost
+// to the next.
+type prompter struct {
+	cmd *cobra.Command
+	in  *bufio.Scanner
+	out io.Writer
+}
+
+func newPrompter(cmd *cobra.Command) *prompter {
+	return &prompter{cmd: cmd, in: bufio.NewScanner(cmd.InOrStdin()), out: cmd.OutOrStdout()}
+}
+
+// line prints prompt and returns the next input line, trimmed. ok is false at EOF.
+func (p *prompter) line(prompt string) (string, bool) {
+	fmt.Fprint(p.out, prompt)
+	if !p.in.Scan() {
+		fmt.Fprintln(p.out)
+		return "", false
+	}
+	return strings.TrimSpace(p.in.Text()), true
+}
+
+// multiline reads a section body until a line containing only "." or EOF. An
+// empty first line skips the section; "!edit" as the first line opens the
+// placeholder in the user's editor and returns the edited text instead.
+func (p *prompter) multiline(placeholder string) (string, error) {
+	fmt.Fprintf(p.out, "Enter text, ending with a line containing only %q. Leave empty to keep the placeholder, or type %q to open $EDITOR.\n",
+		endOfSection, editDirective)
+
+	var lines []string
+	for {
+		fmt.Fprint(p.out, "> ")
+		if !p.in.Scan() {
+			fmt.Fprintln(p.out)
+			break
+		}
+		text := p.in.Text()
+		trimmed := strings.TrimSpace(text)
+		if len(lines) == 0 {
+			if trimmed == "" {
+				return "", nil
+			}
+			if trimmed == editDirective {
+				return editText(p.cmd, placeholder)
+			}
+		}
+		if trimmed == endOfSection {
+			break
+		}
+		lines = append(lines, strings.TrimRight(text, " \t"))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")), nil
+}
+
+// newWizardAnswers holds everything collected by the guided `adr new --interactive` flow.
+type newWizardAnswers struct {
+	Title      string
+	Sections   map[string]string // keyed by TemplateSectionDef.Key
+	Supersedes []int
+	RelatesTo  []int
+}
+
+// runNewWizard walks the user through each template section in order, showing
+// its placeholder as guidance, then offers to supersede or relate existing ADRs.
+// title is prompted for only when empty. Vocabulary fields are filled from the
+// project's scope list and frontmatter fields from a single comma-separated
+// line; every other field accepts multi-line text or $EDITOR.
+func runNewWizard(cmd *cobra.Command, cfg *adr.Config, title string, defs []adr.TemplateSectionDef) (*newWizardAnswers, error) {
+	p := newPrompter(cmd)
+	answers := &newWizardAnswers{Title: title, Sections: make(map[string]string)}
+
+	for answers.Title == "" {
+		text, ok := p.line("Title: ")
+		if !ok {
+			return nil, fmt.Errorf("a title is required")
+		}
+		if _, err := adr.Slugify(text); err != nil {
+			fmt.Fprintf(p.out, "%v, try again\n", err)
+			continue
+		}
+		answers.Title = text
+	}
+
+	for _, def := range defs {
+		optional := ""
+		if def.Optional {
+			optional = " (optional)"
+		}
+		fmt.Fprintf(p.out, "\n%s%s\n", def.Heading, optional)
+		if def.Placeholder != "" {
+			for _, l := range strings.Split(def.Placeholder, "\n") {
+				fmt.Fprintf(p.out, "  %s\n", l)
+			}
+		}
+
+		var value string
+		var err error
+		switch {
+		case def.Vocabulary:
+			var picked []string
+			picked, err = p.pickScopes(cfg)
+			value = strings.Join(picked, ", ")
+		case def.Kind == "frontmatter":
+			value, _ = p.line("Values (comma-separated, blank to skip): ")
+		default:
+			value, err = p.multiline(def.Placeholder)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if value != "" {
+			answers.Sections[def.Key] = value
+		}
+	}
+
+	existing, err := cfg.Repository().List(cmd.Context())
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) == 0 {
+		return answers, nil
+	}
+
+	fmt.Fprintln(p.out, "\nExisting ADRs:")
+	for _, r := range existing {
+		fmt.Fprintf(p.out, "  %d. %s (%s)\n", r.Number, r.Title, r.Status)
+	}
+	if answers.Supersedes, err = p.pickADRs(cfg, "Supersede ADR(s) (comma-separated IDs, blank for none): "); err != nil {
+		return nil, err
+	}
+	if answers.RelatesTo, err = p.pickADRs(cfg, "Relate to ADR(s) (comma-separated IDs, blank for none): "); err != nil {
+		return nil, err
+	}
+	return answers, nil
+}
+
+// pickScopes offers the project's scope vocabulary as a numbered menu and
+// returns the canonical names chosen, by number or name. Invalid input is
+// reported and re-prompted; a blank line or EOF selects nothing.
+func (p *prompter) pickScopes(cfg *adr.Config) ([]string, error) {
+	if len(cfg.Scopes) == 0 {
+		fmt.Fprintln(p.out, "No scopes defined (add some with `adr scope add`); skipping.")
+		return nil, nil
+	}
+	for i, s := range cfg.Scopes {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, s)
+	}
+	for {
+		text, ok := p.line("Select by number or name (comma-separated, blank to skip): ")
+		if !ok || text == "" {
+			return nil, nil
+		}
+		var values []string
+		for _, tok := range strings.Split(text, ",") {
+			tok = strings.TrimSpace(tok)
+			if n, err := strconv.Atoi(tok); err == nil && n >= 1 && n <= len(cfg.Scopes) {
+				tok = cfg.Scopes[n-1]
+			}
+			values = append(values, tok)
+		}
+		canonical, err := resolveScopes(cfg, values)
+		if err != nil {
+			fmt.Fprintf(p.out, "%v\n", err)
+			continue
+		}
+		return canonical, nil
+	}
+}
+
+// pickADRs prompts for a comma-separated list of existing ADR IDs. Each ID must
+// resolve to a file in the ADR directory; invalid input is reported and
+// re-prompted. A blank line or EOF selects nothing.
+func (p *prompter) pickADRs(cfg *adr.Config, prompt string) ([]int, error) {
+	for {
+		text, ok := p.line(prompt)
+		if !ok || text == "" {
+			return nil, nil
+		}
+		ids, err := parseIDList(text)
+		if err == nil {
+			ids, err = deduplicateIDs(ids)
+		}
+		if err == nil {
+			for _, id := range ids {
+				if _, ferr := cfg.Repository().FindFile(id); ferr != nil {
+					err = ferr
+					break
+				}
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(p.out, "%v, try again\n", err)
+			continue
+		}
+		return ids, nil
+	}
+}
+
+// parseIDList parses a comma-separated list of ADR IDs, ignoring empty entries.
+func parseIDList(s string) ([]int, error) {
+	var ids []int
+	for _, tok := range strings.Split(s, ",") {
+		tok = strings.TrimSpace(tok)
+		if tok == "" {
+			continue
+		}
+		id, err := strconv.Atoi(tok)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ADR ID %q: must be a number", tok)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
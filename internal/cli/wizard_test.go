@@ -0,0 +1,260 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/BobMali/adr-helper/internal/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCmd_Interactive_FillsSectionsInOrder(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+
+	input := strings.Join([]string{
+		"Use Go",
+		"We need a CLI.", "It must be fast.", ".",
+		"Use Go.", ".",
+		"", // keep the Consequences placeholder
+	}, "\n") + "\n"
+
+	out := new(bytes.Buffer)
+	root := cli.NewRootCmd()
+	root.SetOut(out)
+	root.SetIn(strings.NewReader(input))
+	root.SetArgs([]string{"new", "--interactive"})
+	require.NoError(t, root.Execute())
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "docs/adr", "0001-use-go.md"))
+	require.NoError(t, err)
+	s := string(content)
+	assert.Contains(t, s, "# 1. Use Go")
+	assert.Contains(t, s, "## Context\n\nWe need a CLI.\nIt must be fast.\n\n## Decision")
+	assert.Contains(t, s, "## Decision\n\nUse Go.\n\n## Consequences")
+	assert.Contains(t, s, "What becomes easier or more difficult")
+
+	// Each section's placeholder is shown as guidance, in template order.
+	o := out.String()
+	ctx := strings.Index(o, "What is the issue")
+	dec := strings.Index(o, "What is the change")
+	require.True(t, ctx >= 0 && dec > ctx, "placeholders should be shown in section order")
+}
+
+func TestNewCmd_Interactive_TitleArgSkipsTitlePrompt(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+
+	out := new(bytes.Buffer)
+	root := cli.NewRootCmd()
+	root.SetOut(out)
+	root.SetIn(strings.NewReader("Context text\n.\n\n\n"))
+	root.SetArgs([]string{"new", "-i", "Given Title"})
+	require.NoError(t, root.Execute())
+
+	assert.NotContains(t, out.String(), "Title: ")
+	content, err := os.ReadFile(filepath.Join(tmpDir, "docs/adr", "0001-given-title.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "## Context\n\nContext text\n")
+}
+
+func TestNewCmd_Interactive_EmptyTitleReprompts(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+
+	out := new(bytes.Buffer)
+	root := cli.NewRootCmd()
+	root.SetOut(out)
+	root.SetIn(strings.NewReader("!!!\nReal Title\n\n\n\n"))
+	root.SetArgs([]string{"new", "--interactive"})
+	require.NoError(t, root.Execute())
+
+	assert.Contains(t, out.String(), "empty slug")
+	_, err := os.Stat(filepath.Join(tmpDir, "docs/adr", "0001-real-title.md"))
+	assert.NoError(t, err)
+}
+
+func TestNewCmd_Interactive_NoTitleAtEOF_Errors(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+
+	root := cli.NewRootCmd()
+	root.SetOut(new(bytes.Buffer))
+	root.SetIn(strings.NewReader(""))
+	root.SetArgs([]string{"new", "--interactive"})
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+
+	err := root.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "title")
+}
+
+func TestNewCmd_Interactive_ScopePickerByNumberAndName(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initScopedWorkspace(t, tmpDir, []string{"Backend", "Frontend", "API"})
+
+	out := new(bytes.Buffer)
+	root := cli.NewRootCmd()
+	root.SetOut(out)
+	root.SetIn(strings.NewReader("Unknown\n1, api\n\n\n\n"))
+	root.SetArgs([]string{"new", "-i", "Scoped"})
+	require.NoError(t, root.Execute())
+
+	o := out.String()
+	assert.Contains(t, o, "1) Backend")
+	assert.Contains(t, o, "3) API")
+	assert.Contains(t, o, "unknown scope", "invalid pick should be reported and re-prompted")
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "docs/adr", "0001-scoped.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Scope: Backend, API")
+}
+
+func TestNewCmd_Interactive_SupersedeAndRelate(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	dir := filepath.Join(tmpDir, "docs/adr")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0001-old.md"),
+		[]byte("# 1. Old\n\nDate: 2024-01-01\n\n## Status\n\nAccepted\n\n## Context\n\nOld.\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0002-other.md"),
+		[]byte("# 2. Other\n\nDate: 2024-01-01\n\n## Status\n\nAccepted\n\n## Context\n\nOther.\n"), 0o644))
+
+	out := new(bytes.Buffer)
+	root := cli.NewRootCmd()
+	root.SetOut(out)
+	// Three skipped sections, supersede 1 (after a bad ID), relate 2.
+	root.SetIn(strings.NewReader("\n\n\n9\n1\n2\n"))
+	root.SetArgs([]string{"new", "-i", "New"})
+	require.NoError(t, root.Execute())
+
+	o := out.String()
+	assert.Contains(t, o, "1. Old (Accepted)")
+	assert.Contains(t, o, "try again")
+	assert.Contains(t, o, "Related to ADR-0002")
+
+	newContent, err := os.ReadFile(filepath.Join(dir, "0003-new.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(newContent), "Supersedes [ADR-0001](0001-old.md)")
+	assert.Contains(t, string(newContent), "Relates to [ADR-0002](0002-other.md)")
+
+	old, err := os.ReadFile(filepath.Join(dir, "0001-old.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(old), "Superseded by [ADR-0003](0003-new.md)")
+
+	other, err := os.ReadFile(filepath.Join(dir, "0002-other.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(other), "Relates to [ADR-0003](0003-new.md)")
+}
+
+func TestNewCmd_Interactive_RelateFailsBeforeWriting(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+	dir := filepath.Join(tmpDir, "docs/adr")
+	// No Status section, so no relation can be added to it.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0001-notes.md"), []byte("# 1. Notes\n\nJust notes.\n"), 0o644))
+
+	root := cli.NewRootCmd()
+	root.SetOut(new(bytes.Buffer))
+	// Three skipped sections, no supersede, relate 1.
+	root.SetIn(strings.NewReader("\n\n\n\n1\n"))
+	root.SetArgs([]string{"new", "-i", "New"})
+	err := root.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot relate to ADR 1")
+	assert.NoFileExists(t, filepath.Join(dir, "0002-new.md"))
+}
+
+func TestNewCmd_Interactive_EditDirectiveOpensEditor(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as $EDITOR")
+	}
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+
+	script := filepath.Join(tmpDir, "editor.sh")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\nprintf 'Written in editor\\n' > \"$1\"\n"), 0o755))
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", script)
+
+	root := cli.NewRootCmd()
+	root.SetOut(new(bytes.Buffer))
+	root.SetIn(strings.NewReader("!edit\n\n\n"))
+	root.SetArgs([]string{"new", "-i", "Edited"})
+	require.NoError(t, root.Execute())
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "docs/adr", "0001-edited.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "## Context\n\nWritten in editor\n\n## Decision")
+}
+
+func TestNewCmd_NonInteractive_StillRequiresTitle(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "nygard")
+
+	root := cli.NewRootCmd()
+	root.SetArgs([]string{"new"})
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+
+	assert.Error(t, root.Execute())
+}
+
+func TestNewCmd_Interactive_ProjectTemplateSections(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "docs/adr"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "docs/adr", "rfc.md"),
+		[]byte("# Title\n\nDate:\n\n## Status\n\nProposed\n\n## Problem\n\n{What problem?}\n\n## Proposal\n\n{What do we do?}\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".adr.json"), []byte(`{
+		"version": "1", "directory": "docs/adr", "template": "rfc",
+		"templates": {"rfc": {"file": "rfc.md"}}
+	}`), 0o644))
+
+	input := "Use Go\nBuilds are slow.\n.\nSwitch to Go.\n.\n"
+
+	root := cli.NewRootCmd()
+	root.SetOut(new(bytes.Buffer))
+	root.SetIn(strings.NewReader(input))
+	root.SetArgs([]string{"new", "-i"})
+	require.NoError(t, root.Execute())
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "docs/adr", "0001-use-go.md"))
+	require.NoError(t, err)
+	s := string(content)
+	assert.Contains(t, s, "# 1. Use Go")
+	assert.Contains(t, s, "## Problem\n\nBuilds are slow.\n\n## Proposal")
+	assert.Contains(t, s, "## Proposal\n\nSwitch to Go.\n")
+}
+
+func TestNewCmd_Interactive_FrontmatterFieldsTakeOneLine(t *testing.T) {
+	tmpDir := chdirTemp(t)
+	initWorkspace(t, tmpDir, "docs/adr", "madr-full")
+
+	input := strings.Join([]string{
+		"Use Go",
+		"Alice, Bob", // decision-makers
+		"",           // consulted: keep the placeholder
+		"Everyone",   // informed
+		"Builds are slow.", ".",
+	}, "\n") + "\n"
+
+	out := new(bytes.Buffer)
+	root := cli.NewRootCmd()
+	root.SetOut(out)
+	root.SetIn(strings.NewReader(input))
+	root.SetArgs([]string{"new", "-i"})
+	require.NoError(t, root.Execute())
+
+	assert.Contains(t, out.String(), "Decision Makers (optional)\n  list everyone involved in the decision\nValues (comma-separated, blank to skip): ")
+	content, err := os.ReadFile(filepath.Join(tmpDir, "docs/adr", "0001-use-go.md"))
+	require.NoError(t, err)
+	s := string(content)
+	assert.Contains(t, s, "\ndecision-makers:\n  - Alice\n  - Bob\nconsulted: {")
+	assert.Contains(t, s, "\ninformed: Everyone\n---\n")
+	assert.Contains(t, s, "## Context and Problem Statement\n\nBuilds are slow.\n")
+}
@@ -0,0 +1,337 @@
+package markdown
+
+import (
+	"html"
+	"strings"
+)
+
+func (r *renderer) inline(s string) string {
+	var b strings.Builder
+	r.inlineTo(&b, s)
+	return b.String()
+}
+
+func (r *renderer) inlineTo(b *strings.Builder, s string) {
+	for i := 0; i < len(s); {
+		switch c := s[i]; c {
+		case '\\':
+			if i+1 < len(s) && isASCIIPunct(s[i+1]) {
+				writeEscaped(b, s[i+1])
+				i += 2
+				continue
+			}
+			if i+1 < len(s) && s[i+1] == '\n' {
+				b.WriteString("<br>\n")
+				i += 2
+				continue
+			}
+		case '`':
+			n := runLength(s, i)
+			if end := closingBackticks(s, i+n, n); end >= 0 {
+				code := strings.ReplaceAll(s[i+n:end], "\n", " ")
+				if len(code) >= 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+					code = code[1 : len(code)-1]
+				}
+				b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+				i = end + n
+				continue
+			}
+			b.WriteString(s[i : i+n])
+			i += n
+			continue
+		case '!':
+			if i+1 < len(s) && s[i+1] == '[' {
+				if l, ok := parseLink(s, i+1); ok {
+					r.image(b, l)
+					i = l.end
+					continue
+				}
+			}
+		case '[':
+			if l, ok := parseLink(s, i); ok {
+				r.link(b, l)
+				i = l.end
+				continue
+			}
+		case '<':
+			if strings.HasPrefix(s[i:], "<!--") {
+				end := strings.Index(s[i+4:], "-->")
+				if end < 0 {
+					return
+				}
+				i += 4 + end + 3
+				continue
+			}
+			if m := autolinkPattern.FindStringSubmatch(s[i:]); m != nil {
+				if href, ok := r.url(m[1]); ok {
+					b.WriteString(`<a href="` + html.EscapeString(href) + `">` + html.EscapeString(m[1]) + "</a>")
+				} else {
+					b.WriteString(html.EscapeString(m[1]))
+				}
+				i += len(m[0])
+				continue
+			}
+			if m := emailPattern.FindStringSubmatch(s[i:]); m != nil {
+				b.WriteString(`<a href="mailto:` + html.EscapeString(m[1]) + `">` + html.EscapeString(m[1]) + "</a>")
+				i += len(m[0])
+				continue
+			}
+		case '*', '_', '~':
+			n := runLength(s, i)
+			if end, ok := r.emphasis(b, s, i, n); ok {
+				i = end
+				continue
+			}
+			b.WriteString(s[i : i+n])
+			i += n
+			continue
+		case '&':
+			if m := entityPattern.FindString(s[i:]); m != "" {
+				b.WriteString(m)
+				i += len(m)
+				continue
+			}
+		case ' ':
+			j := i
+			for j < len(s) && s[j] == ' ' {
+				j++
+			}
+			if j < len(s) && s[j] == '\n' && j-i >= 2 {
+				b.WriteString("<br>\n")
+				i = j + 1
+				continue
+			}
+		}
+		writeEscaped(b, s[i])
+		i++
+	}
+}
+
+func writeEscaped(b *strings.Builder, c byte) {
+	switch c {
+	case '&':
+		b.WriteString("&amp;")
+	case '<':
+		b.WriteString("&lt;")
+	case '>':
+		b.WriteString("&gt;")
+	case '"':
+		b.WriteString("&quot;")
+	default:
+		b.WriteByte(c)
+	}
+}
+
+func runLength(s string, i int) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == s[i] {
+		n++
+	}
+	return n
+}
+
+// closingBackticks returns the index of the next run of exactly n backticks
+// at or after from, or -1.
+func closingBackticks(s string, from, n int) int {
+	for j := from; j < len(s); {
+		if s[j] != '`' {
+			j++
+			continue
+		}
+		m := runLength(s, j)
+		if m == n {
+			return j
+		}
+		j += m
+	}
+	return -1
+}
+
+// emphasis renders the *em*, **strong**, ***both*** (or underscore) or
+// ~~strikethrough~~ span opened by the n-character delimiter run at s[i],
+// returning the index after its closing run.
+func (r *renderer) emphasis(b *strings.Builder, s string, i, n int) (int, bool) {
+	c := s[i]
+	if (c == '~' && n != 2) || n > 3 {
+		return 0, false
+	}
+	if i+n >= len(s) || isSpace(s[i+n]) {
+		return 0, false
+	}
+	if c == '_' && i > 0 && isAlnum(s[i-1]) {
+		return 0, false
+	}
+	for j := i + n; j < len(s); {
+		switch {
+		case s[j] == '`':
+			m := runLength(s, j)
+			if end := closingBackticks(s, j+m, m); end >= 0 {
+				j = end + m
+			} else {
+				j += m
+			}
+			continue
+		case s[j] == '\\':
+			j += 2
+			continue
+		case s[j] != c:
+			j++
+			continue
+		}
+		m := runLength(s, j)
+		if m == n && !isSpace(s[j-1]) && (c != '_' || j+m >= len(s) || !isAlnum(s[j+m])) {
+			open, close := "<em>", "</em>"
+			switch {
+			case c == '~':
+				open, close = "<del>", "</del>"
+			case n == 2:
+				open, close = "<strong>", "</strong>"
+			case n == 3:
+				open, close = "<em><strong>", "</strong></em>"
+			}
+			b.WriteString(open)
+			r.inlineTo(b, s[i+n:j])
+			b.WriteString(close)
+			return j + m, true
+		}
+		j += m
+	}
+	return 0, false
+}
+
+// inlineLink is a parsed [text](dest "title") span ending before s[end].
+type inlineLink struct {
+	text, dest, title string
+	end               int
+}
+
+// parseLink parses an inline link starting at the "[" at s[i].
+func parseLink(s string, i int) (inlineLink, bool) {
+	depth, close := 0, -1
+	for j := i; j < len(s) && close < 0; j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			if depth--; depth == 0 {
+				close = j
+			}
+		}
+	}
+	if close < 0 || close+1 >= len(s) || s[close+1] != '(' {
+		return inlineLink{}, false
+	}
+
+	l := inlineLink{text: s[i+1 : close]}
+	k := skipSpaces(s, close+2)
+	if k < len(s) && s[k] == '<' {
+		end := strings.IndexAny(s[k+1:], ">\n")
+		if end < 0 || s[k+1+end] != '>' {
+			return inlineLink{}, false
+		}
+		l.dest = s[k+1 : k+1+end]
+		k += end + 2
+	} else {
+		start, parens := k, 0
+		for ; k < len(s) && !isSpace(s[k]); k++ {
+			if s[k] == '(' {
+				parens++
+			} else if s[k] == ')' {
+				if parens == 0 {
+					break
+				}
+				parens--
+			}
+		}
+		l.dest = s[start:k]
+	}
+
+	k = skipSpaces(s, k)
+	if k < len(s) && (s[k] == '"' || s[k] == '\'' || s[k] == '(') {
+		closer := s[k]
+		if closer == '(' {
+			closer = ')'
+		}
+		end := strings.IndexByte(s[k+1:], closer)
+		if end < 0 {
+			return inlineLink{}, false
+		}
+		l.title = s[k+1 : k+1+end]
+		k = skipSpaces(s, k+end+2)
+	}
+	if k >= len(s) || s[k] != ')' {
+		return inlineLink{}, false
+	}
+	l.end = k + 1
+	return l, true
+}
+
+func (r *renderer) link(b *strings.Builder, l inlineLink) {
+	href, ok := r.url(l.dest)
+	if !ok {
+		r.inlineTo(b, l.text)
+		return
+	}
+	b.WriteString(`<a href="` + html.EscapeString(href) + `"`)
+	if l.title != "" {
+		b.WriteString(` title="` + html.EscapeString(l.title) + `"`)
+	}
+	b.WriteString(">")
+	r.inlineTo(b, l.text)
+	b.WriteString("</a>")
+}
+
+func (r *renderer) image(b *strings.Builder, l inlineLink) {
+	alt := tagPattern.ReplaceAllString(r.inline(l.text), "")
+	src, ok := r.url(l.dest)
+	if !ok {
+		b.WriteString(alt)
+		return
+	}
+	b.WriteString(`<img src="` + html.EscapeString(src) + `" alt="` + alt + `"`)
+	if l.title != "" {
+		b.WriteString(` title="` + html.EscapeString(l.title) + `"`)
+	}
+	b.WriteString(">")
+}
+
+// url checks a link destination: relative URLs and http, https and mailto
+// ones pass, then go through Options.RewriteLink.
+func (r *renderer) url(dest string) (string, bool) {
+	if m := schemePattern.FindStringSubmatch(dest); m != nil {
+		switch strings.ToLower(m[1]) {
+		case "http", "https", "mailto":
+		default:
+			return "", false
+		}
+	}
+	if r.opts.RewriteLink != nil {
+		dest = r.opts.RewriteLink(dest)
+	}
+	return dest, true
+}
+
+func skipSpaces(s string, i int) int {
+	for i < len(s) && isSpace(s[i]) {
+		i++
+	}
+	return i
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isAlnum(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isASCIIPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
@@ -0,0 +1,586 @@
+// Package markdown renders ADR markdown as HTML. It covers the CommonMark
+// blocks and inlines ADRs use (headings, paragraphs, lists, block quotes, code,
+// GitHub-style tables, emphasis, links and images) without pulling in a
+// markdown dependency.
+//
+// The output is safe to embed in a page: raw HTML in the input is escaped
+// rather than passed through, HTML comments (template guidance) are dropped,
+// and link and image URLs are limited to relative ones and the http, https and
+// mailto schemes.
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Options tunes rendering.
+type Options struct {
+	// RewriteLink, when set, maps each link and image destination that passed
+	// the URL check, e.g. to point links to .md files at exported pages.
+	RewriteLink func(dest string) string
+}
+
+var (
+	fencePattern      = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})[ \t]*([^`\\s]*)")
+	atxPattern        = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	hrPattern         = regexp.MustCompile(`^ {0,3}(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	quotePattern      = regexp.MustCompile(`^ {0,3}> ?`)
+	listItemPattern   = regexp.MustCompile(`^( {0,3})([-*+]|\d{1,9}[.)])([ \t]+|$)`)
+	setextPattern     = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	tableDelimPattern = regexp.MustCompile(`^[ \t]*\|?[ \t]*:?-+:?[ \t]*(\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+	schemePattern     = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9+.-]*):`)
+	autolinkPattern   = regexp.MustCompile(`^<([a-zA-Z][a-zA-Z0-9+.-]{1,31}:[^\s<>]*)>`)
+	emailPattern      = regexp.MustCompile(`^<([a-zA-Z0-9.!#$%&'*+/=?^_{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*)>`)
+	entityPattern     = regexp.MustCompile(`^&(?:[a-zA-Z][a-zA-Z0-9]{1,31}|#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6});`)
+	tagPattern        = regexp.MustCompile(`<[^>]*>`)
+)
+
+// ToHTML renders markdown as HTML. A leading byte order mark and YAML
+// frontmatter block are skipped, and CRLF line endings are read as LF.
+func ToHTML(src string, opts Options) string {
+	return render(src, opts).out.String()
+}
+
+// SectionHTML renders the section of src whose heading has the given id or
+// text (compared case-insensitively): the heading and everything up to the
+// next heading of the same or a higher level. Heading ids are those of the
+// whole document, so anchors match ToHTML's. It reports false when no
+// top-level heading (one outside lists and block quotes) matches.
+func SectionHTML(src, section string, opts Options) (string, bool) {
+	r := render(src, opts)
+	out := r.out.String()
+	for i, h := range r.headings {
+		if !strings.EqualFold(h.id, section) && !strings.EqualFold(h.text, strings.TrimSpace(section)) {
+			continue
+		}
+		end := len(out)
+		for _, next := range r.headings[i+1:] {
+			if next.level <= h.level {
+				end = next.start
+				break
+			}
+		}
+		return out[h.start:end], true
+	}
+	return "", false
+}
+
+func render(src string, opts Options) *renderer {
+	src = strings.TrimPrefix(strings.ReplaceAll(src, "\r\n", "\n"), "\ufeff")
+	src = skipFrontmatter(src)
+	lines := strings.Split(src, "\n")
+	for i, line := range lines {
+		lines[i] = expandTabs(line)
+	}
+	r := &renderer{opts: opts, ids: make(map[string]int), headings: []headingMark{}}
+	r.blocks(lines, false)
+	return r
+}
+
+// Heading is a top-level heading of a markdown document (see Headings).
+type Heading struct {
+	Level int
+	// Text is the heading's source text, trimmed, without its "#" markers or
+	// setext underline.
+	Text string
+	// Start and End are the byte offsets of the heading's lines in the
+	// source; End is just past the newline of the last one.
+	Start, End int
+}
+
+// Headings returns the top-level headings of src in order: the ATX and
+// setext headings ToHTML renders outside lists and block quotes. Heading-like
+// lines in code blocks and HTML comments are not headings. Unlike ToHTML,
+// Headings does not skip frontmatter; src should not have any.
+func Headings(src string) []Heading {
+	lines := strings.Split(src, "\n")
+	offsets := make([]int, len(lines)+1)
+	for i, line := range lines {
+		offsets[i+1] = min(offsets[i]+len(line)+1, len(src))
+		lines[i] = expandTabs(strings.TrimSuffix(line, "\r"))
+	}
+	lines[0] = strings.TrimPrefix(lines[0], "\ufeff")
+	r := &renderer{ids: make(map[string]int), headings: []headingMark{}}
+	r.blocks(lines, false)
+
+	headings := make([]Heading, len(r.headings))
+	for i, h := range r.headings {
+		headings[i] = Heading{Level: h.level, Text: h.source, Start: offsets[h.first], End: offsets[h.last+1]}
+	}
+	return headings
+}
+
+// HeadingID returns the anchor id ToHTML gives a heading with the given text
+// (the first one; repeats get "-1", "-2", … appended).
+func HeadingID(text string) string {
+	var b strings.Builder
+	for _, c := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case unicode.IsLetter(c) || unicode.IsDigit(c) || c == '-' || c == '_':
+			b.WriteRune(c)
+		case c == ' ':
+			b.WriteByte('-')
+		}
+	}
+	if b.Len() == 0 {
+		return "section"
+	}
+	return b.String()
+}
+
+func skipFrontmatter(src string) string {
+	if !strings.HasPrefix(src, "---\n") {
+		return src
+	}
+	rest := src[4:]
+	if strings.HasPrefix(rest, "---\n") {
+		return rest[4:]
+	}
+	if end := strings.Index(rest, "\n---\n"); end >= 0 {
+		return rest[end+5:]
+	}
+	if strings.HasSuffix(rest, "\n---") {
+		return ""
+	}
+	return src
+}
+
+// expandTabs replaces tabs in a line's indentation with spaces (tab stops of
+// 4), so block structure can be measured in spaces.
+func expandTabs(line string) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+	var b strings.Builder
+	col := 0
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\t':
+			n := 4 - col%4
+			b.WriteString(strings.Repeat(" ", n))
+			col += n
+		case ' ':
+			b.WriteByte(' ')
+			col++
+		default:
+			b.WriteString(line[i:])
+			return b.String()
+		}
+	}
+	return b.String()
+}
+
+type renderer struct {
+	opts Options
+	out  strings.Builder
+	// ids counts heading ids in use, shared with nested renderers.
+	ids map[string]int
+	// headings records where each heading starts in out. Only the top-level
+	// renderer keeps it (non-nil), since only its headings delimit sections.
+	headings []headingMark
+}
+
+// headingMark is a rendered heading, its offset in the output and the source
+// lines it was read from (first to last, inclusive).
+type headingMark struct {
+	level       int
+	id, text    string
+	source      string
+	start       int
+	first, last int
+}
+
+func (r *renderer) child() *renderer {
+	return &renderer{opts: r.opts, ids: r.ids}
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// interruptsParagraph reports whether line starts a block that ends a
+// paragraph without a blank line in between.
+func interruptsParagraph(line string) bool {
+	if fencePattern.MatchString(line) || atxPattern.MatchString(line) || hrPattern.MatchString(line) ||
+		quotePattern.MatchString(line) || strings.HasPrefix(strings.TrimLeft(line, " "), "<!--") {
+		return true
+	}
+	m := listItemPattern.FindStringSubmatch(line)
+	if m == nil || strings.TrimSpace(line[len(m[0]):]) == "" {
+		return false
+	}
+	// Only ordered lists starting at 1 may interrupt a paragraph.
+	return !isDigit(m[2][0]) || strings.TrimLeft(m[2][:len(m[2])-1], "0") == "1"
+}
+
+// blocks renders lines as block content. In a tight list item, paragraphs are
+// written without <p> tags.
+func (r *renderer) blocks(lines []string, tight bool) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case isBlank(line):
+			i++
+		case fencePattern.MatchString(line):
+			i = r.fence(lines, i)
+		case strings.HasPrefix(strings.TrimLeft(line, " "), "<!--") && indentOf(line) < 4:
+			i = skipComment(lines, i)
+		case atxPattern.MatchString(line):
+			m := atxPattern.FindStringSubmatch(line)
+			r.heading(len(m[1]), m[2], i, i)
+			i++
+		case hrPattern.MatchString(line):
+			r.out.WriteString("<hr>\n")
+			i++
+		case quotePattern.MatchString(line):
+			i = r.blockquote(lines, i)
+		case listItemPattern.MatchString(line):
+			i = r.list(lines, i)
+		case indentOf(line) >= 4:
+			i = r.indentedCode(lines, i)
+		case isTableStart(lines, i):
+			i = r.table(lines, i)
+		default:
+			i = r.paragraph(lines, i, tight)
+		}
+	}
+}
+
+func (r *renderer) fence(lines []string, i int) int {
+	m := fencePattern.FindStringSubmatch(lines[i])
+	indent, fence, info := len(m[1]), m[2], m[3]
+	var code []string
+	for i++; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if indentOf(line) < 4 && len(trimmed) >= len(fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			i++
+			break
+		}
+		if n := min(indentOf(line), indent); n > 0 {
+			line = line[n:]
+		}
+		code = append(code, line)
+	}
+
+	r.out.WriteString("<pre><code")
+	if info != "" {
+		fmt.Fprintf(&r.out, ` class="language-%s"`, html.EscapeString(info))
+	}
+	r.out.WriteString(">")
+	if len(code) > 0 {
+		r.out.WriteString(html.EscapeString(strings.Join(code, "\n") + "\n"))
+	}
+	r.out.WriteString("</code></pre>\n")
+	return i
+}
+
+func skipComment(lines []string, i int) int {
+	for ; i < len(lines); i++ {
+		if strings.Contains(lines[i], "-->") {
+			return i + 1
+		}
+	}
+	return i
+}
+
+func (r *renderer) indentedCode(lines []string, i int) int {
+	var code []string
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if isBlank(line) {
+			code = append(code, "")
+			continue
+		}
+		if indentOf(line) < 4 {
+			break
+		}
+		code = append(code, line[4:])
+	}
+	for len(code) > 0 && code[len(code)-1] == "" {
+		code = code[:len(code)-1]
+	}
+	r.out.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")+"\n") + "</code></pre>\n")
+	return i
+}
+
+func (r *renderer) heading(level int, text string, first, last int) {
+	inner := r.inline(strings.TrimSpace(text))
+	plain := html.UnescapeString(tagPattern.ReplaceAllString(inner, ""))
+	id := HeadingID(plain)
+	if n := r.ids[id]; n > 0 {
+		r.ids[id] = n + 1
+		id = fmt.Sprintf("%s-%d", id, n)
+	} else {
+		r.ids[id] = 1
+	}
+	if r.headings != nil {
+		r.headings = append(r.headings, headingMark{
+			level: level, id: id, text: strings.TrimSpace(plain), source: strings.TrimSpace(text),
+			start: r.out.Len(), first: first, last: last,
+		})
+	}
+	fmt.Fprintf(&r.out, "<h%d id=\"%s\">%s</h%d>\n", level, html.EscapeString(id), inner, level)
+}
+
+func (r *renderer) paragraph(lines []string, i int, tight bool) int {
+	first := i
+	var para []string
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if isBlank(line) {
+			break
+		}
+		if len(para) > 0 {
+			if m := setextPattern.FindStringSubmatch(line); m != nil {
+				level := 1
+				if m[1][0] == '-' {
+					level = 2
+				}
+				r.heading(level, strings.Join(para, "\n"), first, i)
+				return i + 1
+			}
+			if interruptsParagraph(line) {
+				break
+			}
+		}
+		para = append(para, strings.TrimLeft(line, " "))
+	}
+
+	text := r.inline(strings.TrimRight(strings.Join(para, "\n"), " "))
+	if tight {
+		r.out.WriteString(text + "\n")
+	} else {
+		r.out.WriteString("<p>" + text + "</p>\n")
+	}
+	return i
+}
+
+func (r *renderer) blockquote(lines []string, i int) int {
+	var inner []string
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if loc := quotePattern.FindStringIndex(line); loc != nil {
+			inner = append(inner, line[loc[1]:])
+			continue
+		}
+		// Lazy continuation of a quoted paragraph.
+		if isBlank(line) || interruptsParagraph(line) || isBlank(inner[len(inner)-1]) {
+			break
+		}
+		inner = append(inner, line)
+	}
+	c := r.child()
+	c.blocks(inner, false)
+	r.out.WriteString("<blockquote>\n" + c.out.String() + "</blockquote>\n")
+	return i
+}
+
+// listMarker describes a list item's marker: the bullet character or the
+// ordered delimiter, which must match for items to share a list.
+type listMarker struct {
+	ordered bool
+	char    byte
+	start   int
+}
+
+func parseListMarker(marker string) listMarker {
+	last := marker[len(marker)-1]
+	if !isDigit(marker[0]) {
+		return listMarker{char: last}
+	}
+	start, _ := strconv.Atoi(marker[:len(marker)-1])
+	return listMarker{ordered: true, char: last, start: start}
+}
+
+func (r *renderer) list(lines []string, i int) int {
+	first := parseListMarker(listItemPattern.FindStringSubmatch(lines[i])[2])
+	var items [][]string
+	loose := false
+	for i < len(lines) {
+		m := listItemPattern.FindStringSubmatch(lines[i])
+		if m == nil {
+			break
+		}
+		if mk := parseListMarker(m[2]); mk.ordered != first.ordered || mk.char != first.char {
+			break
+		}
+		contentIndent := len(m[0])
+		if len(m[3]) > 4 || len(m[3]) == 0 {
+			// Code after the marker, or an empty first line.
+			contentIndent = len(m[1]) + len(m[2]) + 1
+		}
+		item := []string{strings.TrimLeft(lines[i][min(contentIndent, len(lines[i])):], " ")}
+		if len(m[3]) > 4 {
+			item[0] = lines[i][contentIndent:]
+		}
+
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			if isBlank(line) {
+				j := i
+				for j < len(lines) && isBlank(lines[j]) {
+					j++
+				}
+				if j == len(lines) || indentOf(lines[j]) < contentIndent {
+					break
+				}
+				loose = true
+				for ; i < j-1; i++ {
+					item = append(item, "")
+				}
+				item = append(item, "")
+				continue
+			}
+			if indentOf(line) >= contentIndent {
+				item = append(item, line[contentIndent:])
+				continue
+			}
+			if listItemPattern.MatchString(line) || interruptsParagraph(line) || isBlank(item[len(item)-1]) {
+				break
+			}
+			item = append(item, strings.TrimLeft(line, " "))
+		}
+		items = append(items, item)
+
+		// A blank line before the next item makes the list loose.
+		j := i
+		for j < len(lines) && isBlank(lines[j]) {
+			j++
+		}
+		if j > i {
+			m := listItemPattern.FindStringSubmatch(safeLine(lines, j))
+			if m == nil {
+				break
+			}
+			if mk := parseListMarker(m[2]); mk.ordered != first.ordered || mk.char != first.char {
+				break
+			}
+			loose = true
+			i = j
+		}
+	}
+
+	tag := "ul"
+	if first.ordered {
+		tag = "ol"
+	}
+	if first.ordered && first.start != 1 {
+		fmt.Fprintf(&r.out, "<ol start=\"%d\">\n", first.start)
+	} else {
+		r.out.WriteString("<" + tag + ">\n")
+	}
+	for _, item := range items {
+		c := r.child()
+		c.blocks(item, !loose)
+		body := c.out.String()
+		if !loose {
+			body = strings.TrimSuffix(body, "\n")
+			if strings.HasSuffix(body, ">") && strings.Contains(body, "\n") {
+				body += "\n"
+			}
+		} else {
+			body = "\n" + body
+		}
+		r.out.WriteString("<li>" + body + "</li>\n")
+	}
+	r.out.WriteString("</" + tag + ">\n")
+	return i
+}
+
+func safeLine(lines []string, i int) string {
+	if i < len(lines) {
+		return lines[i]
+	}
+	return ""
+}
+
+func isTableStart(lines []string, i int) bool {
+	if i+1 >= len(lines) || !strings.Contains(lines[i], "|") || !tableDelimPattern.MatchString(lines[i+1]) {
+		return false
+	}
+	return len(splitRow(lines[i])) == len(splitRow(lines[i+1]))
+}
+
+// splitRow splits a table row into trimmed cells on unescaped pipes.
+func splitRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+func (r *renderer) table(lines []string, i int) int {
+	header := splitRow(lines[i])
+	var align []string
+	for _, d := range splitRow(lines[i+1]) {
+		switch {
+		case strings.HasPrefix(d, ":") && strings.HasSuffix(d, ":"):
+			align = append(align, "center")
+		case strings.HasSuffix(d, ":"):
+			align = append(align, "right")
+		case strings.HasPrefix(d, ":"):
+			align = append(align, "left")
+		default:
+			align = append(align, "")
+		}
+	}
+
+	row := func(tag string, cells []string) {
+		r.out.WriteString("<tr>\n")
+		for c := range header {
+			text := ""
+			if c < len(cells) {
+				text = cells[c]
+			}
+			r.out.WriteString("<" + tag)
+			if align[c] != "" {
+				fmt.Fprintf(&r.out, ` style="text-align: %s"`, align[c])
+			}
+			r.out.WriteString(">" + r.inline(text) + "</" + tag + ">\n")
+		}
+		r.out.WriteString("</tr>\n")
+	}
+
+	r.out.WriteString("<table>\n<thead>\n")
+	row("th", header)
+	r.out.WriteString("</thead>\n")
+	i += 2
+	if i < len(lines) && !isBlank(lines[i]) && strings.Contains(lines[i], "|") {
+		r.out.WriteString("<tbody>\n")
+		for ; i < len(lines); i++ {
+			if isBlank(lines[i]) || interruptsParagraph(lines[i]) {
+				break
+			}
+			row("td", splitRow(lines[i]))
+		}
+		r.out.WriteString("</tbody>\n")
+	}
+	r.out.WriteString("</table>\n")
+	return i
+}
@@ -0,0 +1,151 @@
+package markdown_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/BobMali/adr-helper/internal/markdown"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToHTML_Blocks(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"heading", "# 1. Use *Go*", "<h1 id=\"1-use-go\">1. Use <em>Go</em></h1>\n"},
+		{"duplicate heading ids", "## Notes\n\n## Notes", "<h2 id=\"notes\">Notes</h2>\n<h2 id=\"notes-1\">Notes</h2>\n"},
+		{"setext heading", "Title\n=====", "<h1 id=\"title\">Title</h1>\n"},
+		{"byte order mark and CRLF", "\ufeff---\r\nstatus: x\r\n---\r\n# Title\r\n", "<h1 id=\"title\">Title</h1>\n"},
+		{"paragraph with hard break", "Superseded by x  \nnext", "<p>Superseded by x<br>\nnext</p>\n"},
+		{"thematic break", "a\n\n***", "<p>a</p>\n<hr>\n"},
+		{"fenced code", "```go\nx := \"<a>\"\n```", "<pre><code class=\"language-go\">x := &#34;&lt;a&gt;&#34;\n</code></pre>\n"},
+		{"indented code", "    code\n\ntext", "<pre><code>code\n</code></pre>\n<p>text</p>\n"},
+		{"block quote", "> quoted\nlazy", "<blockquote>\n<p>quoted\nlazy</p>\n</blockquote>\n"},
+		{"tight list", "- a\n- b\n  - c", "<ul>\n<li>a</li>\n<li>b\n<ul>\n<li>c</li>\n</ul>\n</li>\n</ul>\n"},
+		{"loose list", "- a\n\n- b", "<ul>\n<li>\n<p>a</p>\n</li>\n<li>\n<p>b</p>\n</li>\n</ul>\n"},
+		{"ordered list start", "3. c\n4. d", "<ol start=\"3\">\n<li>c</li>\n<li>d</li>\n</ol>\n"},
+		{"list interrupts paragraph", "Options:\n- a", "<p>Options:</p>\n<ul>\n<li>a</li>\n</ul>\n"},
+		{"table", "| A | B |\n|:-|-:|\n| 1 | 2 \\| 3 |", "<table>\n<thead>\n<tr>\n<th style=\"text-align: left\">A</th>\n<th style=\"text-align: right\">B</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td style=\"text-align: left\">1</td>\n<td style=\"text-align: right\">2 | 3</td>\n</tr>\n</tbody>\n</table>\n"},
+		{"frontmatter skipped", "---\nstatus: accepted\n---\n\n# T", "<h1 id=\"t\">T</h1>\n"},
+		{"comment block dropped", "<!-- guidance\nspanning lines -->\ntext", "<p>text</p>\n"},
+		{"crlf", "a\r\nb\r\n", "<p>a\nb</p>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, markdown.ToHTML(tt.src, markdown.Options{}))
+		})
+	}
+}
+
+func TestToHTML_Inlines(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"strong and em", "**a *b* c**", "<strong>a <em>b</em> c</strong>"},
+		{"underscore inside word", "snake_case_name", "snake_case_name"},
+		{"strikethrough", "~~old~~", "<del>old</del>"},
+		{"code span", "`a <b>` and `` x`y ``", "<code>a &lt;b&gt;</code> and <code>x`y</code>"},
+		{"link with title", `[ADR-0002](0002-x.md "Next")`, `<a href="0002-x.md" title="Next">ADR-0002</a>`},
+		{"image", "![diagram](img/a.png)", `<img src="img/a.png" alt="diagram">`},
+		{"autolink", "<https://example.com>", `<a href="https://example.com">https://example.com</a>`},
+		{"email autolink", "<dev@example.com>", `<a href="mailto:dev@example.com">dev@example.com</a>`},
+		{"escape", `\*not em\*`, "*not em*"},
+		{"entity kept", "a &amp; b & c", "a &amp; b &amp; c"},
+		{"inline comment dropped", "a <!-- hidden --> b", "a  b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, "<p>"+tt.want+"</p>\n", markdown.ToHTML(tt.src, markdown.Options{}))
+		})
+	}
+}
+
+func TestToHTML_Sanitizes(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"script tag", "<script>alert(1)</script>"},
+		{"html block", "<div onclick=\"alert(1)\">x</div>"},
+		{"javascript link", "[x](javascript:alert(1))"},
+		{"javascript autolink", "<javascript:alert(1)>"},
+		{"data image", "![x](data:text/html;base64,PHNjcmlwdD4=)"},
+		{"mixed case scheme", "[x](JaVaScRiPt:alert(1))"},
+		{"attribute breakout", `[x](a"onmouseover="alert(1))`},
+		{"heading", "# <img src=x onerror=alert(1)>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.ToLower(markdown.ToHTML(tt.src, markdown.Options{}))
+			assert.NotContains(t, got, "<script")
+			assert.NotContains(t, got, "<div")
+			assert.NotContains(t, got, `href="javascript:`)
+			assert.NotContains(t, got, `src="data:`)
+			assert.NotContains(t, got, `"onmouseover`)
+			assert.NotContains(t, got, "<img src=x")
+		})
+	}
+}
+
+func TestToHTML_RewriteLink(t *testing.T) {
+	opts := markdown.Options{RewriteLink: func(dest string) string {
+		return strings.Replace(dest, ".md", ".html", 1)
+	}}
+	got := markdown.ToHTML("[ADR-0002](0002-x.md#status) [bad](javascript:x)", opts)
+	assert.Equal(t, "<p><a href=\"0002-x.html#status\">ADR-0002</a> bad</p>\n", got)
+}
+
+func TestSectionHTML(t *testing.T) {
+	src := "# 1. Use Go\n\n## Context\n\nWhy.\n\n### Forces\n\nSpeed.\n\n## Decision\n\n> ## Quoted\n\nGo.\n\n## Context\n\nAgain.\n"
+
+	got, ok := markdown.SectionHTML(src, "context", markdown.Options{})
+	require.True(t, ok)
+	assert.Equal(t, "<h2 id=\"context\">Context</h2>\n<p>Why.</p>\n<h3 id=\"forces\">Forces</h3>\n<p>Speed.</p>\n", got)
+
+	got, ok = markdown.SectionHTML(src, "Decision", markdown.Options{})
+	require.True(t, ok)
+	assert.Equal(t, "<h2 id=\"decision\">Decision</h2>\n<blockquote>\n<h2 id=\"quoted\">Quoted</h2>\n</blockquote>\n<p>Go.</p>\n", got)
+
+	// Repeated headings keep the ids they have in the whole document.
+	got, ok = markdown.SectionHTML(src, "context-1", markdown.Options{})
+	require.True(t, ok)
+	assert.Equal(t, "<h2 id=\"context-1\">Context</h2>\n<p>Again.</p>\n", got)
+
+	_, ok = markdown.SectionHTML(src, "quoted", markdown.Options{})
+	assert.False(t, ok)
+	_, ok = markdown.SectionHTML(src, "consequences", markdown.Options{})
+	assert.False(t, ok)
+}
+
+func TestHeadings(t *testing.T) {
+	src := "# 1. Use `Go`\r\n\n" +
+		"```md\n## Status (in a fence)\n```\n\n" +
+		"<!--\n## Status (in a comment)\n-->\n\n" +
+		"    ## indented code\n\n" +
+		"- ## in a list\n\n" +
+		"Status\n------\n\n" +
+		"Accepted\n\n" +
+		"### Notes ###\n" +
+		"#not-a-heading\n\n" +
+		"## Last"
+
+	headings := markdown.Headings(src)
+	require.Len(t, headings, 4)
+	assert.Equal(t, markdown.Heading{Level: 1, Text: "1. Use `Go`", Start: 0, End: 15}, headings[0])
+	assert.Equal(t, markdown.Heading{Level: 2, Text: "Status", Start: strings.Index(src, "Status\n"), End: strings.Index(src, "\nAccepted")}, headings[1])
+	assert.Equal(t, "Notes", headings[2].Text)
+	assert.Equal(t, 3, headings[2].Level)
+	assert.Equal(t, markdown.Heading{Level: 2, Text: "Last", Start: len(src) - 7, End: len(src)}, headings[3])
+}
+
+func TestHeadingID(t *testing.T) {
+	assert.Equal(t, "context-and-problem-statement", markdown.HeadingID("Context and Problem Statement"))
+	assert.Equal(t, "1-use-go", markdown.HeadingID("1. Use Go"))
+	assert.Equal(t, "entscheidung-über-öl", markdown.HeadingID("Entscheidung über Öl"))
+	assert.Equal(t, "section", markdown.HeadingID("!!!"))
+}
@@ -0,0 +1,39 @@
+// Filters the index table by the search box and status select, using the
+// index from search-index.js. ?q= pre-fills the search.
+(function () {
+  var input = document.getElementById("search");
+  if (!input) {
+    return;
+  }
+  var status = document.getElementById("status-filter");
+  var count = document.getElementById("search-count");
+  var rows = document.querySelectorAll("tr[data-number]");
+  var text = {};
+  (window.ADR_SEARCH_INDEX || []).forEach(function (e) {
+    text[e.number] = [e.number, e.title, e.status, e.date, e.scopes.join(" "), e.text].join(" ").toLowerCase();
+  });
+
+  function apply() {
+    var terms = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    var wanted = status.value;
+    var shown = 0;
+    rows.forEach(function (row) {
+      var haystack = text[row.getAttribute("data-number")] || "";
+      var match = terms.every(function (t) { return haystack.indexOf(t) >= 0; }) &&
+        (!wanted || row.getAttribute("data-status") === wanted);
+      row.hidden = !match;
+      if (match) {
+        shown++;
+      }
+    });
+    count.textContent = shown + " of " + rows.length;
+  }
+
+  var q = new URLSearchParams(window.location.search).get("q");
+  if (q) {
+    input.value = q;
+  }
+  input.addEventListener("input", apply);
+  status.addEventListener("change", apply);
+  apply();
+})();
@@ -0,0 +1,70 @@
+package site
+
+import "math"
+
+const (
+	nodeRadius  = 18.0
+	nodeSpacing = 64.0
+	graphMargin = 48.0
+)
+
+// graph is the laid-out supersede/relation graph: ADRs on a circle in index
+// order, with an arrow from each superseding ADR to the one it supersedes and
+// a dashed line between related ones.
+type graph struct {
+	Width, Height int
+	Nodes         []graphNode
+	Edges         []graphEdge
+}
+
+type graphNode struct {
+	X, Y float64
+	Page *page
+}
+
+type graphEdge struct {
+	Kind           string
+	X1, Y1, X2, Y2 float64
+}
+
+func layoutGraph(pages []*page) *graph {
+	n := len(pages)
+	radius := math.Max(nodeSpacing*float64(n)/(2*math.Pi), 3*nodeSpacing)
+	if n == 1 {
+		radius = 0
+	}
+	center := radius + graphMargin
+	size := int(math.Ceil(2 * center))
+	g := &graph{Width: size, Height: size}
+
+	pos := make(map[*page]graphNode, n)
+	for i, p := range pages {
+		angle := 2*math.Pi*float64(i)/float64(n) - math.Pi/2
+		node := graphNode{X: center + radius*math.Cos(angle), Y: center + radius*math.Sin(angle), Page: p}
+		pos[p] = node
+		g.Nodes = append(g.Nodes, node)
+	}
+
+	edge := func(kind string, from, to *page) {
+		a, b := pos[from], pos[to]
+		dx, dy := b.X-a.X, b.Y-a.Y
+		length := math.Hypot(dx, dy)
+		if length <= 2*nodeRadius {
+			return
+		}
+		// Start and end at the node circles, so arrowheads stay visible.
+		ux, uy := dx/length*nodeRadius, dy/length*nodeRadius
+		g.Edges = append(g.Edges, graphEdge{Kind: kind, X1: a.X + ux, Y1: a.Y + uy, X2: b.X - ux, Y2: b.Y - uy})
+	}
+	for _, p := range pages {
+		for _, q := range p.supersedes {
+			edge("supersedes", p, q)
+		}
+		for _, q := range p.relatesTo {
+			if p.Number < q.Number {
+				edge("relates", p, q)
+			}
+		}
+	}
+	return g
+}
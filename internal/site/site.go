@@ -0,0 +1,420 @@
+// Package site generates a static HTML site from a directory of ADRs, for
+// readers without repository access. The site is self-contained: pages link to
+// each other relatively and search runs in the browser, so it can be opened
+// from disk or served by any static host.
+package site
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html"
+	"html/template"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/BobMali/adr-helper/internal/markdown"
+)
+
+//go:embed templates/*.html assets/*
+var files embed.FS
+
+// DefaultTitle heads the site when Options.Title is empty.
+const DefaultTitle = "Architecture Decision Records"
+
+// Options configures Export.
+type Options struct {
+	Title string
+	// Query, Scopes and MatchAllScopes select the ADRs to export, as for
+	// `adr list --search/--scope/--scope-match`. The sidebar and graph leave
+	// out links to ADRs that are not exported.
+	Query          string
+	Scopes         []string
+	MatchAllScopes bool
+	// SortField and SortDesc order the index and scope pages (see
+	// adr.SortADRs); the field defaults to "number".
+	SortField string
+	SortDesc  bool
+}
+
+// page is one exported ADR.
+type page struct {
+	adr.ADR
+	// Source is the markdown filename, File the page's.
+	Source string
+	File   string
+	Body   template.HTML
+	Scopes []*facet
+	Nav    []navGroup
+	// Prev and Next follow the index order.
+	Prev, Next *page
+
+	supersedes, supersededBy, relatesTo []*page
+}
+
+// DateString returns the ADR's date as YYYY-MM-DD, or "" when unknown.
+func (p *page) DateString() string {
+	if p.Date.IsZero() {
+		return ""
+	}
+	return p.Date.Format("2006-01-02")
+}
+
+// navGroup is a sidebar list of linked ADRs.
+type navGroup struct {
+	Label string
+	Pages []*page
+}
+
+// facet is a scope value with its page.
+type facet struct {
+	Name  string
+	File  string
+	Pages []*page
+}
+
+type statusCount struct {
+	Status adr.Status
+	Count  int
+}
+
+type siteData struct {
+	Title  string
+	Pages  []*page
+	Scopes []*facet
+	Counts []statusCount
+}
+
+// view is the data every template is executed with.
+type view struct {
+	Site    *siteData
+	Heading string
+	Page    *page
+	Pages   []*page
+	Scope   *facet
+	Graph   *graph
+}
+
+// searchEntry is one record of the client-side search index.
+type searchEntry struct {
+	Number int      `json:"number"`
+	Title  string   `json:"title"`
+	Status string   `json:"status"`
+	Date   string   `json:"date"`
+	Scopes []string `json:"scopes"`
+	URL    string   `json:"url"`
+	Text   string   `json:"text"`
+}
+
+var (
+	tagPattern        = regexp.MustCompile(`<[^>]*>`)
+	whitespacePattern = regexp.MustCompile(`\s+`)
+)
+
+var funcs = template.FuncMap{
+	"statusClass": func(s adr.Status) string { return strings.ToLower(s.String()) },
+}
+
+// Export renders the ADRs of repo as a static site in outDir: an index page
+// with status badges, scope facets and search, a page per ADR with a sidebar
+// linking the ADRs it supersedes, is superseded by and relates to, a page per
+// scope, and a graph of those links. outDir is created if needed; existing
+// files in it are overwritten, and *.html files this export did not write
+// (pages of ADRs or scopes since removed) are deleted. It returns the number
+// of ADR pages written.
+// Pages are written flat in outDir, so ADRs in subdirectories of the ADR
+// directory get their path in the page name: "platform-0003-x.html".
+func Export(ctx context.Context, repo *adr.FileRepository, outDir string, opts Options) (int, error) {
+	if opts.Title == "" {
+		opts.Title = DefaultTitle
+	}
+	if opts.SortField == "" {
+		opts.SortField = "number"
+	}
+
+	records, err := repo.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	records = adr.FilterByQuery(records, opts.Query)
+	records = adr.FilterByMetaField(records, "scope", opts.Scopes, opts.MatchAllScopes)
+	if err := adr.SortADRs(records, opts.SortField, opts.SortDesc); err != nil {
+		return 0, err
+	}
+	names, err := repo.Filenames()
+	if err != nil {
+		return 0, err
+	}
+
+	site := &siteData{Title: opts.Title}
+	byNumber := make(map[int]*page, len(records))
+	for _, r := range records {
+		p := &page{ADR: r, Source: names[r.Number]}
+		p.File = strings.ReplaceAll(strings.TrimSuffix(p.Source, ".md"), "/", "-") + ".html"
+		full, err := repo.Get(ctx, r.Number)
+		if err != nil {
+			return 0, fmt.Errorf("reading ADR: %w", err)
+		}
+		p.Content = full.Content
+		site.Pages = append(site.Pages, p)
+		byNumber[r.Number] = p
+	}
+	for i, p := range site.Pages {
+		if i > 0 {
+			p.Prev = site.Pages[i-1]
+		}
+		if i+1 < len(site.Pages) {
+			p.Next = site.Pages[i+1]
+		}
+		p.Body = template.HTML(markdown.ToHTML(p.Content, markdown.Options{RewriteLink: pageLink(p.Source, byNumber, repo)}))
+	}
+	linkPages(site.Pages, byNumber, repo)
+	site.Scopes = scopeFacets(records, site.Pages, byNumber)
+	counts := adr.CountByStatus(records)
+	for _, s := range adr.AllStatuses() {
+		site.Counts = append(site.Counts, statusCount{Status: s, Count: counts.ByStatus[s]})
+	}
+
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return 0, fmt.Errorf("creating directory %q: %w", outDir, err)
+	}
+	templates, err := parseTemplates()
+	if err != nil {
+		return 0, err
+	}
+	out := map[string][]byte{}
+	render := func(file, name string, v view) error {
+		v.Site = site
+		var buf bytes.Buffer
+		if err := templates[name].ExecuteTemplate(&buf, "layout", v); err != nil {
+			return fmt.Errorf("rendering %s: %w", file, err)
+		}
+		out[file] = buf.Bytes()
+		return nil
+	}
+	if err := render("index.html", "index.html", view{Pages: site.Pages}); err != nil {
+		return 0, err
+	}
+	for _, p := range site.Pages {
+		heading := fmt.Sprintf("%d. %s", p.Number, p.Title)
+		if err := render(p.File, "adr.html", view{Heading: heading, Page: p}); err != nil {
+			return 0, err
+		}
+	}
+	for _, f := range site.Scopes {
+		if err := render(f.File, "scope.html", view{Heading: "Scope: " + f.Name, Scope: f}); err != nil {
+			return 0, err
+		}
+	}
+	if err := render("graph.html", "graph.html", view{Heading: "Graph", Graph: layoutGraph(site.Pages)}); err != nil {
+		return 0, err
+	}
+	index, err := searchIndex(site.Pages)
+	if err != nil {
+		return 0, err
+	}
+	out["search-index.js"] = index
+	for _, asset := range []string{"style.css", "search.js"} {
+		data, err := files.ReadFile("assets/" + asset)
+		if err != nil {
+			return 0, err
+		}
+		out[asset] = data
+	}
+
+	for name, data := range out {
+		if err := os.WriteFile(filepath.Join(outDir, name), data, 0o644); err != nil {
+			return 0, fmt.Errorf("writing %s: %w", name, err)
+		}
+	}
+	if err := removeStalePages(outDir, out); err != nil {
+		return 0, err
+	}
+	return len(site.Pages), nil
+}
+
+// removeStalePages deletes the *.html files in outDir that are not in
+// written, so pages of renamed or deleted ADRs do not linger.
+func removeStalePages(outDir string, written map[string][]byte) error {
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		return fmt.Errorf("reading directory %q: %w", outDir, err)
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if _, ok := written[name]; ok || e.IsDir() || filepath.Ext(name) != ".html" {
+			continue
+		}
+		if err := os.Remove(filepath.Join(outDir, name)); err != nil {
+			return fmt.Errorf("removing stale page %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// parseTemplates parses each page template together with the shared layout.
+func parseTemplates() (map[string]*template.Template, error) {
+	templates := make(map[string]*template.Template)
+	for _, name := range []string{"index.html", "adr.html", "scope.html", "graph.html"} {
+		tmpl, err := template.New(name).Funcs(funcs).ParseFS(files, "templates/layout.html")
+		if err == nil {
+			tmpl, err = tmpl.ParseFS(files, "templates/"+name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing site template %s: %w", name, err)
+		}
+		templates[name] = tmpl
+	}
+	return templates, nil
+}
+
+// pageLink points links to exported ADR files ("0002-x.md#status") in the
+// ADR stored in source at their pages, by path or, for a stale slug, the
+// number in the filename.
+func pageLink(source string, byNumber map[int]*page, repo *adr.FileRepository) func(string) string {
+	bySource := make(map[string]*page, len(byNumber))
+	for _, p := range byNumber {
+		bySource[p.Source] = p
+	}
+	return func(dest string) string {
+		name, fragment, _ := strings.Cut(dest, "#")
+		if name == "" || strings.Contains(name, ":") || strings.HasPrefix(name, "/") {
+			return dest
+		}
+		name = path.Join(path.Dir(source), name)
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
+			// Another root's or project's ADR, whose number isn't ours.
+			return dest
+		}
+		p, ok := bySource[name]
+		if !ok {
+			n, _ := repo.FileNumber(name)
+			p = byNumber[n]
+		}
+		if p == nil {
+			return dest
+		}
+		if fragment != "" {
+			return p.File + "#" + fragment
+		}
+		return p.File
+	}
+}
+
+// linkPages fills each page's sidebar from the supersede and relation links
+// between ADRs of repo, in both directions, so a link written on one side
+// shows on both.
+func linkPages(pages []*page, byNumber map[int]*page, repo *adr.FileRepository) {
+	add := func(list *[]*page, p *page) {
+		for _, q := range *list {
+			if q == p {
+				return
+			}
+		}
+		*list = append(*list, p)
+	}
+	for _, p := range pages {
+		for _, rel := range repo.Relations(p.Source, p.Content) {
+			q := byNumber[rel.Number]
+			if q == nil || q == p {
+				continue
+			}
+			switch rel.Kind {
+			case adr.RelationSupersedes:
+				add(&p.supersedes, q)
+				add(&q.supersededBy, p)
+			case adr.RelationSupersededBy:
+				add(&p.supersededBy, q)
+				add(&q.supersedes, p)
+			case adr.RelationRelatesTo:
+				add(&p.relatesTo, q)
+				add(&q.relatesTo, p)
+			}
+		}
+	}
+	for _, p := range pages {
+		for _, g := range []navGroup{
+			{"Superseded by", p.supersededBy},
+			{"Supersedes", p.supersedes},
+			{"Relates to", p.relatesTo},
+		} {
+			if len(g.Pages) > 0 {
+				sort.Slice(g.Pages, func(i, j int) bool { return g.Pages[i].Number < g.Pages[j].Number })
+				p.Nav = append(p.Nav, g)
+			}
+		}
+	}
+}
+
+// scopeFacets returns a facet per scope value (compared case-insensitively,
+// sorted by name) listing the pages that carry it, in page order.
+func scopeFacets(records []adr.ADR, pages []*page, byNumber map[int]*page) []*facet {
+	names := adr.MetaValues(records, "scope")
+
+	files := make(map[string]bool)
+	facets := make([]*facet, 0, len(names))
+	byName := make(map[string]*facet, len(names))
+	for i, name := range names {
+		slug, err := adr.Slugify(name)
+		if err != nil {
+			slug = strconv.Itoa(i + 1)
+		}
+		file := "scope-" + slug + ".html"
+		for n := 2; files[file]; n++ {
+			file = fmt.Sprintf("scope-%s-%d.html", slug, n)
+		}
+		files[file] = true
+
+		f := &facet{Name: name, File: file}
+		for _, r := range adr.FilterByMetaField(records, "scope", []string{name}, false) {
+			f.Pages = append(f.Pages, byNumber[r.Number])
+		}
+		facets = append(facets, f)
+		byName[strings.ToLower(name)] = f
+	}
+	for _, p := range pages {
+		for _, v := range p.Meta["scope"] {
+			p.Scopes = append(p.Scopes, byName[strings.ToLower(v)])
+		}
+	}
+	return facets
+}
+
+// searchIndex returns search-index.js, which sets window.ADR_SEARCH_INDEX. A
+// script rather than JSON, so the index loads from file:// URLs too.
+func searchIndex(pages []*page) ([]byte, error) {
+	entries := make([]searchEntry, 0, len(pages))
+	for _, p := range pages {
+		e := searchEntry{
+			Number: p.Number,
+			Title:  p.Title,
+			Status: strings.ToLower(p.Status.String()),
+			Date:   p.DateString(),
+			Scopes: []string{},
+			URL:    p.File,
+			Text:   plainText(string(p.Body)),
+		}
+		for _, f := range p.Scopes {
+			e.Scopes = append(e.Scopes, f.Name)
+		}
+		entries = append(entries, e)
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return nil, err
+	}
+	return []byte("window.ADR_SEARCH_INDEX = " + string(data) + ";\n"), nil
+}
+
+func plainText(body string) string {
+	text := html.UnescapeString(tagPattern.ReplaceAllString(body, " "))
+	return strings.TrimSpace(whitespacePattern.ReplaceAllString(text, " "))
+}
@@ -0,0 +1,184 @@
+package site_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/BobMali/adr-helper/internal/site"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeADRs(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"0001-use-mysql.md": "# 1. Use MySQL\n\nDate: 2024-01-01\n\n## Status\n\nSuperseded by [ADR-0002](0002-use-postgresql.md)  \n\n## Context\n\nWe need a database.\n",
+		"0002-use-postgresql.md": "# 2. Use PostgreSQL\n\nDate: 2024-02-01\n\nScope: Backend, Data\n\n## Status\n\nAccepted\n\nSupersedes [ADR-0001](0001-use-mysql.md)  \n\n" +
+			"## Context\n\nMySQL lacks <features>. See [the decision](0003-cache-with-redis.md#decision-outcome).\n",
+		"0003-cache-with-redis.md": "---\nstatus: \"proposed\"\ndate: 2024-03-01\n---\n\n# 3. Cache with Redis\n\n" +
+			"## Relations\n\nRelates to [ADR-0002](0002-use-postgresql.md)  \n\n## Decision Outcome\n\nUse Redis.\n",
+		"template.md": "# NUMBER. TITLE\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	return dir
+}
+
+func readOut(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestExport_WritesSite(t *testing.T) {
+	dir := writeADRs(t)
+	out := filepath.Join(t.TempDir(), "site")
+
+	n, err := site.Export(context.Background(), adr.NewFileRepository(dir), out, site.Options{Title: "Payments decisions"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	for _, name := range []string{"index.html", "graph.html", "style.css", "search.js", "search-index.js",
+		"0001-use-mysql.html", "0002-use-postgresql.html", "0003-cache-with-redis.html",
+		"scope-backend.html", "scope-data.html"} {
+		assert.FileExists(t, filepath.Join(out, name))
+	}
+	assert.NoFileExists(t, filepath.Join(out, "template.html"))
+
+	index := readOut(t, out, "index.html")
+	assert.Contains(t, index, "<title>Payments decisions</title>")
+	assert.Contains(t, index, `<tr data-number="2" data-status="accepted">`)
+	assert.Contains(t, index, `<a href="0002-use-postgresql.html">Use PostgreSQL</a>`)
+	assert.Contains(t, index, `<span class="badge status-superseded">Superseded</span>`)
+	assert.Contains(t, index, `<a class="scope" href="scope-backend.html">Backend <span class="count">1</span></a>`)
+	assert.Contains(t, index, `<script src="search-index.js"></script>`)
+}
+
+func TestExport_RemovesStalePages(t *testing.T) {
+	dir := writeADRs(t)
+	out := filepath.Join(t.TempDir(), "site")
+	repo := adr.NewFileRepository(dir)
+	_, err := site.Export(context.Background(), repo, out, site.Options{})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(out, "notes.txt"), []byte("keep"), 0o644))
+
+	require.NoError(t, os.Rename(filepath.Join(dir, "0003-cache-with-redis.md"), filepath.Join(dir, "0003-cache-with-valkey.md")))
+	require.NoError(t, os.Remove(filepath.Join(dir, "0002-use-postgresql.md")))
+	_, err = site.Export(context.Background(), repo, out, site.Options{})
+	require.NoError(t, err)
+
+	assert.FileExists(t, filepath.Join(out, "0003-cache-with-valkey.html"))
+	assert.FileExists(t, filepath.Join(out, "notes.txt"))
+	for _, name := range []string{"0003-cache-with-redis.html", "0002-use-postgresql.html", "scope-backend.html"} {
+		assert.NoFileExists(t, filepath.Join(out, name))
+	}
+}
+
+func TestExport_ADRPage(t *testing.T) {
+	dir := writeADRs(t)
+	out := t.TempDir()
+	_, err := site.Export(context.Background(), adr.NewFileRepository(dir), out, site.Options{})
+	require.NoError(t, err)
+
+	page := readOut(t, out, "0002-use-postgresql.html")
+	assert.Contains(t, page, "<title>2. Use PostgreSQL – Architecture Decision Records</title>")
+	assert.Contains(t, page, `<h1 id="2-use-postgresql">2. Use PostgreSQL</h1>`)
+	assert.Contains(t, page, "MySQL lacks &lt;features&gt;.")
+	assert.Contains(t, page, `<a href="0003-cache-with-redis.html#decision-outcome">the decision</a>`)
+	assert.Contains(t, page, `<a href="0001-use-mysql.html">ADR-0001</a>`)
+	assert.Contains(t, page, "<h2>Supersedes</h2>\n<ul>\n<li><a href=\"0001-use-mysql.html\">0001 Use MySQL</a>")
+	assert.Contains(t, page, "<h2>Relates to</h2>\n<ul>\n<li><a href=\"0003-cache-with-redis.html\">0003 Cache with Redis</a>")
+	assert.Contains(t, page, `<a class="scope" href="scope-data.html">Data</a>`)
+	assert.Contains(t, page, `<a class="prev" href="0001-use-mysql.html">`)
+	assert.Contains(t, page, `<a class="next" href="0003-cache-with-redis.html">`)
+
+	// The relation written only on 0003 shows on both sides.
+	redis := readOut(t, out, "0003-cache-with-redis.html")
+	assert.Contains(t, redis, "<h2>Relates to</h2>")
+	assert.NotContains(t, redis, "status: &#34;proposed&#34;")
+}
+
+func TestExport_GraphAndSearchIndex(t *testing.T) {
+	dir := writeADRs(t)
+	out := t.TempDir()
+	_, err := site.Export(context.Background(), adr.NewFileRepository(dir), out, site.Options{})
+	require.NoError(t, err)
+
+	graph := readOut(t, out, "graph.html")
+	assert.Equal(t, 1, strings.Count(graph, `class="edge edge-supersedes"`))
+	assert.Equal(t, 1, strings.Count(graph, `class="edge edge-relates"`))
+	assert.Contains(t, graph, `<a href="0001-use-mysql.html"><g class="node status-superseded">`)
+
+	index := readOut(t, out, "search-index.js")
+	assert.True(t, strings.HasPrefix(index, "window.ADR_SEARCH_INDEX = ["))
+	assert.Contains(t, index, `"title":"Use PostgreSQL","status":"accepted","date":"2024-02-01","scopes":["Backend","Data"],"url":"0002-use-postgresql.html"`)
+	assert.Contains(t, index, `MySQL lacks \u003cfeatures\u003e.`)
+}
+
+func TestExport_FiltersAndSorts(t *testing.T) {
+	dir := writeADRs(t)
+	out := t.TempDir()
+
+	n, err := site.Export(context.Background(), adr.NewFileRepository(dir), out, site.Options{Scopes: []string{"backend"}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	page := readOut(t, out, "0002-use-postgresql.html")
+	assert.NotContains(t, page, "<h2>Supersedes</h2>")
+	assert.Contains(t, page, `<a href="0001-use-mysql.md">ADR-0001</a>`)
+
+	out = t.TempDir()
+	_, err = site.Export(context.Background(), adr.NewFileRepository(dir), out, site.Options{SortField: "date", SortDesc: true})
+	require.NoError(t, err)
+	index := readOut(t, out, "index.html")
+	assert.Less(t, strings.Index(index, `data-number="3"`), strings.Index(index, `data-number="1"`))
+
+	_, err = site.Export(context.Background(), adr.NewFileRepository(dir), t.TempDir(), site.Options{SortField: "colour"})
+	assert.ErrorContains(t, err, `invalid sort field "colour"`)
+}
+
+func TestExport_NestedDirectories(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"platform/0001-use-go.md":  "# 1. Use Go\n\n## Status\n\nAccepted\n\n## Context\n\nSee [the UI](../frontend/0002-use-vue.md).\n",
+		"frontend/0002-use-vue.md": "# 2. Use Vue\n\n## Status\n\nAccepted\n\n## Relations\n\nRelates to [ADR-0001](../platform/0001-use-go.md)  \n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	cfg := &adr.Config{Directory: dir, Recursive: true}
+	out := t.TempDir()
+
+	n, err := site.Export(context.Background(), cfg.Repository(), out, site.Options{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Contains(t, readOut(t, out, "platform-0001-use-go.html"), `<a href="frontend-0002-use-vue.html">the UI</a>`)
+	assert.Contains(t, readOut(t, out, "frontend-0002-use-vue.html"), `href="platform-0001-use-go.html"`)
+}
+
+func TestExport_IgnoresRelationsIntoOtherRoots(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"0001-use-adyen.md": "# 1. Use Adyen\n\n## Status\n\nAccepted\n",
+		"0002-use-kafka.md": "# 2. Use Kafka\n\n## Status\n\nAccepted\n\n" +
+			"Supersedes [payments:ADR-0001](../../services/pay/adr/0001-use-stripe.md)  \n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	out := t.TempDir()
+
+	_, err := site.Export(context.Background(), adr.NewFileRepository(dir), out, site.Options{})
+	require.NoError(t, err)
+	assert.NotContains(t, readOut(t, out, "0001-use-adyen.html"), "Superseded by")
+	page := readOut(t, out, "0002-use-kafka.html")
+	assert.NotContains(t, page, "<h2>Supersedes</h2>")
+	assert.Contains(t, page, `href="../../services/pay/adr/0001-use-stripe.md"`)
+}
@@ -1,6 +1,7 @@
 package web
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -8,12 +9,16 @@ import (
 	"io"
 	"io/fs"
 	"log"
+	"maps"
+	"mime"
 	"net/http"
 	"path"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/BobMali/adr-helper/internal/adr"
+	"github.com/BobMali/adr-helper/internal/markdown"
 	"github.com/go-chi/chi/v5"
 )
 
@@ -37,6 +42,19 @@ type Relator interface {
 	AddRelation(ctx context.Context, sourceNum, targetNum int) (*adr.ADR, error)
 }
 
+// Renamer replaces an ADR's content and renames its file to the new title's
+// slug, rewriting inbound links in other ADRs, in one change: when the rename
+// is rejected, the content isn't written either.
+type Renamer interface {
+	UpdateContentAndRename(ctx context.Context, number int, content string) (*adr.ADR, error)
+}
+
+// ArchiveLister lists ADRs including those moved to the archive directory,
+// e.g. an *adr.FileRepository.
+type ArchiveLister interface {
+	ListWithArchived(ctx context.Context) ([]adr.ADR, error)
+}
+
 // ScopeStore reads and extends the project's scope vocabulary, persisting
 // additions. Implementations must be safe for concurrent use.
 type ScopeStore interface {
@@ -44,6 +62,20 @@ type ScopeStore interface {
 	AddScope(value string) ([]string, error)
 }
 
+// TemplateProvider supplies the project template (content and section schema)
+// for the create form, e.g. an *adr.TemplateLoader that follows edits to the
+// on-disk template file.
+type TemplateProvider interface {
+	Load(name string) (*adr.ProjectTemplate, error)
+}
+
+// AssetStore serves and stores the non-markdown files kept alongside ADRs
+// (images, diagrams), e.g. an *adr.FileRepository.
+type AssetStore interface {
+	OpenAsset(name string) (fs.File, error)
+	SaveAsset(ctx context.Context, number int, name string, data io.Reader) (string, error)
+}
+
 // ServerOption configures optional Server behaviour.
 type ServerOption func(*Server)
 
@@ -83,6 +115,47 @@ func WithContentUpdater(u ContentUpdater) ServerOption {
 	}
 }
 
+// WithRenamer enables the optional rename on the PUT content endpoint.
+func WithRenamer(ren Renamer) ServerOption {
+	return func(s *Server) {
+		s.renamer = ren
+	}
+}
+
+// WithArchiveLister enables listing archived ADRs with ?archived=true.
+func WithArchiveLister(l ArchiveLister) ServerOption {
+	return func(s *Server) {
+		s.archive = l
+	}
+}
+
+// WithRoot serves the ADRs of another of the project's roots (see
+// adr.Config.Root) from root: they are listed along with the server's own and
+// addressed by root-qualified IDs, as in /api/adr/payments:12.
+func WithRoot(name string, root *Server) ServerOption {
+	return func(s *Server) {
+		if s.roots == nil {
+			s.roots = make(map[string]*Server)
+		}
+		s.roots[name] = root
+		root.root = name
+	}
+}
+
+// WithProject serves another project's ADRs (see adr.Mount) from project,
+// under /api/projects/{name}/…: its routes are the ones a server has at /api/…,
+// its ADRs are listed in the cross-project /api/catalog, and links and
+// relations between the projects' ADRs resolve across them.
+func WithProject(name string, project *Server) ServerOption {
+	return func(s *Server) {
+		if s.projects == nil {
+			s.projects = make(map[string]*Server)
+		}
+		s.projects[name] = project
+		project.mount(name, s)
+	}
+}
+
 // WithConfig provides the project configuration for template rendering.
 func WithConfig(cfg *adr.Config) ServerOption {
 	return func(s *Server) {
@@ -90,6 +163,22 @@ func WithConfig(cfg *adr.Config) ServerOption {
 	}
 }
 
+// WithTemplateProvider sets where templates are loaded from. Without one the
+// server resolves them from the config on every request.
+func WithTemplateProvider(p TemplateProvider) ServerOption {
+	return func(s *Server) {
+		s.templates = p
+	}
+}
+
+// WithAuthorHeader names the request header, set by a trusted
+// authenticating proxy, whose value templates see as {{.Author}}.
+func WithAuthorHeader(name string) ServerOption {
+	return func(s *Server) {
+		s.authorHeader = name
+	}
+}
+
 // WithScopeStore enables the scope vocabulary endpoints.
 func WithScopeStore(store ScopeStore) ServerOption {
 	return func(s *Server) {
@@ -97,6 +186,14 @@ func WithScopeStore(store ScopeStore) ServerOption {
 	}
 }
 
+// WithAssetStore enables the asset endpoints: serving files next to the ADRs
+// and uploading attachments.
+func WithAssetStore(store AssetStore) ServerOption {
+	return func(s *Server) {
+		s.assets = store
+	}
+}
+
 // Saver can persist a new ADR record.
 type Saver interface {
 	Save(ctx context.Context, record *adr.ADR) error
@@ -112,8 +209,22 @@ type Server struct {
 	superseder     Superseder
 	relator        Relator
 	contentUpdater ContentUpdater
+	renamer        Renamer
+	archive        ArchiveLister
+	roots          map[string]*Server
+	projects       map[string]*Server
 	scopeStore     ScopeStore
+	assets         AssetStore
+	templates      TemplateProvider
+	authorHeader   string
 	config         *adr.Config
+
+	// root is set on a root's server by WithRoot: the root's name.
+	root string
+	// project and catalog are set on a project's server, and its roots', by
+	// WithProject: its name and the server federating it.
+	project string
+	catalog *Server
 }
 
 // NewServer creates a new Server with routes configured.
@@ -125,19 +236,36 @@ func NewServer(repo adr.Repository, opts ...ServerOption) *Server {
 		opt(s)
 	}
 
+	if len(s.projects) > 0 {
+		r.Use(s.routeProjects)
+		for _, root := range s.roots {
+			root.catalog = s
+		}
+	}
+	if len(s.roots) > 0 {
+		r.Use(s.routeRoots)
+	}
+
 	r.Get("/health", s.handleHealth)
 	r.Get("/api/config", s.handleGetConfig)
 	r.Get("/api/template-sections", s.handleGetTemplateSections)
+	r.Get("/api/templates", s.handleGetTemplates)
 	r.Get("/api/meta-fields", s.handleGetMetaFields)
 	r.Get("/api/scopes", s.handleGetScopes)
 	r.Post("/api/scopes", s.handleAddScope)
+	r.Get("/api/catalog", s.handleCatalog)
 	r.Get("/api/adr", s.handleListADRs)
 	r.Get("/api/adr/statuses", s.handleStatuses)
 	r.Post("/api/adr", s.handleCreateADR)
 	r.Get("/api/adr/{number}", s.handleGetADR)
+	r.Get("/api/adr/{number}/html", s.handleGetADRHTML)
 	r.Put("/api/adr/{number}", s.handleUpdateContent)
+	r.Patch("/api/adr/{number}/sections/{key}", s.handlePatchSection)
+	r.Patch("/api/adr/{number}/meta/{key}", s.handlePatchMeta)
 	r.Patch("/api/adr/{number}/status", s.handleUpdateStatus)
 	r.Post("/api/adr/{number}/relations", s.handleAddRelation)
+	r.Post("/api/adr/{number}/assets", s.handleUploadAsset)
+	r.Get("/api"+assetRoute+"*", s.handleGetAsset)
 
 	if s.frontend != nil {
 		r.NotFound(spaHandler(s.frontend))
@@ -157,20 +285,29 @@ func (s *Server) ListenAndServe(addr string) error {
 }
 
 type adrResponse struct {
-	Number int                 `json:"number"`
-	Title  string              `json:"title"`
-	Status adr.Status          `json:"status"`
-	Date   string              `json:"date"`
-	Meta   map[string][]string `json:"meta,omitempty"`
+	Project  string              `json:"project,omitempty"`
+	Root     string              `json:"root,omitempty"`
+	Number   int                 `json:"number"`
+	Title    string              `json:"title"`
+	Status   adr.Status          `json:"status"`
+	Date     string              `json:"date"`
+	Meta     map[string][]string `json:"meta,omitempty"`
+	Archived bool                `json:"archived,omitempty"`
 }
 
 type adrDetailResponse struct {
-	Number  int                 `json:"number"`
-	Title   string              `json:"title"`
-	Status  adr.Status          `json:"status"`
-	Date    string              `json:"date"`
-	Content string              `json:"content"`
-	Meta    map[string][]string `json:"meta,omitempty"`
+	Root string `json:"root,omitempty"`
+	// Dir is the directory of the ADR's file below the ADR directory, which
+	// its relative links are written from; omitted at the top level.
+	Dir      string              `json:"dir,omitempty"`
+	Number   int                 `json:"number"`
+	Title    string              `json:"title"`
+	Status   adr.Status          `json:"status"`
+	Date     string              `json:"date"`
+	Content  string              `json:"content"`
+	Meta     map[string][]string `json:"meta,omitempty"`
+	Sections []adr.Section       `json:"sections"`
+	Archived bool                `json:"archived,omitempty"`
 }
 
 func toResponse(a adr.ADR) adrResponse {
@@ -179,26 +316,50 @@ func toResponse(a adr.ADR) adrResponse {
 		dateStr = a.Date.Format("2006-01-02")
 	}
 	return adrResponse{
-		Number: a.Number,
-		Title:  a.Title,
-		Status: a.Status,
-		Date:   dateStr,
-		Meta:   a.Meta,
+		Root:     a.Root,
+		Number:   a.Number,
+		Title:    a.Title,
+		Status:   a.Status,
+		Date:     dateStr,
+		Meta:     a.Meta,
+		Archived: a.Archived,
 	}
 }
 
-func toDetailResponse(a adr.ADR) adrDetailResponse {
+// detailResponse returns a's full record, with its content parsed into
+// sections keyed by the project's templates.
+func (s *Server) detailResponse(a adr.ADR) adrDetailResponse {
 	dateStr := ""
 	if !a.Date.IsZero() {
 		dateStr = a.Date.Format("2006-01-02")
 	}
+	var defs []adr.TemplateSectionDef
+	if s.config != nil {
+		defs = adr.KnownSections(s.config, s.loadTemplate)
+	}
+	sections := adr.ParseDocument(a.Content, defs).Sections
+	if sections == nil {
+		sections = []adr.Section{}
+	}
+	var dir string
+	if s.config != nil {
+		if cfg, err := s.config.Resolve(a.ID()); err == nil {
+			if file, err := cfg.Repository().FindFile(a.Number); err == nil && path.Dir(file) != "." {
+				dir = path.Dir(file)
+			}
+		}
+	}
 	return adrDetailResponse{
-		Number:  a.Number,
-		Title:   a.Title,
-		Status:  a.Status,
-		Date:    dateStr,
-		Content: a.Content,
-		Meta:    a.Meta,
+		Root:     a.Root,
+		Dir:      dir,
+		Number:   a.Number,
+		Title:    a.Title,
+		Status:   a.Status,
+		Date:     dateStr,
+		Content:  a.Content,
+		Meta:     a.Meta,
+		Sections: sections,
+		Archived: a.Archived,
 	}
 }
 
@@ -212,12 +373,12 @@ type metaFieldResponse struct {
 }
 
 func (s *Server) handleListADRs(w http.ResponseWriter, r *http.Request) {
-	if s.repo == nil {
+	if s.repo == nil && len(s.roots) == 0 {
 		http.Error(w, "repository not configured", http.StatusServiceUnavailable)
 		return
 	}
 
-	adrs, err := s.repo.List(r.Context())
+	adrs, err := s.listRoots(r)
 	if err != nil {
 		http.Error(w, "failed to list ADRs", http.StatusInternalServerError)
 		return
@@ -238,6 +399,181 @@ func (s *Server) handleListADRs(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleCatalog lists, and with ?q= searches, the ADRs of the server and of
+// every project mounted with WithProject, each tagged with its project.
+func (s *Server) handleCatalog(w http.ResponseWriter, r *http.Request) {
+	if s.repo == nil && len(s.roots) == 0 && len(s.projects) == 0 {
+		http.Error(w, "repository not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	q := r.URL.Query().Get("q")
+	resp := []adrResponse{}
+	add := func(name string, project *Server) error {
+		adrs, err := project.listRoots(r)
+		if err != nil {
+			return err
+		}
+		if q != "" {
+			adrs = adr.FilterByQuery(adrs, q)
+		}
+		for _, a := range adrs {
+			item := toResponse(a)
+			item.Project = name
+			resp = append(resp, item)
+		}
+		return nil
+	}
+	if err := add("", s); err != nil {
+		http.Error(w, "failed to list ADRs", http.StatusInternalServerError)
+		return
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.projects)) {
+		if err := add(name, s.projects[name]); err != nil {
+			http.Error(w, "failed to list ADRs", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// listRoots returns the server's own ADRs followed by its roots', in root name
+// order.
+func (s *Server) listRoots(r *http.Request) ([]adr.ADR, error) {
+	adrs, err := s.list(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.roots)) {
+		rootADRs, err := s.roots[name].list(r)
+		if err != nil {
+			return nil, err
+		}
+		for i := range rootADRs {
+			rootADRs[i].Root = name
+		}
+		adrs = append(adrs, rootADRs...)
+	}
+	return adrs, nil
+}
+
+// list returns the server's own ADRs, with archived ones on ?archived=true.
+func (s *Server) list(r *http.Request) ([]adr.ADR, error) {
+	if s.repo == nil {
+		return nil, nil
+	}
+	list := s.repo.List
+	if r.URL.Query().Get("archived") == "true" && s.archive != nil {
+		list = s.archive.ListWithArchived
+	}
+	return list(r.Context())
+}
+
+// routeRoots hands requests for a root-qualified ADR ("/api/adr/payments:12…")
+// to that root's server, as requests for the unqualified ID, and requests
+// under /api/roots/{name}/ (its assets) as requests for the same path under
+// /api/.
+func (s *Server) routeRoots(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rest, ok := strings.CutPrefix(r.URL.Path, "/api/roots/"); ok {
+			name, tail, _ := strings.Cut(rest, "/")
+			root, ok := s.roots[name]
+			if !ok {
+				http.Error(w, "unknown root", http.StatusNotFound)
+				return
+			}
+			r = r.Clone(context.WithValue(r.Context(), chi.RouteCtxKey, nil))
+			r.URL.Path = "/api/" + tail
+			r.URL.RawPath = ""
+			root.router.ServeHTTP(w, r)
+			return
+		}
+		rest, ok := strings.CutPrefix(r.URL.Path, "/api/adr/")
+		ref, tail, _ := strings.Cut(rest, "/")
+		name, number, qualified := strings.Cut(ref, ":")
+		if !ok || !qualified {
+			next.ServeHTTP(w, r)
+			return
+		}
+		root, ok := s.roots[name]
+		if !ok {
+			http.Error(w, "unknown root", http.StatusNotFound)
+			return
+		}
+		// A fresh routing context lets the root's router match the path anew.
+		r = r.Clone(context.WithValue(r.Context(), chi.RouteCtxKey, nil))
+		r.URL.Path = "/api/adr/" + number
+		if tail != "" {
+			r.URL.Path += "/" + tail
+		}
+		r.URL.RawPath = ""
+		root.router.ServeHTTP(w, r)
+	})
+}
+
+// routeProjects hands requests under /api/projects/{name}/ to that project's
+// server, as requests for the same path under /api/.
+func (s *Server) routeProjects(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest, ok := strings.CutPrefix(r.URL.Path, "/api/projects/")
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		name, tail, _ := strings.Cut(rest, "/")
+		project, ok := s.projects[name]
+		if !ok {
+			http.Error(w, "unknown project", http.StatusNotFound)
+			return
+		}
+		r = r.Clone(context.WithValue(r.Context(), chi.RouteCtxKey, nil))
+		r.URL.Path = "/api/" + tail
+		r.URL.RawPath = ""
+		project.router.ServeHTTP(w, r)
+	})
+}
+
+// mount names s, and its roots, as the project name of catalog.
+func (s *Server) mount(name string, catalog *Server) {
+	s.project, s.catalog = name, catalog
+	for _, root := range s.roots {
+		root.mount(name, catalog)
+	}
+}
+
+// locate returns the server, among the catalog's own and its projects', whose
+// ADRs include the one stored at file, and that ADR's ID there.
+func (s *Server) locate(file string) (*Server, adr.ID, bool) {
+	if s.config != nil {
+		if id, ok := s.config.Locate(file); ok {
+			return s, id, true
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.projects)) {
+		project := s.projects[name]
+		if project.config == nil {
+			continue
+		}
+		if id, ok := project.config.Locate(file); ok {
+			return project, id, true
+		}
+	}
+	return nil, adr.ID{}, false
+}
+
+// federation returns the server federating s with other projects: its catalog,
+// s itself when it mounts projects, or nil.
+func (s *Server) federation() *Server {
+	if s.catalog == nil && len(s.projects) > 0 {
+		return s
+	}
+	return s.catalog
+}
+
 func (s *Server) handleGetADR(w http.ResponseWriter, r *http.Request) {
 	if s.repo == nil {
 		http.Error(w, "repository not configured", http.StatusServiceUnavailable)
@@ -262,11 +598,138 @@ func (s *Server) handleGetADR(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(toDetailResponse(*record)); err != nil {
+	if err := json.NewEncoder(w).Encode(s.detailResponse(*record)); err != nil {
 		http.Error(w, "failed to encode response", http.StatusInternalServerError)
 	}
 }
 
+// handleGetADRHTML renders an ADR (or, with ?section=, one section of it,
+// matched by heading text or anchor id) as sanitized HTML, with links to other
+// ADR files pointing at their SPA routes.
+func (s *Server) handleGetADRHTML(w http.ResponseWriter, r *http.Request) {
+	if s.repo == nil {
+		http.Error(w, "repository not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	number, err := strconv.Atoi(chi.URLParam(r, "number"))
+	if err != nil || number <= 0 {
+		http.Error(w, "invalid ADR number", http.StatusBadRequest)
+		return
+	}
+
+	record, err := s.repo.Get(r.Context(), number)
+	if err != nil {
+		if errors.Is(err, adr.ErrNotFound) {
+			http.Error(w, "ADR not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "failed to get ADR", http.StatusInternalServerError)
+		return
+	}
+
+	opts := markdown.Options{RewriteLink: s.linkRewriter(number)}
+	body := markdown.ToHTML(record.Content, opts)
+	if section := r.URL.Query().Get("section"); section != "" {
+		var ok bool
+		if body, ok = markdown.SectionHTML(record.Content, section, opts); !ok {
+			http.Error(w, "section not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	_, _ = io.WriteString(w, body)
+}
+
+// rewriteLink points relative links to ADR files ("0002-x.md#status") at the
+// SPA's /adr/{number} route, keeping any fragment, and other relative links
+// (images, diagrams) at the asset endpoint when it is enabled. ADR files are
+// recognized by the project's naming when a config is set. dir is the
+// directory of the linking ADR below the ADR directory ("." at the top), which
+// asset links are relative to; links leaving the ADR directory are kept.
+func (s *Server) rewriteLink(dir, dest string) string {
+	name, fragment, hasFragment := strings.Cut(dest, "#")
+	if n, ok := s.fileNumber(strings.TrimPrefix(name, "./")); ok {
+		return s.adrRoute(strconv.Itoa(n), fragment, hasFragment)
+	}
+	if s.assets == nil || name == "" || strings.HasPrefix(name, "/") || strings.Contains(name, ":") ||
+		strings.EqualFold(path.Ext(name), ".md") {
+		return dest
+	}
+	asset := path.Join(dir, name)
+	if asset == ".." || strings.HasPrefix(asset, "../") {
+		return dest
+	}
+	return s.assetBase() + asset
+}
+
+// linkRewriter returns the RewriteLink for the HTML of ADR number, which
+// resolves asset links against the ADR's own directory. In a project
+// with several roots, or among mounted projects, links are resolved from the
+// ADR's own file, so that links into a root point at its root-qualified route
+// ("/adr/payments:12") and links into another project at that project's
+// ("/projects/billing/adr/3").
+func (s *Server) linkRewriter(number int) func(string) string {
+	dir := "."
+	local := func(dest string) string { return s.rewriteLink(dir, dest) }
+	if s.config == nil {
+		return local
+	}
+	source, err := s.config.Repository().FindFile(number)
+	if err != nil {
+		return local
+	}
+	dir = path.Dir(source)
+	federation := s.federation()
+	if len(s.config.RootNames()) == 0 && federation == nil {
+		return local
+	}
+	return func(dest string) string {
+		name, fragment, hasFragment := strings.Cut(dest, "#")
+		file, ok := s.config.LinkPath(source, strings.TrimPrefix(name, "./"))
+		if !ok {
+			return local(dest)
+		}
+		if id, ok := s.config.Locate(file); ok {
+			return s.adrRoute(id.String(), fragment, hasFragment)
+		}
+		if federation != nil {
+			if owner, id, ok := federation.locate(file); ok {
+				return owner.adrRoute(id.String(), fragment, hasFragment)
+			}
+		}
+		return local(dest)
+	}
+}
+
+// adrRoute returns the SPA route of the server's ADR with the given ID, with
+// the fragment when hasFragment is set.
+func (s *Server) adrRoute(id, fragment string, hasFragment bool) string {
+	link := "/adr/" + id
+	if s.project != "" {
+		link = "/projects/" + s.project + link
+	}
+	if hasFragment {
+		link += "#" + fragment
+	}
+	return link
+}
+
+// fileNumber returns the number of the ADR file name links to. Links between
+// ADRs in subdirectories are relative ("../platform/0002-x.md"), so a numbered
+// filename is recognized by its last element.
+func (s *Server) fileNumber(name string) (int, bool) {
+	if strings.Contains(name, ":") || strings.HasPrefix(name, "/") {
+		return 0, false
+	}
+	if s.config == nil {
+		return adr.ADRFilenameNumber(path.Base(name))
+	}
+	return s.config.Repository().FileNumber(name)
+}
+
 func (s *Server) handleStatuses(w http.ResponseWriter, _ *http.Request) {
 	statuses := adr.AllStatuses()
 	names := make([]string, len(statuses))
@@ -343,7 +806,7 @@ func (s *Server) handleUpdateStatus(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(toDetailResponse(*record)); err != nil {
+	if err := json.NewEncoder(w).Encode(s.detailResponse(*record)); err != nil {
 		http.Error(w, "failed to encode response", http.StatusInternalServerError)
 	}
 }
@@ -375,6 +838,10 @@ func (s *Server) handleAddRelation(w http.ResponseWriter, r *http.Request) {
 	r.Body = http.MaxBytesReader(w, r.Body, 1024)
 	var body struct {
 		RelatedTo int `json:"relatedTo"`
+		// Project and Root name where relatedTo is, for a relation across
+		// mounted projects or roots; Root defaults to the project's default.
+		Project string `json:"project"`
+		Root    string `json:"root"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		http.Error(w, "invalid request body", http.StatusBadRequest)
@@ -386,35 +853,250 @@ func (s *Server) handleAddRelation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if body.RelatedTo == number {
-		http.Error(w, "cannot relate an ADR to itself", http.StatusBadRequest)
+	var record *adr.ADR
+	if body.Project != "" || body.Root != "" {
+		target, status, msg := s.relationTarget(body.Project, body.Root)
+		if target == nil {
+			http.Error(w, msg, status)
+			return
+		}
+		if target.ProjectName() == s.config.ProjectName() && target.RootName() == s.config.RootName() {
+			if body.RelatedTo == number {
+				http.Error(w, "cannot relate an ADR to itself", http.StatusBadRequest)
+				return
+			}
+			record, err = s.relator.AddRelation(r.Context(), number, body.RelatedTo)
+		} else {
+			record, err = s.config.Relate(number, target, body.RelatedTo)
+		}
+	} else {
+		if body.RelatedTo == number {
+			http.Error(w, "cannot relate an ADR to itself", http.StatusBadRequest)
+			return
+		}
+		record, err = s.relator.AddRelation(r.Context(), number, body.RelatedTo)
+	}
+	if err != nil {
+		if errors.Is(err, adr.ErrNotFound) {
+			http.Error(w, "ADR not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "failed to add relation", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.detailResponse(*record)); err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// relationTarget returns the config of the root named root in the project
+// named project, the server's own when project is empty, or the HTTP status
+// and message to reject the relation with.
+func (s *Server) relationTarget(project, root string) (*adr.Config, int, string) {
+	if s.config == nil {
+		// Every server of a mounted project or root has its config (see
+		// cmd/adr-web); one without knows of no other root or project.
+		return nil, http.StatusNotFound, "unknown root"
+	}
+	cfg := s.config
+	if project != "" && project != s.project {
+		federation := s.federation()
+		if federation == nil {
+			return nil, http.StatusNotFound, "unknown project"
+		}
+		target, ok := federation.projects[project]
+		if !ok || target.config == nil {
+			return nil, http.StatusNotFound, "unknown project"
+		}
+		cfg = target.config
+	}
+	target, err := cfg.Root(root)
+	if err != nil {
+		if errors.Is(err, adr.ErrUnknownRoot) {
+			return nil, http.StatusNotFound, "unknown root"
+		}
+		return nil, http.StatusBadRequest, "root required"
+	}
+	return target, 0, ""
+}
+
+// patchError rejects a PATCH edit with an HTTP status.
+type patchError struct {
+	status int
+	msg    string
+}
+
+func (e *patchError) Error() string { return e.msg }
+
+// patchADR decodes the JSON request body into body, applies edit to the
+// current content of the ADR named in the URL and saves the result. edit
+// receives the section definitions of the project's templates (see
+// adr.KnownSections) and returns a *patchError, or an adr.ErrInvalidScope
+// error, to reject the change.
+func (s *Server) patchADR(w http.ResponseWriter, r *http.Request, body any, edit func(content string, defs []adr.TemplateSectionDef) (string, error)) {
+	if s.repo == nil {
+		http.Error(w, "repository not configured", http.StatusServiceUnavailable)
+		return
+	}
+	if s.config == nil {
+		http.Error(w, "config not available", http.StatusServiceUnavailable)
+		return
+	}
+	if s.contentUpdater == nil {
+		http.Error(w, "content updates not supported", http.StatusNotImplemented)
+		return
+	}
+
+	number, err := strconv.Atoi(chi.URLParam(r, "number"))
+	if err != nil || number <= 0 {
+		http.Error(w, "invalid ADR number", http.StatusBadRequest)
+		return
+	}
+
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		http.Error(w, "Content-Type must be application/json", http.StatusBadRequest)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, 65536)
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	record, err := s.relator.AddRelation(r.Context(), number, body.RelatedTo)
+	record, err := s.repo.Get(r.Context(), number)
 	if err != nil {
 		if errors.Is(err, adr.ErrNotFound) {
 			http.Error(w, "ADR not found", http.StatusNotFound)
 			return
 		}
-		http.Error(w, "failed to add relation", http.StatusInternalServerError)
+		http.Error(w, "failed to get ADR", http.StatusInternalServerError)
+		return
+	}
+
+	content, err := edit(record.Content, adr.KnownSections(s.config, s.loadTemplate))
+	if err != nil {
+		var perr *patchError
+		switch {
+		case errors.As(err, &perr):
+			http.Error(w, perr.msg, perr.status)
+		case errors.Is(err, adr.ErrInvalidScope):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "failed to update content", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	record, err = s.contentUpdater.UpdateContent(r.Context(), number, content)
+	if err != nil {
+		if errors.Is(err, adr.ErrNotFound) {
+			http.Error(w, "ADR not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "failed to update content", http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(toDetailResponse(*record)); err != nil {
+	if err := json.NewEncoder(w).Encode(s.detailResponse(*record)); err != nil {
 		http.Error(w, "failed to encode response", http.StatusInternalServerError)
 	}
 }
 
+// findSectionDef returns the first def with key whose kind is one of kinds.
+func findSectionDef(defs []adr.TemplateSectionDef, key string, kinds ...string) (adr.TemplateSectionDef, bool) {
+	for _, d := range defs {
+		if d.Key == key && slices.Contains(kinds, d.Kind) {
+			return d, true
+		}
+	}
+	return adr.TemplateSectionDef{}, false
+}
+
+// handlePatchSection replaces the body of one "##"/"###" section, named by its
+// template key, keeping the rest of the ADR (subsections included) as is.
+func (s *Server) handlePatchSection(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Body string `json:"body"`
+	}
+	key := chi.URLParam(r, "key")
+	s.patchADR(w, r, &body, func(content string, defs []adr.TemplateSectionDef) (string, error) {
+		def, ok := findSectionDef(defs, key, "h2", "h3")
+		if !ok {
+			return "", &patchError{http.StatusNotFound, "unknown section"}
+		}
+		text := strings.Trim(body.Body, "\n")
+		if strings.TrimSpace(text) == "" && !def.Optional {
+			return "", &patchError{http.StatusBadRequest, def.Heading + " is required"}
+		}
+		section := adr.ParseDocument(content, defs).Section(key)
+		if section == nil {
+			return "", &patchError{http.StatusNotFound, "section not found in ADR"}
+		}
+		updated, _ := adr.ReplaceSectionContent(content, section.Heading, text)
+		return updated, nil
+	})
+}
+
+// handlePatchMeta sets one metadata field, named by its template key: a
+// title-block line ("Scope: …") or a frontmatter key, whose comma-separated
+// value is written as a list (see adr.SetFrontmatterList).
+func (s *Server) handlePatchMeta(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Value string `json:"value"`
+	}
+	key := chi.URLParam(r, "key")
+	s.patchADR(w, r, &body, func(content string, defs []adr.TemplateSectionDef) (string, error) {
+		def, ok := findSectionDef(defs, key, "meta", "frontmatter")
+		if !ok {
+			return "", &patchError{http.StatusNotFound, "unknown metadata field"}
+		}
+		values := map[string]string{key: body.Value}
+		if err := s.normalizeSectionValues([]adr.TemplateSectionDef{def}, values); err != nil {
+			return "", err
+		}
+		value := strings.TrimSpace(values[key])
+		if value == "" && !def.Optional {
+			return "", &patchError{http.StatusBadRequest, def.Heading + " is required"}
+		}
+
+		var updated string
+		var found bool
+		if def.Kind == "meta" {
+			updated, found = adr.ReplaceMetaField(content, def.Heading, value)
+		} else {
+			updated, found = adr.SetFrontmatterList(content, def.Key, adr.SplitListValue(value))
+		}
+		if !found {
+			return "", &patchError{http.StatusNotFound, "field not found in ADR"}
+		}
+		return updated, nil
+	})
+}
+
 func (s *Server) handleGetConfig(w http.ResponseWriter, r *http.Request) {
-	if s.config == nil {
+	if s.config == nil && len(s.projects) == 0 {
 		http.Error(w, "config not available", http.StatusServiceUnavailable)
 		return
 	}
 
+	resp := map[string]any{}
+	if s.config != nil {
+		resp["template"] = s.config.Template
+	}
+	if len(s.roots) > 0 {
+		resp["roots"] = slices.Sorted(maps.Keys(s.roots))
+	}
+	if s.project != "" {
+		resp["project"] = s.project
+	}
+	if len(s.projects) > 0 {
+		resp["projects"] = slices.Sorted(maps.Keys(s.projects))
+	}
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(map[string]string{"template": s.config.Template}); err != nil {
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		http.Error(w, "failed to encode response", http.StatusInternalServerError)
 	}
 }
@@ -425,11 +1107,12 @@ func (s *Server) handleGetTemplateSections(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	sections, err := adr.TemplateSections(s.config.Template)
+	tmpl, err := s.loadTemplate(s.config.Template)
 	if err != nil {
 		http.Error(w, "failed to load template sections", http.StatusInternalServerError)
 		return
 	}
+	sections := tmpl.EditableSections()
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(sections); err != nil {
@@ -437,8 +1120,65 @@ func (s *Server) handleGetTemplateSections(w http.ResponseWriter, r *http.Reques
 	}
 }
 
+// templateResponse describes one template offered by GET /api/templates.
+type templateResponse struct {
+	Name     string                   `json:"name"`
+	Default  bool                     `json:"default"`
+	Sections []adr.TemplateSectionDef `json:"sections"`
+}
+
+func (s *Server) handleGetTemplates(w http.ResponseWriter, r *http.Request) {
+	if s.config == nil {
+		http.Error(w, "config not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	names := adr.TemplateChoices(s.config)
+	resp := make([]templateResponse, 0, len(names))
+	for _, name := range names {
+		tmpl, err := s.loadTemplate(name)
+		if err != nil {
+			// One broken template (e.g. a missing file) must not hide the rest.
+			log.Printf("warning: loading template %q: %v", name, err)
+			continue
+		}
+		resp = append(resp, templateResponse{
+			Name:     name,
+			Default:  name == s.config.Template,
+			Sections: tmpl.EditableSections(),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// loadTemplate resolves a project template through the configured provider,
+// or straight from the config when there is none. A built-in whose project
+// copy is missing is served from the embedded one.
+func (s *Server) loadTemplate(name string) (*adr.ProjectTemplate, error) {
+	var tmpl *adr.ProjectTemplate
+	var err error
+	if s.templates != nil {
+		tmpl, err = s.templates.Load(name)
+	} else {
+		tmpl, err = adr.LoadProjectTemplate(s.config, name)
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		if embedded, eerr := adr.EmbeddedTemplate(s.config, name); eerr == nil {
+			return embedded, nil
+		}
+	}
+	return tmpl, err
+}
+
 func (s *Server) handleGetMetaFields(w http.ResponseWriter, r *http.Request) {
 	defs := adr.AllMetaFieldDefs()
+	if s.config != nil {
+		defs = s.config.MetaFieldDefs()
+	}
 	resp := make([]metaFieldResponse, 0, len(defs))
 	for _, d := range defs {
 		mf := metaFieldResponse{Key: d.Key, Heading: d.Heading, Vocabulary: d.Vocabulary}
@@ -531,7 +1271,10 @@ func (s *Server) handleCreateADR(w http.ResponseWriter, r *http.Request) {
 	r.Body = http.MaxBytesReader(w, r.Body, 65536)
 	var body struct {
 		Title    string            `json:"title"`
+		Template string            `json:"template,omitempty"`
 		Sections map[string]string `json:"sections,omitempty"`
+		// Vars are custom template values, available as {{.Vars.key}}.
+		Vars map[string]string `json:"vars,omitempty"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		http.Error(w, "invalid request body", http.StatusBadRequest)
@@ -544,9 +1287,20 @@ func (s *Server) handleCreateADR(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	templateContent, err := adr.TemplateContent(s.config.Template)
+	templateName := s.config.Template
+	if body.Template != "" {
+		templateName = body.Template
+	}
+	tmpl, err := s.loadTemplate(templateName)
 	if err != nil {
-		http.Error(w, "failed to load template", http.StatusInternalServerError)
+		switch {
+		case errors.Is(err, adr.ErrUnknownTemplate):
+			http.Error(w, "unknown template", http.StatusBadRequest)
+		case errors.Is(err, fs.ErrNotExist):
+			http.Error(w, fmt.Sprintf("the file of template %q is missing", templateName), http.StatusUnprocessableEntity)
+		default:
+			http.Error(w, "failed to load template", http.StatusInternalServerError)
+		}
 		return
 	}
 
@@ -556,27 +1310,29 @@ func (s *Server) handleCreateADR(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := s.normalizeSectionValues(tmpl.EditableSections(), body.Sections); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	record := adr.New(nextNum, title)
-	record.Content = adr.RenderTemplate(templateContent, record)
+	data := adr.NewTemplateData(record)
+	if s.authorHeader != "" {
+		data.Author = strings.TrimSpace(r.Header.Get(s.authorHeader))
+	}
+	data.Scopes = adr.VocabularyValues(tmpl.EditableSections(), body.Sections)
+	data.Vars = body.Vars
+	content, err := tmpl.Execute(data)
+	if err != nil {
+		log.Printf("error rendering template %q: %v", tmpl.Name, err)
+		http.Error(w, "failed to render template", http.StatusInternalServerError)
+		return
+	}
+	record.Content = adr.RenderTemplate(content, record)
 
 	// Replace section content with user-provided values
 	if len(body.Sections) > 0 {
-		sectionDefs, _ := adr.TemplateSections(s.config.Template)
-		for _, def := range sectionDefs {
-			text, ok := body.Sections[def.Key]
-			if !ok || strings.TrimSpace(text) == "" {
-				continue
-			}
-			// "meta" kinds are title-block lines (e.g. Scope); everything else
-			// is a "## Heading" body section.
-			if def.Kind == "meta" {
-				if replaced, found := adr.ReplaceMetaField(record.Content, def.Heading, text); found {
-					record.Content = replaced
-				}
-			} else if replaced, found := adr.ReplaceSectionContent(record.Content, def.Heading, text); found {
-				record.Content = replaced
-			}
-		}
+		record.Content = adr.ApplySections(record.Content, tmpl.EditableSections(), body.Sections)
 	}
 
 	if err := s.repo.Save(r.Context(), record); err != nil {
@@ -591,11 +1347,33 @@ func (s *Server) handleCreateADR(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Location", fmt.Sprintf("/api/adr/%d", record.Number))
 	w.WriteHeader(http.StatusCreated)
-	if err := json.NewEncoder(w).Encode(toDetailResponse(*record)); err != nil {
+	if err := json.NewEncoder(w).Encode(s.detailResponse(*record)); err != nil {
 		log.Printf("error encoding create response: %v", err)
 	}
 }
 
+// normalizeSectionValues checks section values (keyed by
+// TemplateSectionDef.Key) the way both the create form and the PATCH
+// endpoints accept them: with a scope store, vocabulary fields may only use
+// its values, and are rewritten in its spelling.
+func (s *Server) normalizeSectionValues(defs []adr.TemplateSectionDef, values map[string]string) error {
+	if s.scopeStore == nil {
+		return nil
+	}
+	for _, d := range defs {
+		v, ok := values[d.Key]
+		if !d.Vocabulary || !ok || strings.TrimSpace(v) == "" {
+			continue
+		}
+		canonical, err := adr.CanonicalScopes(s.scopeStore.Scopes(), strings.Split(v, ","))
+		if err != nil {
+			return err
+		}
+		values[d.Key] = strings.Join(canonical, ", ")
+	}
+	return nil
+}
+
 func (s *Server) handleUpdateContent(w http.ResponseWriter, r *http.Request) {
 	if s.contentUpdater == nil {
 		http.Error(w, "content updates not supported", http.StatusNotImplemented)
@@ -618,6 +1396,9 @@ func (s *Server) handleUpdateContent(w http.ResponseWriter, r *http.Request) {
 	r.Body = http.MaxBytesReader(w, r.Body, 65536)
 	var body struct {
 		Content string `json:"content"`
+		// Rename renames the file to match the (possibly changed) title and
+		// rewrites inbound links, as `adr rename` does.
+		Rename bool `json:"rename,omitempty"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		http.Error(w, "invalid request body", http.StatusBadRequest)
@@ -629,6 +1410,11 @@ func (s *Server) handleUpdateContent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if body.Rename && s.renamer == nil {
+		http.Error(w, "rename not supported", http.StatusNotImplemented)
+		return
+	}
+
 	// Validate heading number matches URL number
 	meta := adr.ExtractMetadata(body.Content)
 	if meta.Number > 0 && meta.Number != number {
@@ -636,18 +1422,36 @@ func (s *Server) handleUpdateContent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	record, err := s.contentUpdater.UpdateContent(r.Context(), number, body.Content)
-	if err != nil {
-		if errors.Is(err, adr.ErrNotFound) {
-			http.Error(w, "ADR not found", http.StatusNotFound)
+	var record *adr.ADR
+	if body.Rename {
+		record, err = s.renamer.UpdateContentAndRename(r.Context(), number, body.Content)
+		if err != nil {
+			switch {
+			case errors.Is(err, adr.ErrNotFound):
+				http.Error(w, "ADR not found", http.StatusNotFound)
+			case errors.Is(err, adr.ErrConflict):
+				http.Error(w, "an ADR file with the new name already exists", http.StatusConflict)
+			case errors.Is(err, adr.ErrInvalidRecord):
+				http.Error(w, "title cannot be used as a filename", http.StatusBadRequest)
+			default:
+				http.Error(w, "failed to rename ADR", http.StatusInternalServerError)
+			}
+			return
+		}
+	} else {
+		record, err = s.contentUpdater.UpdateContent(r.Context(), number, body.Content)
+		if err != nil {
+			if errors.Is(err, adr.ErrNotFound) {
+				http.Error(w, "ADR not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, "failed to update content", http.StatusInternalServerError)
 			return
 		}
-		http.Error(w, "failed to update content", http.StatusInternalServerError)
-		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(toDetailResponse(*record)); err != nil {
+	if err := json.NewEncoder(w).Encode(s.detailResponse(*record)); err != nil {
 		http.Error(w, "failed to encode response", http.StatusInternalServerError)
 	}
 }
@@ -659,6 +1463,151 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// assetRoute is the route, below /api, that handleGetAsset serves files from
+// the ADR directory at.
+const assetRoute = "/assets/"
+
+// assetBase returns the URL prefix the server's assets are served under: below
+// its root's routes for a named root, and its project's for a mounted project.
+func (s *Server) assetBase() string {
+	base := "/api"
+	if s.project != "" {
+		base += "/projects/" + s.project
+	}
+	if s.root != "" {
+		base += "/roots/" + s.root
+	}
+	return base + assetRoute
+}
+
+// maxAssetSize caps attachment uploads.
+const maxAssetSize = 10 << 20
+
+// assetContentTypes covers diagram sources the mime package doesn't know.
+var assetContentTypes = map[string]string{
+	".drawio": "application/vnd.jgraph.mxfile",
+	".puml":   "text/plain; charset=utf-8",
+	".mmd":    "text/plain; charset=utf-8",
+}
+
+// handleGetAsset serves a non-markdown file from the ADR directory. Files may
+// change while the server runs, so clients revalidate (ETag/Last-Modified)
+// rather than cache blindly. The sandboxing CSP keeps scripts in served SVG or
+// HTML from running with the app's origin.
+func (s *Server) handleGetAsset(w http.ResponseWriter, r *http.Request) {
+	if s.assets == nil {
+		http.Error(w, "assets not supported", http.StatusNotImplemented)
+		return
+	}
+
+	name := chi.URLParam(r, "*")
+	f, err := s.assets.OpenAsset(name)
+	if err != nil {
+		if errors.Is(err, adr.ErrNotFound) {
+			http.Error(w, "asset not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "failed to open asset", http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		http.Error(w, "failed to open asset", http.StatusInternalServerError)
+		return
+	}
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(f)
+		if err != nil {
+			http.Error(w, "failed to read asset", http.StatusInternalServerError)
+			return
+		}
+		content = bytes.NewReader(data)
+	}
+
+	ext := strings.ToLower(path.Ext(name))
+	ctype := assetContentTypes[ext]
+	if ctype == "" {
+		ctype = mime.TypeByExtension(ext)
+	}
+	if ctype == "" {
+		ctype = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", ctype)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; img-src 'self' data:; style-src 'unsafe-inline'; sandbox")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("ETag", fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()))
+	http.ServeContent(w, r, path.Base(name), info.ModTime(), content)
+}
+
+type assetResponse struct {
+	Path     string `json:"path"`
+	URL      string `json:"url"`
+	Markdown string `json:"markdown"`
+}
+
+// handleUploadAsset stores the multipart "file" field as an attachment of the
+// ADR and returns its path relative to the ADR's file, ready to link.
+func (s *Server) handleUploadAsset(w http.ResponseWriter, r *http.Request) {
+	if s.repo == nil {
+		http.Error(w, "repository not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	if s.assets == nil {
+		http.Error(w, "assets not supported", http.StatusNotImplemented)
+		return
+	}
+
+	number, err := strconv.Atoi(chi.URLParam(r, "number"))
+	if err != nil || number <= 0 {
+		http.Error(w, "invalid ADR number", http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxAssetSize)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "attachment too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "expected a multipart form with a file field", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	rel, err := s.assets.SaveAsset(r.Context(), number, header.Filename, file)
+	if err != nil {
+		switch {
+		case errors.Is(err, adr.ErrNotFound):
+			http.Error(w, "ADR not found", http.StatusNotFound)
+		case errors.Is(err, adr.ErrInvalidAsset):
+			http.Error(w, "attachments need a non-markdown file extension", http.StatusBadRequest)
+		case errors.Is(err, adr.ErrConflict):
+			http.Error(w, "an attachment with this name already exists", http.StatusConflict)
+		default:
+			http.Error(w, "failed to store attachment", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	label := strings.TrimSuffix(path.Base(header.Filename), path.Ext(header.Filename))
+	label = strings.NewReplacer("[", `\[`, "]", `\]`).Replace(label)
+	snippet := fmt.Sprintf("[%s](%s)", label, rel)
+	if strings.HasPrefix(mime.TypeByExtension(path.Ext(rel)), "image/") {
+		snippet = "!" + snippet
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(assetResponse{Path: rel, URL: s.linkRewriter(number)(rel), Markdown: snippet}); err != nil {
+		log.Printf("error encoding asset response: %v", err)
+	}
+}
+
 // spaHandler returns an http.HandlerFunc that serves static files from the
 // given fs.FS and falls back to index.html for unknown paths (SPA routing).
 func spaHandler(frontend fs.FS) http.HandlerFunc {
@@ -1,11 +1,15 @@
 package web_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"testing/fstest"
@@ -149,6 +153,39 @@ func TestListADRs_EmptyList(t *testing.T) {
 	assert.Equal(t, "[]", trimNewline(rec.Body.String()))
 }
 
+func TestListADRs_ArchivedOnRequest(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "archive"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "archive", "0001-use-perl.md"), []byte("# 1. Use Perl\n\n## Status\n\nDeprecated\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0002-use-go.md"), []byte("# 2. Use Go\n\n## Status\n\nAccepted\n"), 0o644))
+	repo := adr.NewFileRepository(dir)
+	srv := web.NewServer(repo, web.WithArchiveLister(repo))
+
+	list := func(url string) []map[string]interface{} {
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var body []map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return body
+	}
+	body := list("/api/adr")
+	require.Len(t, body, 1)
+	assert.Equal(t, float64(2), body[0]["number"])
+	assert.Nil(t, body[0]["archived"])
+	body = list("/api/adr?archived=true")
+	require.Len(t, body, 2)
+	assert.Equal(t, true, body[0]["archived"])
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/adr/1", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var detail map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &detail))
+	assert.Equal(t, "Use Perl", detail["title"])
+	assert.Equal(t, true, detail["archived"])
+}
+
 func TestListADRs_RepoError(t *testing.T) {
 	repo := &mockRepo{err: fmt.Errorf("disk error")}
 	srv := web.NewServer(repo)
@@ -204,6 +241,29 @@ func TestGetADR_ReturnsADRWithContent(t *testing.T) {
 	assert.Equal(t, "# 1. Use Go\n\n## Status\n\nAccepted\n", body["content"])
 }
 
+func TestGetADR_IncludesSections(t *testing.T) {
+	repo := &mockRepo{
+		getADR: &adr.ADR{
+			Number:  1,
+			Content: "# 1. Use Go\n\n## Context\n\nWhy.\n\n### Forces\n\nSpeed.\n\n## Decision\n\nGo.\n",
+		},
+	}
+	srv := web.NewServer(repo, web.WithConfig(&adr.Config{Directory: t.TempDir(), Template: "nygard"}))
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/adr/1", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body struct {
+		Sections []adr.Section `json:"sections"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.Sections, 3)
+	assert.Equal(t, adr.Section{Level: 2, Heading: "Context", Key: "context", Body: "Why."}, body.Sections[0])
+	assert.Equal(t, adr.Section{Level: 3, Heading: "Forces", Body: "Speed."}, body.Sections[1])
+	assert.Equal(t, adr.Section{Level: 2, Heading: "Decision", Key: "decision", Body: "Go."}, body.Sections[2])
+}
+
 func TestGetADR_NotFound(t *testing.T) {
 	repo := &mockRepo{
 		getErr: fmt.Errorf("ADR 0099: %w", adr.ErrNotFound),
@@ -241,6 +301,285 @@ func TestGetADR_NilRepo(t *testing.T) {
 	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
 }
 
+func TestGetADRHTML_RendersSanitizedHTML(t *testing.T) {
+	repo := &mockRepo{
+		getADR: &adr.ADR{
+			Number: 2,
+			Title:  "Use PostgreSQL",
+			Content: "# 2. Use PostgreSQL\n\n## Status\n\nAccepted\n\nSupersedes [ADR-0001](0001-use-mysql.md)  \n\n" +
+				"## Context\n\nSee [the outcome](./0003-cache.md#decision) and <script>alert(1)</script>.\n",
+		},
+	}
+	srv := web.NewServer(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/adr/2/html", nil)
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	assert.Contains(t, body, `<h1 id="2-use-postgresql">2. Use PostgreSQL</h1>`)
+	assert.Contains(t, body, `<h2 id="context">Context</h2>`)
+	assert.Contains(t, body, `<a href="/adr/1">ADR-0001</a>`)
+	assert.Contains(t, body, `<a href="/adr/3#decision">the outcome</a>`)
+	assert.Contains(t, body, "&lt;script&gt;")
+	assert.NotContains(t, body, "<script>")
+}
+
+func TestGetADRHTML_Section(t *testing.T) {
+	repo := &mockRepo{
+		getADR: &adr.ADR{
+			Number:  1,
+			Content: "# 1. Use Go\n\n## Status\n\nAccepted\n\n## Context and Problem Statement\n\nWhy.\n\n## Decision\n\nGo.\n",
+		},
+	}
+	srv := web.NewServer(repo)
+
+	for _, section := range []string{"context-and-problem-statement", "Context%20and%20Problem%20Statement"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/adr/1/html?section="+section, nil)
+		rec := httptest.NewRecorder()
+
+		srv.Handler().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "<h2 id=\"context-and-problem-statement\">Context and Problem Statement</h2>\n<p>Why.</p>\n", rec.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/adr/1/html?section=consequences", nil)
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "section not found")
+}
+
+func TestGetADRHTML_RewritesAssetLinks(t *testing.T) {
+	repo := &mockRepo{getADR: &adr.ADR{Number: 12, Content: "![flow](assets/0012-flow.png) [source](./diagrams/flow.drawio) [web](https://example.com/x.png)\n"}}
+
+	rec := httptest.NewRecorder()
+	web.NewServer(repo, web.WithAssetStore(adr.NewFileRepository(t.TempDir()))).Handler().
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/adr/12/html", nil))
+
+	assert.Contains(t, rec.Body.String(), `<img src="/api/assets/assets/0012-flow.png" alt="flow">`)
+	assert.Contains(t, rec.Body.String(), `<a href="/api/assets/diagrams/flow.drawio">source</a>`)
+	assert.Contains(t, rec.Body.String(), `<a href="https://example.com/x.png">web</a>`)
+}
+
+func TestGetADRHTML_RewritesLinksIntoSubdirectories(t *testing.T) {
+	repo := &mockRepo{getADR: &adr.ADR{Number: 2, Content: "[up](../0001-use-go.md) [down](platform/0003-use-kafka.md#status) [web](https://example.com/0004-x.md)\n"}}
+
+	rec := httptest.NewRecorder()
+	web.NewServer(repo).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/adr/2/html", nil))
+
+	assert.Contains(t, rec.Body.String(), `<a href="/adr/1">up</a>`)
+	assert.Contains(t, rec.Body.String(), `<a href="/adr/3#status">down</a>`)
+	assert.Contains(t, rec.Body.String(), `<a href="https://example.com/0004-x.md">web</a>`)
+}
+
+func TestRoots_QualifiedIDs(t *testing.T) {
+	dir := t.TempDir()
+	project := &adr.Config{
+		Directory: filepath.Join(dir, "docs", "adr"), Template: "nygard",
+		Roots: []adr.RootDef{{Name: "payments", Directory: filepath.Join(dir, "services", "payments", "docs", "adr")}},
+	}
+	payments, err := project.Root("payments")
+	require.NoError(t, err)
+	files := map[string]string{
+		filepath.Join(project.Directory, "0001-use-go.md"): "# 1. Use Go\n\n## Status\n\nAccepted\n\n" +
+			"Relates to [payments:ADR-0001](../../services/payments/docs/adr/0001-use-stripe.md)\n",
+		filepath.Join(payments.Directory, "0001-use-stripe.md"): "# 1. Use Stripe\n\n## Status\n\nProposed\n\n" +
+			"Relates to [ADR-0001](../../../../docs/adr/0001-use-go.md) and [ADR-0002](0002-use-adyen.md)\n",
+	}
+	for path, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	paymentsSrv := web.NewServer(payments.Repository(), web.WithConfig(payments))
+	srv := web.NewServer(project.Repository(), web.WithConfig(project), web.WithRoot("payments", paymentsSrv))
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	var list []map[string]interface{}
+	require.NoError(t, json.Unmarshal(get("/api/adr").Body.Bytes(), &list))
+	require.Len(t, list, 2)
+	assert.Nil(t, list[0]["root"])
+	assert.Equal(t, "payments", list[1]["root"])
+	assert.Equal(t, "Use Stripe", list[1]["title"])
+
+	rec := get("/api/adr/payments:1")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var detail map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &detail))
+	assert.Equal(t, "Use Stripe", detail["title"])
+	assert.Equal(t, "payments", detail["root"])
+
+	assert.Contains(t, get("/api/adr/1/html").Body.String(), `<a href="/adr/payments:1">payments:ADR-0001</a>`)
+	html := get("/api/adr/payments:1/html").Body.String()
+	assert.Contains(t, html, `<a href="/adr/1">ADR-0001</a>`)
+	assert.Contains(t, html, `<a href="/adr/payments:2">ADR-0002</a>`)
+
+	assert.Equal(t, http.StatusNotFound, get("/api/adr/shipping:1").Code)
+	var cfg map[string]interface{}
+	require.NoError(t, json.Unmarshal(get("/api/config").Body.Bytes(), &cfg))
+	assert.Equal(t, []interface{}{"payments"}, cfg["roots"])
+}
+
+func TestProjects_Catalog(t *testing.T) {
+	mount := func(name string, files map[string]string) *adr.Config {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".adr.json"),
+			[]byte(`{"version": "1", "directory": "docs/adr", "template": "nygard"}`), 0o644))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "docs", "adr"), 0o755))
+		for file, content := range files {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "docs", "adr", file), []byte(content), 0o644))
+		}
+		cfg, err := adr.Mount(name, dir)
+		require.NoError(t, err)
+		return cfg
+	}
+	shop := mount("shop", map[string]string{
+		"0001-use-go.md": "# 1. Use Go\n\n## Status\n\nAccepted\n\n![diagram](diagram.png)\n",
+	})
+	billing := mount("billing", map[string]string{
+		"0001-use-postgres.md": "# 1. Use Postgres\n\n## Status\n\nAccepted\n",
+	})
+	project := func(cfg *adr.Config) *web.Server {
+		repo := cfg.Repository()
+		return web.NewServer(repo, web.WithConfig(cfg), web.WithRelator(repo), web.WithAssetStore(repo))
+	}
+	srv := web.NewServer(nil, web.WithProject("shop", project(shop)), web.WithProject("billing", project(billing)))
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	var list []map[string]interface{}
+	require.NoError(t, json.Unmarshal(serve(http.MethodGet, "/api/catalog", "").Body.Bytes(), &list))
+	require.Len(t, list, 2)
+	assert.Equal(t, "billing", list[0]["project"])
+	assert.Equal(t, "shop", list[1]["project"])
+	require.NoError(t, json.Unmarshal(serve(http.MethodGet, "/api/catalog?q=postgres", "").Body.Bytes(), &list))
+	require.Len(t, list, 1)
+	assert.Equal(t, "Use Postgres", list[0]["title"])
+
+	var detail map[string]interface{}
+	require.NoError(t, json.Unmarshal(serve(http.MethodGet, "/api/projects/shop/adr/1", "").Body.Bytes(), &detail))
+	assert.Equal(t, "Use Go", detail["title"])
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/api/projects/shipping/adr/1", "").Code)
+	assert.Equal(t, http.StatusServiceUnavailable, serve(http.MethodGet, "/api/adr/1", "").Code)
+
+	var cfg map[string]interface{}
+	require.NoError(t, json.Unmarshal(serve(http.MethodGet, "/api/config", "").Body.Bytes(), &cfg))
+	assert.Equal(t, []interface{}{"billing", "shop"}, cfg["projects"])
+	require.NoError(t, json.Unmarshal(serve(http.MethodGet, "/api/projects/billing/config", "").Body.Bytes(), &cfg))
+	assert.Equal(t, "billing", cfg["project"])
+	assert.Equal(t, "nygard", cfg["template"])
+
+	rec := serve(http.MethodPost, "/api/projects/shop/adr/1/relations", `{"relatedTo": 1, "project": "billing"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), "billing/ADR-0001")
+	assert.Equal(t, http.StatusNotFound,
+		serve(http.MethodPost, "/api/projects/shop/adr/1/relations", `{"relatedTo": 1, "project": "shipping"}`).Code)
+
+	html := serve(http.MethodGet, "/api/projects/shop/adr/1/html", "").Body.String()
+	assert.Contains(t, html, `<a href="/projects/billing/adr/1">billing/ADR-0001</a>`)
+	assert.Contains(t, html, `src="/api/projects/shop/assets/diagram.png"`)
+	html = serve(http.MethodGet, "/api/projects/billing/adr/1/html", "").Body.String()
+	assert.Contains(t, html, `<a href="/projects/shop/adr/1">shop/ADR-0001</a>`)
+}
+
+func TestProjects_RelateToNamedRootOfOtherProject(t *testing.T) {
+	shopDir, billingDir := t.TempDir(), t.TempDir()
+	for path, content := range map[string]string{
+		filepath.Join(shopDir, ".adr.json"):                                     `{"version": "1", "directory": "docs/adr", "template": "nygard"}`,
+		filepath.Join(shopDir, "docs/adr/0001-use-go.md"):                       "# 1. Use Go\n\n## Status\n\nAccepted\n",
+		filepath.Join(billingDir, ".adr.json"):                                  `{"version": "1", "template": "nygard", "roots": [{"name": "invoices", "directory": "services/invoices/docs/adr"}, {"name": "refunds", "directory": "services/refunds/docs/adr"}]}`,
+		filepath.Join(billingDir, "services/invoices/docs/adr/0001-use-pdf.md"): "# 1. Use PDF\n\n## Status\n\nAccepted\n",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	shop, err := adr.Mount("shop", shopDir)
+	require.NoError(t, err)
+	billing, err := adr.Mount("billing", billingDir)
+	require.NoError(t, err)
+	adr.Federate(shop, billing)
+	invoices, err := billing.Root("invoices")
+	require.NoError(t, err)
+
+	rootServer := func(cfg *adr.Config) *web.Server {
+		repo := cfg.Repository()
+		return web.NewServer(repo, web.WithConfig(cfg), web.WithRelator(repo))
+	}
+	srv := web.NewServer(nil,
+		web.WithProject("shop", rootServer(shop)),
+		web.WithProject("billing", web.NewServer(nil, web.WithConfig(billing), web.WithRoot("invoices", rootServer(invoices)))))
+	relate := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := relate("/api/projects/shop/adr/1/relations", `{"relatedTo": 1, "project": "billing", "root": "invoices"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	link := shop.LinkTo("0001-use-go.md", invoices, 1, "0001-use-pdf.md")
+	assert.Equal(t, "billing/invoices:ADR-0001", link.Label)
+	assert.Contains(t, rec.Body.String(), link.Label)
+	content, err := os.ReadFile(filepath.Join(invoices.Directory, "0001-use-pdf.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Relates to ["+invoices.LinkTo("0001-use-pdf.md", shop, 1, "0001-use-go.md").Label+"](")
+
+	assert.Equal(t, http.StatusNotFound,
+		relate("/api/projects/shop/adr/1/relations", `{"relatedTo": 1, "project": "billing", "root": "shipping"}`).Code)
+	assert.Equal(t, http.StatusBadRequest,
+		relate("/api/projects/shop/adr/1/relations", `{"relatedTo": 1, "project": "billing"}`).Code, "billing has several roots and no default")
+	// A server without a config knows of no other root.
+	repo := shop.Repository()
+	bare := web.NewServer(repo, web.WithRelator(repo))
+	req := httptest.NewRequest(http.MethodPost, "/api/adr/1/relations", strings.NewReader(`{"relatedTo": 1, "root": "invoices"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	bare.Handler().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestGetADRHTML_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		repo adr.Repository
+		path string
+		want int
+	}{
+		{"nil repo", nil, "/api/adr/1/html", http.StatusServiceUnavailable},
+		{"invalid number", &mockRepo{}, "/api/adr/abc/html", http.StatusBadRequest},
+		{"not found", &mockRepo{getErr: fmt.Errorf("ADR 0099: %w", adr.ErrNotFound)}, "/api/adr/99/html", http.StatusNotFound},
+		{"repository error", &mockRepo{getErr: fmt.Errorf("disk on fire")}, "/api/adr/1/html", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := web.NewServer(tt.repo)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rec := httptest.NewRecorder()
+
+			srv.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
+
 // --- GET /api/adr/statuses ---
 
 func TestStatuses_ReturnsAllStatuses(t *testing.T) {
@@ -992,6 +1331,110 @@ func TestUpdateContent_NoUpdater(t *testing.T) {
 	assert.Equal(t, http.StatusNotImplemented, rec.Code)
 }
 
+var _ web.Renamer = (*mockRenamer)(nil)
+
+type mockRenamer struct {
+	result  *adr.ADR
+	err     error
+	called  bool
+	content string
+}
+
+func (m *mockRenamer) UpdateContentAndRename(_ context.Context, _ int, content string) (*adr.ADR, error) {
+	m.called = true
+	m.content = content
+	return m.result, m.err
+}
+
+func TestUpdateContent_RenameTriggersRenamerWithNewContent(t *testing.T) {
+	updater := &mockContentUpdater{result: &adr.ADR{Number: 1, Title: "Use Go Modules", Content: "# 1. Use Go Modules\n"}}
+	renamer := &mockRenamer{result: &adr.ADR{Number: 1, Title: "Use Go Modules", Content: "# 1. Use Go Modules\n"}}
+	srv := web.NewServer(&mockRepo{}, web.WithContentUpdater(updater), web.WithRenamer(renamer))
+
+	body := strings.NewReader(`{"content":"# 1. Use Go Modules\n","rename":true}`)
+	req := httptest.NewRequest(http.MethodPut, "/api/adr/1", body)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.False(t, updater.called, "the renamer writes the content itself")
+	assert.True(t, renamer.called)
+	assert.Equal(t, "# 1. Use Go Modules\n", renamer.content)
+}
+
+func TestUpdateContent_WithoutRenameFlagSkipsRenamer(t *testing.T) {
+	updater := &mockContentUpdater{result: &adr.ADR{Number: 1, Title: "T"}}
+	renamer := &mockRenamer{}
+	srv := web.NewServer(&mockRepo{}, web.WithContentUpdater(updater), web.WithRenamer(renamer))
+
+	body := strings.NewReader(`{"content":"# 1. T\n"}`)
+	req := httptest.NewRequest(http.MethodPut, "/api/adr/1", body)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.False(t, renamer.called)
+}
+
+func TestUpdateContent_RenameWithoutRenamer_NotImplemented(t *testing.T) {
+	updater := &mockContentUpdater{}
+	srv := web.NewServer(&mockRepo{}, web.WithContentUpdater(updater))
+
+	body := strings.NewReader(`{"content":"# 1. T\n","rename":true}`)
+	req := httptest.NewRequest(http.MethodPut, "/api/adr/1", body)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+	assert.False(t, updater.called, "content must not be written when the rename can't follow")
+}
+
+func TestUpdateContent_RenameConflict(t *testing.T) {
+	updater := &mockContentUpdater{result: &adr.ADR{Number: 1, Title: "T"}}
+	renamer := &mockRenamer{err: fmt.Errorf("file: %w", adr.ErrConflict)}
+	srv := web.NewServer(&mockRepo{}, web.WithContentUpdater(updater), web.WithRenamer(renamer))
+
+	body := strings.NewReader(`{"content":"# 1. T\n","rename":true}`)
+	req := httptest.NewRequest(http.MethodPut, "/api/adr/1", body)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.False(t, updater.called, "content must not be written when the rename is rejected")
+}
+
+func TestCreateADR_MissingTemplateFile(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *adr.Config
+		want int
+	}{
+		{"built-in falls back to the embedded copy", &adr.Config{Template: "nygard", TemplateFile: "template.md"}, http.StatusCreated},
+		{"project template", &adr.Config{Template: "rfc", TemplateFile: "rfc.md"}, http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Version, tt.cfg.Directory = "1", t.TempDir()
+			srv := web.NewServer(&mockRepo{nextNum: 1}, web.WithConfig(tt.cfg))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/adr", strings.NewReader(`{"title":"Use Go"}`))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.want, rec.Code, rec.Body.String())
+		})
+	}
+}
+
 // --- POST /api/adr with sections ---
 
 func TestCreateADR_WithSections(t *testing.T) {
@@ -1069,6 +1512,224 @@ func TestGetTemplateSections_ReturnsNygardSections(t *testing.T) {
 	assert.Equal(t, "context", sections[0]["key"])
 }
 
+func TestGetTemplateSections_ProjectTemplate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "rfc.md"),
+		[]byte("# Title\n\nReviewer:\n\n## Problem\n\n{Why?}\n"), 0o644))
+	cfg := &adr.Config{
+		Version: "1", Directory: dir, Template: "rfc",
+		Templates: map[string]adr.TemplateDef{"rfc": {File: "rfc.md"}},
+	}
+	srv := web.NewServer(nil, web.WithConfig(cfg))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/template-sections", nil)
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var sections []adr.TemplateSectionDef
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &sections))
+	assert.Equal(t, []adr.TemplateSectionDef{
+		{Key: "reviewer", Heading: "Reviewer", Kind: "meta"},
+		{Key: "problem", Heading: "Problem", Kind: "h2", Placeholder: "Why?"},
+	}, sections)
+}
+
+func TestCreateADR_ProjectTemplate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "rfc.md"),
+		[]byte("# Title\n\nDate:\n\n## Status\n\nProposed\n\n## Problem\n\n{Why?}\n"), 0o644))
+	repo := &mockRepo{nextNum: 1}
+	cfg := &adr.Config{
+		Version: "1", Directory: dir, Template: "rfc",
+		Templates: map[string]adr.TemplateDef{"rfc": {File: "rfc.md"}},
+	}
+	srv := web.NewServer(repo, web.WithConfig(cfg))
+
+	body := strings.NewReader(`{"title":"Use Go","sections":{"problem":"Builds are slow."}}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/adr", body)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Contains(t, resp["content"], "# 1. Use Go")
+	assert.Contains(t, resp["content"], "## Problem\n\nBuilds are slow.")
+}
+
+func TestCreateADR_UsesCustomizedTemplateFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "template.md")
+	require.NoError(t, os.WriteFile(path,
+		[]byte("# Title\n\nDate:\n\n## Status\n\nProposed\n\n## Context\n\nWhy?\n\nACME legal footer\n"), 0o644))
+	repo := &mockRepo{nextNum: 1}
+	cfg := &adr.Config{Version: "1", Directory: dir, Template: "nygard", TemplateFile: "template.md"}
+	srv := web.NewServer(repo, web.WithConfig(cfg), web.WithTemplateProvider(adr.NewTemplateLoader(cfg)))
+
+	create := func() string {
+		req := httptest.NewRequest(http.MethodPost, "/api/adr", strings.NewReader(`{"title":"Use Go"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return resp["content"].(string)
+	}
+
+	assert.Contains(t, create(), "ACME legal footer")
+
+	// Edits to the template file are picked up without a restart.
+	require.NoError(t, os.WriteFile(path,
+		[]byte("# Title\n\nDate:\n\n## Status\n\nProposed\n\n## Context\n\nWhy?\n\nACME legal footer v2\n"), 0o644))
+	later := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(path, later, later))
+	assert.Contains(t, create(), "ACME legal footer v2")
+}
+
+func TestCreateADR_TemplateVariables(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "rfc.md"),
+		[]byte("{{/* adr:template */}}\n# Title\n\nDate:\nAuthor: {{.Author}}\nTeam: {{.Vars.team}}\n{{if .Scopes}}Areas: {{join .Scopes \"/\"}}\n{{end}}\n## Status\n\nProposed\n\n## Problem\n\n{Why?}\n"), 0o644))
+	repo := &mockRepo{nextNum: 1}
+	cfg := &adr.Config{
+		Version: "1", Directory: dir, Template: "rfc",
+		Templates: map[string]adr.TemplateDef{"rfc": {File: "rfc.md", Sections: []adr.TemplateSectionDef{
+			{Key: "problem", Heading: "Problem", Kind: "h2"},
+			{Key: "areas", Heading: "Areas", Kind: "meta", Vocabulary: true, Optional: true},
+		}}},
+	}
+	srv := web.NewServer(repo, web.WithConfig(cfg), web.WithAuthorHeader("X-Forwarded-User"))
+
+	body := strings.NewReader(`{"title":"Use Go","vars":{"team":"payments"},"sections":{"problem":"Slow.","areas":"Backend, Infra"}}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/adr", body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Forwarded-User", "ada")
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Contains(t, resp["content"], "Author: ada\nTeam: payments\n")
+	assert.Contains(t, resp["content"], "Areas: Backend, Infra\n")
+}
+
+func TestCreateADR_AuthorHeaderIgnoredWhenNotConfigured(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "rfc.md"),
+		[]byte("{{/* adr:template */}}\n# Title\n\nDate:\nAuthor: [{{.Author}}]\n\n## Status\n\nProposed\n"), 0o644))
+	repo := &mockRepo{nextNum: 1}
+	cfg := &adr.Config{Version: "1", Directory: dir, Template: "rfc",
+		Templates: map[string]adr.TemplateDef{"rfc": {File: "rfc.md"}}}
+	srv := web.NewServer(repo, web.WithConfig(cfg))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/adr", strings.NewReader(`{"title":"Use Go"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Forwarded-User", "mallory")
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Contains(t, resp["content"], "Author: []\n")
+}
+
+// --- GET /api/templates ---
+
+func TestGetTemplates_ListsChoicesWithSections(t *testing.T) {
+	cfg := &adr.Config{Version: "1", Directory: t.TempDir(), Template: "nygard-scoped"}
+	srv := web.NewServer(nil, web.WithConfig(cfg))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/templates", nil)
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var templates []struct {
+		Name     string                   `json:"name"`
+		Default  bool                     `json:"default"`
+		Sections []adr.TemplateSectionDef `json:"sections"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &templates))
+	require.Len(t, templates, 4)
+	assert.Equal(t, "nygard-scoped", templates[0].Name)
+	assert.True(t, templates[0].Default)
+	assert.Equal(t, "scope", templates[0].Sections[0].Key)
+	assert.Equal(t, "nygard", templates[1].Name)
+	assert.False(t, templates[1].Default)
+}
+
+func TestGetTemplates_NoConfig(t *testing.T) {
+	srv := web.NewServer(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/templates", nil)
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestCreateADR_WithTemplate(t *testing.T) {
+	repo := &mockRepo{nextNum: 1}
+	cfg := &adr.Config{Version: "1", Directory: "docs/adr", Template: "nygard"}
+	srv := web.NewServer(repo, web.WithConfig(cfg))
+
+	body := strings.NewReader(`{"title":"Use Kafka","template":"madr-minimal","sections":{"considered-options":"* Kafka\n* NATS"}}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/adr", body)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Contains(t, resp["content"], "## Considered Options\n\n* Kafka\n* NATS")
+}
+
+func TestCreateADR_WritesFrontmatterFields(t *testing.T) {
+	repo := &mockRepo{nextNum: 1}
+	cfg := &adr.Config{Version: "1", Directory: "docs/adr", Template: "madr-full"}
+	srv := web.NewServer(repo, web.WithConfig(cfg))
+
+	body := strings.NewReader(`{"title":"Use Kafka","sections":{"decision-makers":"Alice, Bob","informed":"Everyone"}}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/adr", body)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Contains(t, repo.savedADR.Content, "\ndecision-makers:\n  - Alice\n  - Bob\n")
+	assert.Contains(t, repo.savedADR.Content, "\ninformed: Everyone\n---\n")
+	assert.Equal(t, []string{"Alice", "Bob"}, adr.ExtractMetaFields(repo.savedADR.Content)["decision-makers"])
+}
+
+func TestCreateADR_UnknownTemplate(t *testing.T) {
+	repo := &mockRepo{nextNum: 1}
+	cfg := &adr.Config{Version: "1", Directory: "docs/adr", Template: "nygard"}
+	srv := web.NewServer(repo, web.WithConfig(cfg))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/adr", strings.NewReader(`{"title":"X","template":"nope"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.False(t, repo.saveCalled)
+}
+
 func TestGetTemplateSections_NoConfig(t *testing.T) {
 	srv := web.NewServer(nil)
 
@@ -1272,6 +1933,39 @@ func TestGetMetaFields_ReturnsFacetsWithVocabularyValues(t *testing.T) {
 	assert.Equal(t, []interface{}{"backend", "api"}, scope["values"])
 }
 
+func TestGetMetaFields_PerProject(t *testing.T) {
+	mount := func(name, cfgJSON string, files map[string]string) *web.Server {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, adr.ConfigFileName), []byte(cfgJSON), 0o644))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "docs", "adr"), 0o755))
+		for file, content := range files {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "docs", "adr", file), []byte(content), 0o644))
+		}
+		cfg, err := adr.Mount(name, dir)
+		require.NoError(t, err)
+		return web.NewServer(cfg.Repository(), web.WithConfig(cfg))
+	}
+	rfc := mount("rfc", `{"version": "1", "directory": "docs/adr", "template": "rfc", "templates": {"rfc": {"file": "rfc.md"}}}`,
+		map[string]string{"rfc.md": "# Title\n\nReviewer: {who signed off}\n\n## Status\n\nProposed\n"})
+	plain := mount("plain", `{"version": "1", "directory": "docs/adr", "template": "nygard"}`, nil)
+	srv := web.NewServer(nil, web.WithProject("rfc", rfc), web.WithProject("plain", plain))
+	keys := func(path string) []string {
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var fields []map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &fields))
+		var keys []string
+		for _, f := range fields {
+			keys = append(keys, f["key"].(string))
+		}
+		return keys
+	}
+
+	assert.Contains(t, keys("/api/projects/rfc/meta-fields"), "reviewer")
+	assert.NotContains(t, keys("/api/projects/plain/meta-fields"), "reviewer")
+	assert.NotContains(t, keys("/api/meta-fields"), "reviewer")
+}
+
 func TestGetMetaFields_NoScopeStore_GracefulEmptyValues(t *testing.T) {
 	srv := web.NewServer(nil) // no scope store
 
@@ -1290,3 +1984,361 @@ func TestGetMetaFields_NoScopeStore_GracefulEmptyValues(t *testing.T) {
 		}
 	}
 }
+
+func newAssetServer(t *testing.T) (*web.Server, string) {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0012-pick-a-queue.md"), []byte("# 12. Pick a queue\n"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "assets"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "assets", "0012-flow.svg"), []byte("<svg/>"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "flow.puml"), []byte("@startuml"), 0o644))
+	repo := adr.NewFileRepository(dir)
+	return web.NewServer(repo, web.WithAssetStore(repo)), dir
+}
+
+func TestGetAsset_ServesFileWithHeaders(t *testing.T) {
+	srv, _ := newAssetServer(t)
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/assets/assets/0012-flow.svg", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "<svg/>", rec.Body.String())
+	assert.Equal(t, "image/svg+xml", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+	assert.Contains(t, rec.Header().Get("Content-Security-Policy"), "sandbox")
+	assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.NotEmpty(t, rec.Header().Get("Last-Modified"))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/assets/assets/0012-flow.svg", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/assets/flow.puml", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+}
+
+func TestGetAsset_RefusesMarkdownHiddenAndTraversal(t *testing.T) {
+	srv, _ := newAssetServer(t)
+
+	for _, target := range []string{
+		"/api/assets/0012-pick-a-queue.md",
+		"/api/assets/.adr-journal.json",
+		"/api/assets/..%2F..%2Fetc%2Fpasswd",
+		"/api/assets/assets/%2e%2e/%2e%2e/secret.png",
+		"/api/assets/missing.png",
+	} {
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code, target)
+	}
+}
+
+func TestGetAsset_NotSupported(t *testing.T) {
+	rec := httptest.NewRecorder()
+	web.NewServer(&mockRepo{}).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/assets/x.png", nil))
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+}
+
+func uploadRequest(t *testing.T, target, field, filename, content string) *http.Request {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fw, err := mw.CreateFormFile(field, filename)
+	require.NoError(t, err)
+	_, err = fw.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+	req := httptest.NewRequest(http.MethodPost, target, &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestUploadAsset_StoresAttachment(t *testing.T) {
+	srv, dir := newAssetServer(t)
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, uploadRequest(t, "/api/adr/12/assets", "file", "Sequence.png", "png"))
+
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "assets/0012-sequence.png", body["path"])
+	assert.Equal(t, "/api/assets/assets/0012-sequence.png", body["url"])
+	assert.Equal(t, "![Sequence](assets/0012-sequence.png)", body["markdown"])
+	data, err := os.ReadFile(filepath.Join(dir, "assets", "0012-sequence.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "png", string(data))
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, uploadRequest(t, "/api/adr/12/assets", "file", "flow.drawio", "<mxfile/>"))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "[flow](assets/0012-flow.drawio)", body["markdown"])
+}
+
+func TestAssets_ADRInSubdirectory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "platform"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "platform", "0003-use-kafka.md"),
+		[]byte("# 3. Use Kafka\n\n![topology](../assets/0003-topology.png) [outside](../../README.txt)\n"), 0o644))
+	cfg := &adr.Config{Directory: dir, Template: "nygard", Recursive: true}
+	repo := cfg.Repository()
+	srv := web.NewServer(repo, web.WithConfig(cfg), web.WithAssetStore(repo))
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, uploadRequest(t, "/api/adr/3/assets", "file", "Sequence.png", "png"))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "../assets/0003-sequence.png", body["path"])
+	assert.Equal(t, "/api/assets/assets/0003-sequence.png", body["url"])
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/adr/3/html", nil))
+	assert.Contains(t, rec.Body.String(), `<img src="/api/assets/assets/0003-topology.png" alt="topology">`)
+	assert.Contains(t, rec.Body.String(), `<a href="../../README.txt">outside</a>`)
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/adr/3", nil))
+	var detail struct{ Dir string }
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &detail))
+	assert.Equal(t, "platform", detail.Dir)
+}
+
+func TestAssets_NamedRoots(t *testing.T) {
+	dir := t.TempDir()
+	project := &adr.Config{
+		Directory: filepath.Join(dir, "docs", "adr"), Template: "nygard",
+		Roots: []adr.RootDef{{Name: "payments", Directory: filepath.Join(dir, "services", "payments", "docs", "adr")}},
+	}
+	payments, err := project.Root("payments")
+	require.NoError(t, err)
+	files := map[string]string{
+		filepath.Join(project.Directory, "0001-use-go.md"):           "# 1. Use Go\n\n![d](assets/0001-flow.png)\n",
+		filepath.Join(project.Directory, "assets", "0001-flow.png"):  "default",
+		filepath.Join(payments.Directory, "0001-use-stripe.md"):      "# 1. Use Stripe\n\n![d](assets/0001-flow.png)\n",
+		filepath.Join(payments.Directory, "assets", "0001-flow.png"): "payments",
+	}
+	for path, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	paymentsRepo := payments.Repository()
+	paymentsSrv := web.NewServer(paymentsRepo, web.WithConfig(payments), web.WithAssetStore(paymentsRepo))
+	repo := project.Repository()
+	srv := web.NewServer(repo, web.WithConfig(project), web.WithAssetStore(repo), web.WithRoot("payments", paymentsSrv))
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+	get := func(path string) string {
+		return serve(httptest.NewRequest(http.MethodGet, path, nil)).Body.String()
+	}
+
+	assert.Contains(t, get("/api/adr/1/html"), `src="/api/assets/assets/0001-flow.png"`)
+	assert.Contains(t, get("/api/adr/payments:1/html"), `src="/api/roots/payments/assets/assets/0001-flow.png"`)
+	assert.Equal(t, "default", get("/api/assets/assets/0001-flow.png"))
+	assert.Equal(t, "payments", get("/api/roots/payments/assets/assets/0001-flow.png"))
+	assert.Equal(t, http.StatusNotFound, serve(httptest.NewRequest(http.MethodGet, "/api/roots/billing/assets/x.png", nil)).Code)
+
+	rec := serve(uploadRequest(t, "/api/adr/payments:1/assets", "file", "Sequence.png", "png"))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "/api/roots/payments/assets/assets/0001-sequence.png", body["url"])
+	assert.Equal(t, "png", get(body["url"]))
+}
+
+func TestUploadAsset_Errors(t *testing.T) {
+	srv, _ := newAssetServer(t)
+
+	tests := []struct {
+		name string
+		req  *http.Request
+		want int
+	}{
+		{"unknown ADR", uploadRequest(t, "/api/adr/7/assets", "file", "flow.png", "png"), http.StatusNotFound},
+		{"invalid number", uploadRequest(t, "/api/adr/x/assets", "file", "flow.png", "png"), http.StatusBadRequest},
+		{"markdown", uploadRequest(t, "/api/adr/12/assets", "file", "notes.md", "# x"), http.StatusBadRequest},
+		{"missing field", uploadRequest(t, "/api/adr/12/assets", "upload", "flow.png", "png"), http.StatusBadRequest},
+		{"existing", uploadRequest(t, "/api/adr/12/assets", "file", "flow.svg", "<svg/>"), http.StatusConflict},
+		{"too large", uploadRequest(t, "/api/adr/12/assets", "file", "big.png", strings.Repeat("x", 10<<20+1)), http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(rec, tt.req)
+			assert.Equal(t, tt.want, rec.Code, rec.Body.String())
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	web.NewServer(&mockRepo{}).Handler().ServeHTTP(rec, uploadRequest(t, "/api/adr/12/assets", "file", "flow.png", "png"))
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+}
+
+func newPatchServer(t *testing.T, files map[string]string, opts ...web.ServerOption) (*web.Server, string) {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	repo := adr.NewFileRepository(dir)
+	cfg := &adr.Config{Version: "1", Directory: dir, Template: "nygard-scoped"}
+	opts = append([]web.ServerOption{web.WithConfig(cfg), web.WithContentUpdater(repo)}, opts...)
+	return web.NewServer(repo, opts...), dir
+}
+
+func patchRequest(target, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPatch, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+const patchNygardADR = "# 1. Use Go\n\nDate: 2024-01-01\n\nScope: Backend\n\n## Status\n\nAccepted\n\n## Context\n\nOld context.\n\n## Decision\n\nUse Go.\n\n## Consequences\n\nFaster builds.\n"
+
+func TestPatchSection_ReplacesOneSection(t *testing.T) {
+	srv, dir := newPatchServer(t, map[string]string{"0001-use-go.md": patchNygardADR})
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, patchRequest("/api/adr/1/sections/context", `{"body":"New context.\n\nSecond paragraph."}`))
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	data, err := os.ReadFile(filepath.Join(dir, "0001-use-go.md"))
+	require.NoError(t, err)
+	assert.Equal(t, strings.Replace(patchNygardADR, "Old context.", "New context.\n\nSecond paragraph.", 1), string(data))
+
+	var body struct {
+		Sections []adr.Section `json:"sections"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "New context.\n\nSecond paragraph.", body.Sections[1].Body)
+}
+
+func TestPatchSection_KeepsSubsections(t *testing.T) {
+	content := "---\nstatus: \"accepted\"\n---\n\n# 2. Use Chi\n\n## Decision Outcome\n\nChosen option: X.\n\n### Consequences\n\n* Good\n\n## More Information\n\nNone.\n"
+	srv, dir := newPatchServer(t, map[string]string{"0002-use-chi.md": content})
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, patchRequest("/api/adr/2/sections/decision-outcome", `{"body":"Chosen option: Y."}`))
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	data, err := os.ReadFile(filepath.Join(dir, "0002-use-chi.md"))
+	require.NoError(t, err)
+	assert.Equal(t, strings.Replace(content, "Chosen option: X.", "Chosen option: Y.", 1), string(data))
+}
+
+func TestPatchSection_Errors(t *testing.T) {
+	srv, _ := newPatchServer(t, map[string]string{"0001-use-go.md": "# 1. Use Go\n\n## Context\n\nC.\n"})
+
+	tests := []struct {
+		name string
+		req  *http.Request
+		want int
+	}{
+		{"unknown key", patchRequest("/api/adr/1/sections/colour", `{"body":"x"}`), http.StatusNotFound},
+		{"section missing from ADR", patchRequest("/api/adr/1/sections/decision", `{"body":"x"}`), http.StatusNotFound},
+		{"required section emptied", patchRequest("/api/adr/1/sections/context", `{"body":"  "}`), http.StatusBadRequest},
+		{"unknown ADR", patchRequest("/api/adr/9/sections/context", `{"body":"x"}`), http.StatusNotFound},
+		{"invalid number", patchRequest("/api/adr/x/sections/context", `{"body":"x"}`), http.StatusBadRequest},
+		{"invalid body", patchRequest("/api/adr/1/sections/context", `{"body":`), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(rec, tt.req)
+			assert.Equal(t, tt.want, rec.Code, rec.Body.String())
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	web.NewServer(&mockRepo{}, web.WithConfig(&adr.Config{Template: "nygard"})).Handler().
+		ServeHTTP(rec, patchRequest("/api/adr/1/sections/context", `{"body":"x"}`))
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+}
+
+func TestPatchMeta_TitleBlockVocabulary(t *testing.T) {
+	store := &mockScopeStore{scopes: []string{"Backend", "Data"}}
+	srv, dir := newPatchServer(t, map[string]string{"0001-use-go.md": patchNygardADR}, web.WithScopeStore(store))
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, patchRequest("/api/adr/1/meta/scope", `{"value":"data, backend"}`))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	data, err := os.ReadFile(filepath.Join(dir, "0001-use-go.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "\nScope: Data, Backend\n")
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, patchRequest("/api/adr/1/meta/scope", `{"value":"Payments"}`))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "unknown scope(s) [Payments]")
+}
+
+func TestPatchMeta_Frontmatter(t *testing.T) {
+	content := "---\nstatus: \"accepted\"\ndate: 2024-01-01\ndecision-makers: {list everyone involved in the decision}\n---\n\n# 2. Use Chi\n\n## Context and Problem Statement\n\nC.\n"
+	srv, dir := newPatchServer(t, map[string]string{"0002-use-chi.md": content})
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, patchRequest("/api/adr/2/meta/decision-makers", `{"value":"Alice, Bob"}`))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, patchRequest("/api/adr/2/meta/consulted", `{"value":"Carol"}`))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	data, err := os.ReadFile(filepath.Join(dir, "0002-use-chi.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "---\nstatus: \"accepted\"\ndate: 2024-01-01\ndecision-makers:\n  - Alice\n  - Bob\nconsulted: Carol\n---\n\n# 2. Use Chi\n\n## Context and Problem Statement\n\nC.\n", string(data))
+	var body struct {
+		Meta map[string][]string `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, []string{"Alice", "Bob"}, body.Meta["decision-makers"])
+}
+
+func TestPatchMeta_Errors(t *testing.T) {
+	srv, _ := newPatchServer(t, map[string]string{"0001-use-go.md": "# 1. Use Go\n\n## Context\n\nC.\n"})
+
+	for target, want := range map[string]int{
+		"/api/adr/1/meta/colour":          http.StatusNotFound, // no template defines it
+		"/api/adr/1/meta/scope":           http.StatusNotFound, // no Scope line in the ADR
+		"/api/adr/1/meta/decision-makers": http.StatusNotFound, // no frontmatter
+		"/api/adr/1/sections/scope":       http.StatusNotFound, // a meta key is not a section
+		"/api/adr/1/meta/context":         http.StatusNotFound, // and a section key is not metadata
+	} {
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, patchRequest(target, `{"value":"x","body":"x"}`))
+		assert.Equal(t, want, rec.Code, target)
+	}
+}
+
+func TestCreateADR_RejectsScopesOutsideVocabulary(t *testing.T) {
+	repo := &mockRepo{nextNum: 5}
+	cfg := &adr.Config{Version: "1", Directory: "docs/adr", Template: "nygard-scoped"}
+	srv := web.NewServer(repo, web.WithConfig(cfg), web.WithScopeStore(&mockScopeStore{scopes: []string{"Backend"}}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/adr", strings.NewReader(`{"title":"Use PostgreSQL","sections":{"scope":"backend, Payments"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "unknown scope(s) [Payments]")
+	assert.False(t, repo.saveCalled)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/adr", strings.NewReader(`{"title":"Use PostgreSQL","sections":{"scope":"backend"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, repo.savedADR.Content, "Scope: Backend")
+}
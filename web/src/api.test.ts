@@ -1,4 +1,4 @@
-import { fetchADRs, fetchADR, fetchStatuses, updateADRStatus, fetchConfig, createADR, fetchTemplateSections, updateADRContent, NotFoundError, ConflictError } from './api'
+import { fetchADRs, fetchADR, fetchStatuses, updateADRStatus, fetchConfig, fetchProjects, setProject, createADR, fetchTemplateSections, fetchTemplates, updateADRContent, NotFoundError, ConflictError } from './api'
 
 function mockFetchOk(body: unknown, status = 200) {
   vi.stubGlobal(
@@ -105,6 +105,14 @@ describe('fetchADR', () => {
     expect(result).toEqual(data)
   })
 
+  it('GETs an ADR of another root by its qualified ID', async () => {
+    mockFetchOk({ root: 'payments', number: 3, title: 'Use Z', status: 'Proposed', date: '2025-02-01', content: '# Z' })
+
+    await fetchADR('payments:3')
+
+    expect(fetch).toHaveBeenCalledWith('/api/adr/payments:3')
+  })
+
   it('throws NotFoundError on 404', async () => {
     mockFetchFail(404)
 
@@ -241,6 +249,47 @@ describe('fetchConfig', () => {
   })
 })
 
+describe('fetchProjects', () => {
+  it('lists the current project first and then the mounted ones', async () => {
+    mockFetchOk({ template: 'nygard', projects: ['billing', 'shop'] })
+
+    expect(await fetchProjects()).toEqual(['', 'billing', 'shop'])
+    expect(fetch).toHaveBeenCalledWith('/api/config')
+  })
+
+  it('leaves out the current project when none is served', async () => {
+    mockFetchOk({ projects: ['billing'] })
+
+    expect(await fetchProjects()).toEqual(['billing'])
+  })
+})
+
+describe('setProject', () => {
+  afterEach(() => {
+    setProject('')
+  })
+
+  it('addresses the mounted project\'s endpoints', async () => {
+    mockFetchOk([])
+    setProject('billing')
+
+    await fetchADRs()
+    await fetchConfig()
+
+    expect(fetch).toHaveBeenCalledWith('/api/projects/billing/adr')
+    expect(fetch).toHaveBeenCalledWith('/api/projects/billing/config')
+  })
+
+  it('keeps listing projects from the top-level config', async () => {
+    mockFetchOk({ projects: ['billing'] })
+    setProject('billing')
+
+    await fetchProjects()
+
+    expect(fetch).toHaveBeenCalledWith('/api/config')
+  })
+})
+
 describe('fetchTemplateSections', () => {
   it('GETs /api/template-sections and returns array', async () => {
     const data = [{ key: 'context', heading: 'Context', kind: 'h2', optional: false, placeholder: 'Some text' }]
@@ -259,6 +308,24 @@ describe('fetchTemplateSections', () => {
   })
 })
 
+describe('fetchTemplates', () => {
+  it('GETs /api/templates and returns array', async () => {
+    const data = [{ name: 'nygard', default: true, sections: [] }]
+    mockFetchOk(data)
+
+    const result = await fetchTemplates()
+
+    expect(fetch).toHaveBeenCalledWith('/api/templates')
+    expect(result).toEqual(data)
+  })
+
+  it('throws on non-ok response', async () => {
+    mockFetchFail(503)
+
+    await expect(fetchTemplates()).rejects.toThrow('Failed to fetch templates: 503')
+  })
+})
+
 describe('updateADRContent', () => {
   it('PUTs content to /api/adr/{number} and returns ADRDetail', async () => {
     const data = { number: 1, title: 'Use Go', status: 'Proposed', date: '2026-03-02', content: '# 1. Use Go' }
@@ -274,6 +341,18 @@ describe('updateADRContent', () => {
     expect(result).toEqual(data)
   })
 
+  it('sends rename: true when requested', async () => {
+    mockFetchOk({ number: 1, title: 'Use Go', status: 'Proposed', date: '', content: '# 1. Use Go' })
+
+    await updateADRContent(1, '# 1. Use Go', { rename: true })
+
+    expect(fetch).toHaveBeenCalledWith('/api/adr/1', {
+      method: 'PUT',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify({ content: '# 1. Use Go', rename: true }),
+    })
+  })
+
   it('throws NotFoundError on 404', async () => {
     mockFetchFail(404)
 
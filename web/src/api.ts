@@ -1,4 +1,17 @@
-import type { ADRSummary, ADRDetail, CreateADRPayload, TemplateSectionDef, MetaField } from './types'
+import type { ADRID, ADRSummary, ADRDetail, CreateADRPayload, TemplateSectionDef, TemplateInfo, MetaField } from './types'
+
+// The mounted project (adr-web --project) the API calls address, set by the
+// router: its endpoints are under /api/projects/{name}/, and those of the
+// current directory's project, addressed by '', under /api/.
+let project = ''
+
+export function setProject(name: string) {
+  project = name
+}
+
+function apiURL(path: string): string {
+  return project ? `/api/projects/${encodeURIComponent(project)}${path}` : `/api${path}`
+}
 
 async function apiFetch(url: string, init?: RequestInit): Promise<Response> {
   try {
@@ -15,7 +28,7 @@ async function apiFetch(url: string, init?: RequestInit): Promise<Response> {
 }
 
 export async function fetchADRs(query?: string, signal?: AbortSignal): Promise<ADRSummary[]> {
-  let url = '/api/adr'
+  let url = apiURL('/adr')
   if (query) {
     url += `?q=${encodeURIComponent(query)}`
   }
@@ -27,10 +40,10 @@ export async function fetchADRs(query?: string, signal?: AbortSignal): Promise<A
   return res.json()
 }
 
-export async function fetchADR(number: number): Promise<ADRDetail> {
-  const res = await apiFetch(`/api/adr/${number}`)
+export async function fetchADR(id: ADRID): Promise<ADRDetail> {
+  const res = await apiFetch(apiURL(`/adr/${id}`))
   if (res.status === 404) {
-    throw new NotFoundError(`ADR #${number} not found`)
+    throw new NotFoundError(`ADR #${id} not found`)
   }
   if (!res.ok) {
     throw new Error(`Failed to fetch ADR: ${res.status}`)
@@ -39,7 +52,7 @@ export async function fetchADR(number: number): Promise<ADRDetail> {
 }
 
 export async function fetchStatuses(): Promise<string[]> {
-  const res = await apiFetch('/api/adr/statuses')
+  const res = await apiFetch(apiURL('/adr/statuses'))
   if (!res.ok) {
     throw new Error(`Failed to fetch statuses: ${res.status}`)
   }
@@ -52,7 +65,7 @@ interface UpdateStatusPayload {
 }
 
 export async function updateADRStatus(
-  number: number,
+  id: ADRID,
   status: string,
   options?: { supersededBy?: number },
 ): Promise<ADRDetail> {
@@ -60,13 +73,13 @@ export async function updateADRStatus(
   if (options?.supersededBy != null) {
     payload.supersededBy = options.supersededBy
   }
-  const res = await apiFetch(`/api/adr/${number}/status`, {
+  const res = await apiFetch(apiURL(`/adr/${id}/status`), {
     method: 'PATCH',
     headers: { 'Content-Type': 'application/json' },
     body: JSON.stringify(payload),
   })
   if (res.status === 404) {
-    throw new NotFoundError(`ADR #${number} not found`)
+    throw new NotFoundError(`ADR #${id} not found`)
   }
   if (!res.ok) {
     throw new Error(`Failed to update status: ${res.status}`)
@@ -74,14 +87,14 @@ export async function updateADRStatus(
   return res.json()
 }
 
-export async function addRelation(number: number, relatedTo: number): Promise<ADRDetail> {
-  const res = await apiFetch(`/api/adr/${number}/relations`, {
+export async function addRelation(id: ADRID, relatedTo: number): Promise<ADRDetail> {
+  const res = await apiFetch(apiURL(`/adr/${id}/relations`), {
     method: 'POST',
     headers: { 'Content-Type': 'application/json' },
     body: JSON.stringify({ relatedTo }),
   })
   if (res.status === 404) {
-    throw new NotFoundError(`ADR #${number} not found`)
+    throw new NotFoundError(`ADR #${id} not found`)
   }
   if (!res.ok) {
     throw new Error(`Failed to add relation: ${res.status}`)
@@ -90,29 +103,52 @@ export async function addRelation(number: number, relatedTo: number): Promise<AD
 }
 
 export async function fetchTemplateSections(): Promise<TemplateSectionDef[]> {
-  const res = await apiFetch('/api/template-sections')
+  const res = await apiFetch(apiURL('/template-sections'))
   if (!res.ok) {
     throw new Error(`Failed to fetch template sections: ${res.status}`)
   }
   return res.json()
 }
 
+export async function fetchTemplates(): Promise<TemplateInfo[]> {
+  const res = await apiFetch(apiURL('/templates'))
+  if (!res.ok) {
+    throw new Error(`Failed to fetch templates: ${res.status}`)
+  }
+  return res.json()
+}
+
 export async function fetchMetaFields(): Promise<MetaField[]> {
-  const res = await apiFetch('/api/meta-fields')
+  const res = await apiFetch(apiURL('/meta-fields'))
   if (!res.ok) {
     throw new Error(`Failed to fetch meta fields: ${res.status}`)
   }
   return res.json()
 }
 
-export async function updateADRContent(number: number, content: string): Promise<ADRDetail> {
-  const res = await apiFetch(`/api/adr/${number}`, {
+interface UpdateContentPayload {
+  content: string
+  rename?: boolean
+}
+
+// With `rename`, the server also renames the file to match the (possibly
+// changed) title and rewrites inbound links in other ADRs.
+export async function updateADRContent(
+  id: ADRID,
+  content: string,
+  options?: { rename?: boolean },
+): Promise<ADRDetail> {
+  const payload: UpdateContentPayload = { content }
+  if (options?.rename) {
+    payload.rename = true
+  }
+  const res = await apiFetch(apiURL(`/adr/${id}`), {
     method: 'PUT',
     headers: { 'Content-Type': 'application/json' },
-    body: JSON.stringify({ content }),
+    body: JSON.stringify(payload),
   })
   if (res.status === 404) {
-    throw new NotFoundError(`ADR #${number} not found`)
+    throw new NotFoundError(`ADR #${id} not found`)
   }
   if (!res.ok) {
     throw new Error(`Failed to update content: ${res.status}`)
@@ -121,7 +157,7 @@ export async function updateADRContent(number: number, content: string): Promise
 }
 
 export async function fetchScopes(): Promise<string[]> {
-  const res = await apiFetch('/api/scopes')
+  const res = await apiFetch(apiURL('/scopes'))
   if (!res.ok) {
     throw new Error(`Failed to fetch scopes: ${res.status}`)
   }
@@ -129,7 +165,7 @@ export async function fetchScopes(): Promise<string[]> {
 }
 
 export async function addScope(value: string): Promise<string[]> {
-  const res = await apiFetch('/api/scopes', {
+  const res = await apiFetch(apiURL('/scopes'), {
     method: 'POST',
     headers: { 'Content-Type': 'application/json' },
     body: JSON.stringify({ value }),
@@ -143,15 +179,27 @@ export async function addScope(value: string): Promise<string[]> {
 }
 
 export async function fetchConfig(): Promise<{ template: string }> {
-  const res = await apiFetch('/api/config')
+  const res = await apiFetch(apiURL('/config'))
   if (!res.ok) {
     throw new Error(`Failed to fetch config: ${res.status}`)
   }
   return res.json()
 }
 
+// fetchProjects returns the names of the projects served, led by '' for the
+// current directory's project when there is one.
+export async function fetchProjects(): Promise<string[]> {
+  const res = await apiFetch('/api/config')
+  if (!res.ok) {
+    throw new Error(`Failed to fetch config: ${res.status}`)
+  }
+  const config: { template?: string; projects?: string[] } = await res.json()
+  const names = config.projects ?? []
+  return config.template !== undefined ? ['', ...names] : names
+}
+
 export async function createADR(payload: CreateADRPayload): Promise<ADRDetail> {
-  const res = await apiFetch('/api/adr', {
+  const res = await apiFetch(apiURL('/adr'), {
     method: 'POST',
     headers: { 'Content-Type': 'application/json' },
     body: JSON.stringify(payload),
@@ -10,7 +10,8 @@ export function useCreateADR() {
   const submitError = ref('')
   const sectionErrors: Ref<Record<string, string>> = ref({})
 
-  async function submit(sectionDefs?: TemplateSectionDef[]): Promise<ADRDetail | null> {
+  // `template` selects a non-default template; omit it for the project default.
+  async function submit(sectionDefs?: TemplateSectionDef[], template?: string): Promise<ADRDetail | null> {
     submitError.value = ''
     sectionErrors.value = {}
 
@@ -48,7 +49,11 @@ export function useCreateADR() {
 
     submitting.value = true
     try {
-      const payload = { title: trimmed, ...(Object.keys(sectionPayload).length > 0 ? { sections: sectionPayload } : {}) }
+      const payload = {
+        title: trimmed,
+        ...(template ? { template } : {}),
+        ...(Object.keys(sectionPayload).length > 0 ? { sections: sectionPayload } : {}),
+      }
       const result = await createADR(payload)
       return result
     } catch (e) {
@@ -1,5 +1,5 @@
 import { ref } from 'vue'
-import type { ADRDetail } from '../types'
+import type { ADRDetail, ADRID } from '../types'
 import { updateADRContent } from '../api'
 
 export type EditState = 'idle' | 'confirming' | 'editing' | 'saving'
@@ -26,11 +26,11 @@ export function useEditContent() {
     saveError.value = ''
   }
 
-  async function saveEdit(number: number): Promise<ADRDetail | null> {
+  async function saveEdit(id: ADRID): Promise<ADRDetail | null> {
     editState.value = 'saving'
     saveError.value = ''
     try {
-      const result = await updateADRContent(number, editedContent.value)
+      const result = await updateADRContent(id, editedContent.value)
       editState.value = 'idle'
       editedContent.value = ''
       return result
@@ -0,0 +1,43 @@
+import { mount } from '@vue/test-utils'
+import { createRouter, createMemoryHistory } from 'vue-router'
+import { defineComponent } from 'vue'
+import { useProject } from './useProject'
+
+async function mountAt(path: string) {
+  const router = createRouter({
+    history: createMemoryHistory(),
+    routes: [
+      { path: '/', component: { template: '<div />' } },
+      { path: '/projects/:project', component: { template: '<div />' } },
+    ],
+  })
+  router.push(path)
+  await router.isReady()
+
+  let project!: ReturnType<typeof useProject>
+  mount(
+    defineComponent({
+      setup() {
+        project = useProject()
+        return () => null
+      },
+    }),
+    { global: { plugins: [router] } },
+  )
+  return { project, router }
+}
+
+describe('useProject', () => {
+  it('is empty outside a mounted project', async () => {
+    const { project } = await mountAt('/')
+    expect(project.value).toBe('')
+  })
+
+  it('follows the route into a mounted project', async () => {
+    const { project, router } = await mountAt('/projects/billing')
+    expect(project.value).toBe('billing')
+
+    await router.push('/')
+    expect(project.value).toBe('')
+  })
+})
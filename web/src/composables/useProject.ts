@@ -0,0 +1,9 @@
+import { computed } from 'vue'
+import { useRoute } from 'vue-router'
+
+// useProject returns the mounted project the current route is in, the
+// :project of /projects/:project/…, or '' for the current directory's.
+export function useProject() {
+  const route = useRoute()
+  return computed(() => (typeof route.params.project === 'string' ? route.params.project : ''))
+}
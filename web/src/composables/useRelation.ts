@@ -1,8 +1,8 @@
 import { ref, onUnmounted } from 'vue'
-import type { ADRDetail } from '../types'
+import type { ADRDetail, ADRID } from '../types'
 import { addRelation } from '../api'
 
-export function useRelation(adrNumber: number) {
+export function useRelation(id: ADRID) {
   const adding = ref(false)
   const feedback = ref('')
   const feedbackType = ref<'success' | 'error' | ''>('')
@@ -31,7 +31,7 @@ export function useRelation(adrNumber: number) {
 
     adding.value = true
     try {
-      const result = await addRelation(adrNumber, targetNumber)
+      const result = await addRelation(id, targetNumber)
       setFeedback('Relation added successfully', 'success')
       return result
     } catch (e) {
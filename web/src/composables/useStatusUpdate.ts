@@ -1,8 +1,8 @@
 import { ref, onUnmounted } from 'vue'
-import type { ADRDetail } from '../types'
+import type { ADRDetail, ADRID } from '../types'
 import { updateADRStatus } from '../api'
 
-export function useStatusUpdate(adrNumber: number) {
+export function useStatusUpdate(id: ADRID) {
   const updating = ref(false)
   const feedback = ref('')
   const feedbackType = ref<'success' | 'error'>('success')
@@ -31,7 +31,7 @@ export function useStatusUpdate(adrNumber: number) {
     }
 
     try {
-      const updated = await updateADRStatus(adrNumber, newStatus, options)
+      const updated = await updateADRStatus(id, newStatus, options)
       previousStatus = updated.status
       feedbackType.value = 'success'
       feedback.value = `Status updated to ${updated.status}`
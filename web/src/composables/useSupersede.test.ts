@@ -38,6 +38,19 @@ describe('useSupersede', () => {
     expect(result.loadingADRs.value).toBe(false)
   })
 
+  it('startSupersede offers only ADRs of the same root', async () => {
+    mockedFetchADRs.mockResolvedValue([
+      { number: 3, title: 'Use MySQL', status: 'Accepted', date: '2025-01-01' },
+      { root: 'payments', number: 3, title: 'Use Adyen', status: 'Accepted', date: '2025-01-01' },
+      { root: 'payments', number: 5, title: 'Use Stripe', status: 'Accepted', date: '2025-01-15' },
+    ])
+
+    const [result] = withSetup(() => useSupersede(5, 'payments'))
+    await result.startSupersede()
+
+    expect(result.availableADRs.value.map(a => a.title)).toEqual(['Use Adyen'])
+  })
+
   it('startSupersede sets loadingADRs during fetch', async () => {
     mockedFetchADRs.mockReturnValue(new Promise(() => {}))
 
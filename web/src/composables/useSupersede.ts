@@ -2,7 +2,8 @@ import { ref, onUnmounted } from 'vue'
 import type { ADRSummary } from '../types'
 import { fetchADRs } from '../api'
 
-export function useSupersede(adrNumber: number) {
+// The candidates are the other ADRs of root, which supersede one another.
+export function useSupersede(adrNumber: number, root = '') {
   const pendingSuperseded = ref(false)
   const supersededBy = ref<number | null>(null)
   const availableADRs = ref<ADRSummary[]>([])
@@ -21,7 +22,7 @@ export function useSupersede(adrNumber: number) {
     try {
       const allADRs = await fetchADRs(undefined, currentController.signal)
       if (currentController !== supersedeFetchController) return
-      availableADRs.value = allADRs.filter(a => a.number !== adrNumber)
+      availableADRs.value = allADRs.filter(a => (a.root ?? '') === root && a.number !== adrNumber)
     } catch (e) {
       if (e instanceof DOMException && e.name === 'AbortError') return
       if (currentController !== supersedeFetchController) return
@@ -21,4 +21,17 @@ describe('router', () => {
     const result = propsFn({ params: { number: '42' } } as never)
     expect(result).toEqual({ number: 42 })
   })
+
+  it('detail route props function splits a root-qualified number', () => {
+    const route = routes.find(r => r.name === 'detail')!
+    const propsFn = route.props.default as (route: { params: { number: string } }) => { number: number; root?: string }
+    const result = propsFn({ params: { number: 'payments:12' } } as never)
+    expect(result).toEqual({ number: 12, root: 'payments' })
+  })
+
+  it('has the project routes', () => {
+    expect(routes.find(r => r.path === '/projects/:project')?.name).toBe('project-list')
+    expect(routes.find(r => r.path === '/projects/:project/adr/new')?.name).toBe('project-create')
+    expect(routes.find(r => r.path === '/projects/:project/adr/:number')?.name).toBe('project-detail')
+  })
 })
@@ -2,6 +2,8 @@ import { createRouter, createWebHistory } from 'vue-router'
 import ADRListView from '../views/ADRListView.vue'
 import ADRCreateView from '../views/ADRCreateView.vue'
 import ADRDetailView from '../views/ADRDetailView.vue'
+import { setProject } from '../api'
+import { parseADRID } from '../utils/adrLinks'
 
 const router = createRouter({
   history: createWebHistory(),
@@ -20,9 +22,32 @@ const router = createRouter({
       path: '/adr/:number',
       name: 'detail',
       component: ADRDetailView,
-      props: (route) => ({ number: Number(route.params.number) }),
+      // The number may be root-qualified, as in /adr/payments:12.
+      props: (route) => parseADRID(String(route.params.number)),
+    },
+    // A mounted project's ADRs (adr-web --project), with the routes above.
+    {
+      path: '/projects/:project',
+      name: 'project-list',
+      component: ADRListView,
+    },
+    {
+      path: '/projects/:project/adr/new',
+      name: 'project-create',
+      component: ADRCreateView,
+    },
+    {
+      path: '/projects/:project/adr/:number',
+      name: 'project-detail',
+      component: ADRDetailView,
+      props: (route) => parseADRID(String(route.params.number)),
     },
   ],
 })
 
+// Point the API at the project of the route being entered.
+router.beforeEach((to) => {
+  setProject(typeof to.params.project === 'string' ? to.params.project : '')
+})
+
 export default router
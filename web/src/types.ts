@@ -1,4 +1,10 @@
+// An ADR's number, or its root-qualified ID ("payments:12") when it's in
+// another of the project's roots.
+export type ADRID = number | string
+
 export interface ADRSummary {
+  // Root the ADR is in; absent for the project's default root.
+  root?: string
   number: number
   title: string
   status: string
@@ -6,12 +12,25 @@ export interface ADRSummary {
   meta?: Record<string, string[]>
 }
 
+export interface ADRSection {
+  level: number
+  heading: string
+  // Key of the template section the heading matches; absent otherwise.
+  key?: string
+  body: string
+}
+
 export interface ADRDetail extends ADRSummary {
   content: string
+  // Directory of the ADR's file below the ADR directory; absent at the top.
+  dir?: string
+  sections?: ADRSection[]
 }
 
 export interface CreateADRPayload {
   title: string
+  // Template name; omitted to use the project default.
+  template?: string
   sections?: Record<string, string>
 }
 
@@ -24,6 +43,13 @@ export interface TemplateSectionDef {
   vocabulary?: boolean
 }
 
+// A template offered by the create form (GET /api/templates).
+export interface TemplateInfo {
+  name: string
+  default: boolean
+  sections: TemplateSectionDef[]
+}
+
 export type SortField = 'number' | 'title' | 'status' | 'date'
 export type SortDirection = 'asc' | 'desc'
 
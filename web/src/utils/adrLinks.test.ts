@@ -0,0 +1,59 @@
+import { adrID, parseADRID, projectPath, resolveADRHref } from './adrLinks'
+
+describe('adrID', () => {
+  it('qualifies the number with the root', () => {
+    expect(adrID({ number: 12 })).toBe(12)
+    expect(adrID({ number: 12, root: 'payments' })).toBe('payments:12')
+  })
+})
+
+describe('parseADRID', () => {
+  it('splits a root-qualified ID', () => {
+    expect(parseADRID('12')).toEqual({ number: 12 })
+    expect(parseADRID('payments:12')).toEqual({ number: 12, root: 'payments' })
+  })
+})
+
+describe('projectPath', () => {
+  it('prefixes paths of a mounted project', () => {
+    expect(projectPath('', '/adr/12')).toBe('/adr/12')
+    expect(projectPath('billing', '/adr/12')).toBe('/projects/billing/adr/12')
+    expect(projectPath('billing', '/')).toBe('/projects/billing')
+  })
+})
+
+describe('resolveADRHref', () => {
+  it('maps ADR files to their route', () => {
+    expect(resolveADRHref('0002-use-postgresql.md')).toBe('/adr/2')
+    expect(resolveADRHref('./0012-x.md#decision')).toBe('/adr/12#decision')
+  })
+
+  it('maps ADR files of a root to its root-qualified route', () => {
+    expect(resolveADRHref('0002-use-stripe.md', '', 'payments')).toBe('/adr/payments:2')
+    expect(resolveADRHref('assets/flow.png', '', 'payments')).toBe('/api/roots/payments/assets/assets/flow.png')
+  })
+
+  it('maps links of a mounted project\'s ADR into the project', () => {
+    expect(resolveADRHref('0002-use-stripe.md', '', '', 'billing')).toBe('/projects/billing/adr/2')
+    expect(resolveADRHref('assets/flow.png', '', '', 'billing')).toBe('/api/projects/billing/assets/assets/flow.png')
+  })
+
+  it('maps other relative files to the asset endpoint', () => {
+    expect(resolveADRHref('assets/0012-flow.png')).toBe('/api/assets/assets/0012-flow.png')
+    expect(resolveADRHref('./diagrams/flow.drawio')).toBe('/api/assets/diagrams/flow.drawio')
+  })
+
+  it('resolves asset links from an ADR in a subdirectory', () => {
+    expect(resolveADRHref('../assets/0003-flow.png', 'platform')).toBe('/api/assets/assets/0003-flow.png')
+    expect(resolveADRHref('diagrams/flow.drawio', 'platform')).toBe('/api/assets/platform/diagrams/flow.drawio')
+    expect(resolveADRHref('../../README.txt', 'platform')).toBe('../../README.txt')
+  })
+
+  it('leaves absolute, anchor and other markdown links alone', () => {
+    expect(resolveADRHref('https://example.com/a.png')).toBe('https://example.com/a.png')
+    expect(resolveADRHref('mailto:team@example.com')).toBe('mailto:team@example.com')
+    expect(resolveADRHref('/docs/guide')).toBe('/docs/guide')
+    expect(resolveADRHref('#context')).toBe('#context')
+    expect(resolveADRHref('README.md')).toBe('README.md')
+  })
+})
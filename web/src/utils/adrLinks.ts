@@ -0,0 +1,58 @@
+import type { ADRID } from '../types'
+
+const ADR_FILE = /^(?:\.\/)?(\d{4,})-[^/]*\.md$/
+const SCHEME = /^[a-zA-Z][a-zA-Z0-9+.-]*:/
+
+// adrID returns the ID an ADR is addressed by in routes and API paths: its
+// number, qualified with its root when it has one.
+export function adrID(adr: { root?: string; number: number }): ADRID {
+  return adr.root ? `${adr.root}:${adr.number}` : adr.number
+}
+
+// parseADRID splits a route's ID ("12" or "payments:12") into root and number.
+export function parseADRID(id: string): { number: number; root?: string } {
+  const colon = id.lastIndexOf(':')
+  if (colon < 0) return { number: Number(id) }
+  return { number: Number(id.slice(colon + 1)), root: id.slice(0, colon) }
+}
+
+// projectPath returns the SPA path of path ("/", "/adr/12") in project: below
+// /projects/:project for a mounted project, and path itself for ''.
+export function projectPath(project: string, path: string): string {
+  if (!project) return path
+  const base = `/projects/${encodeURIComponent(project)}`
+  return path === '/' ? base : base + path
+}
+
+// joinPath resolves path against dir, both relative to the ADR directory, or
+// returns null when the result leaves that directory.
+function joinPath(dir: string, path: string): string | null {
+  const parts: string[] = []
+  for (const part of `${dir}/${path}`.split('/')) {
+    if (part === '' || part === '.') continue
+    if (part !== '..') parts.push(part)
+    else if (parts.pop() === undefined) return null
+  }
+  return parts.join('/')
+}
+
+// resolveADRHref maps a link or image destination written relative to the ADR
+// file onto the SPA: other ADRs go to their /adr/:number route, and anything
+// else relative (images, diagrams) to the server's asset endpoint. dir is the
+// ADR file's directory below the ADR directory ('' at the top level), and root
+// and project the root and mounted project it's in, whose ADRs and assets its
+// links point at.
+export function resolveADRHref(href: string, dir = '', root = '', project = ''): string {
+  if (!href || href.startsWith('#') || href.startsWith('/') || SCHEME.test(href)) return href
+  const hashIndex = href.indexOf('#')
+  const path = hashIndex >= 0 ? href.slice(0, hashIndex) : href
+  const hash = hashIndex >= 0 ? href.slice(hashIndex) : ''
+  const match = ADR_FILE.exec(path)
+  if (match) return projectPath(project, `/adr/${adrID({ root, number: Number(match[1]) })}${hash}`)
+  if (/\.md$/i.test(path)) return href
+  const asset = joinPath(dir, path)
+  if (asset === null) return href
+  let api = project ? `/api/projects/${encodeURIComponent(project)}` : '/api'
+  if (root) api += `/roots/${encodeURIComponent(root)}`
+  return `${api}/assets/${asset}${hash}`
+}
@@ -1,13 +1,14 @@
 import { mount, flushPromises } from '@vue/test-utils'
 import { createRouter, createMemoryHistory } from 'vue-router'
 import ADRCreateView from './ADRCreateView.vue'
-import { fetchConfig, createADR, fetchTemplateSections, fetchScopes, addScope } from '../api'
+import { fetchConfig, createADR, fetchTemplateSections, fetchTemplates, fetchScopes, addScope } from '../api'
 import type { TemplateSectionDef } from '../types'
 
 vi.mock('../api', () => ({
   fetchConfig: vi.fn(),
   createADR: vi.fn(),
   fetchTemplateSections: vi.fn(),
+  fetchTemplates: vi.fn(),
   fetchScopes: vi.fn(),
   addScope: vi.fn(),
 }))
@@ -15,12 +16,14 @@ vi.mock('../api', () => ({
 const mockedFetchConfig = fetchConfig as ReturnType<typeof vi.fn>
 const mockedCreateADR = createADR as ReturnType<typeof vi.fn>
 const mockedFetchTemplateSections = fetchTemplateSections as ReturnType<typeof vi.fn>
+const mockedFetchTemplates = fetchTemplates as ReturnType<typeof vi.fn>
 const mockedFetchScopes = fetchScopes as ReturnType<typeof vi.fn>
 const mockedAddScope = addScope as ReturnType<typeof vi.fn>
 
 beforeEach(() => {
   // Sensible defaults so non-scoped tests don't need to wire the vocabulary.
   mockedFetchScopes.mockResolvedValue([])
+  mockedFetchTemplates.mockResolvedValue([])
 })
 
 const nygardSections: TemplateSectionDef[] = [
@@ -539,4 +542,98 @@ describe('ADRCreateView', () => {
 
       expect(mockedCreateADR).not.toHaveBeenCalled()
     })
+  
+  describe('template picker', () => {
+    const madrSections: TemplateSectionDef[] = [
+      { key: 'considered-options', heading: 'Considered Options', kind: 'h2', optional: false, placeholder: '* Option 1' },
+    ]
+
+    beforeEach(() => {
+      mockedFetchConfig.mockResolvedValue({ template: 'nygard' })
+      mockedFetchTemplateSections.mockResolvedValue(nygardSections)
+      mockedFetchTemplates.mockResolvedValue([
+        { name: 'nygard', default: true, sections: nygardSections },
+        { name: 'madr-minimal', default: false, sections: madrSections },
+      ])
+    })
+
+    it('hides the picker when only one template is offered', async () => {
+      mockedFetchTemplates.mockResolvedValue([{ name: 'nygard', default: true, sections: nygardSections }])
+      const { wrapper } = await mountView()
+      await flushPromises()
+
+      expect(wrapper.find('#adr-template').exists()).toBe(false)
+      expect(wrapper.text()).toContain('nygard')
+    })
+
+    it('switches sections and sends the chosen template', async () => {
+      mockedCreateADR.mockResolvedValue({ number: 2, title: 'Use Kafka', status: 'Proposed', date: '2026-03-02', content: '' })
+      const { wrapper } = await mountView()
+      await flushPromises()
+
+      await wrapper.find('#adr-template').setValue('madr-minimal')
+      expect(wrapper.find('#section-context').exists()).toBe(false)
+
+      await wrapper.find('#adr-title').setValue('Use Kafka')
+      await wrapper.find('#section-considered-options').setValue('* Kafka')
+      await wrapper.find('form').trigger('submit')
+      await flushPromises()
+
+      expect(mockedCreateADR).toHaveBeenCalledWith({
+        title: 'Use Kafka',
+        template: 'madr-minimal',
+        sections: { 'considered-options': '* Kafka' },
+      })
+    })
+
+    it('renders frontmatter fields as single-line inputs', async () => {
+      mockedFetchTemplates.mockResolvedValue([
+        { name: 'nygard', default: true, sections: nygardSections },
+        {
+          name: 'madr-full',
+          default: false,
+          sections: [
+            { key: 'decision-makers', heading: 'Decision Makers', kind: 'frontmatter', optional: true, placeholder: 'list everyone involved' },
+            ...madrSections,
+          ],
+        },
+      ])
+      mockedCreateADR.mockResolvedValue({ number: 2, title: 'Use Kafka', status: 'Proposed', date: '2026-03-02', content: '' })
+      const { wrapper } = await mountView()
+      await flushPromises()
+
+      await wrapper.find('#adr-template').setValue('madr-full')
+      const field = wrapper.find('#section-decision-makers')
+      expect(field.element.tagName).toBe('INPUT')
+
+      await wrapper.find('#adr-title').setValue('Use Kafka')
+      await field.setValue('Alice, Bob')
+      await wrapper.find('#section-considered-options').setValue('* Kafka')
+      await wrapper.find('form').trigger('submit')
+      await flushPromises()
+
+      expect(mockedCreateADR).toHaveBeenCalledWith({
+        title: 'Use Kafka',
+        template: 'madr-full',
+        sections: { 'decision-makers': 'Alice, Bob', 'considered-options': '* Kafka' },
+      })
+    })
+
+    it('omits the template when the default is kept', async () => {
+      mockedCreateADR.mockResolvedValue({ number: 2, title: 'Use Go', status: 'Proposed', date: '2026-03-02', content: '' })
+      const { wrapper } = await mountView()
+      await flushPromises()
+
+      await wrapper.find('#adr-title').setValue('Use Go')
+      await wrapper.find('#section-context').setValue('Why')
+      await wrapper.find('#section-decision').setValue('Go')
+      await wrapper.find('form').trigger('submit')
+      await flushPromises()
+
+      expect(mockedCreateADR).toHaveBeenCalledWith({
+        title: 'Use Go',
+        sections: { context: 'Why', decision: 'Go' },
+      })
+    })
   })
+})
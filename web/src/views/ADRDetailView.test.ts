@@ -8,6 +8,7 @@ import { nextTick } from 'vue'
 import ADRDetailView from './ADRDetailView.vue'
 import type { ADRDetail, ADRSummary } from '../types'
 import { NotFoundError } from '../api'
+import { parseADRID } from '../utils/adrLinks'
 
 vi.mock('../api', async (importOriginal) => {
   const actual = await importOriginal<typeof import('../api')>()
@@ -50,7 +51,12 @@ function makeRouter() {
         path: '/adr/:number',
         name: 'detail',
         component: ADRDetailView,
-        props: (route) => ({ number: Number(route.params.number) }),
+        props: (route) => parseADRID(String(route.params.number)),
+      },
+      {
+        path: '/projects/:project/adr/:number',
+        component: ADRDetailView,
+        props: (route) => parseADRID(String(route.params.number)),
       },
     ],
   })
@@ -158,6 +164,34 @@ describe('ADRDetailView', () => {
       expect(section.text()).toContain('Context')
     })
 
+    it('fetches and titles an ADR of another root by its qualified ID', async () => {
+      mockedFetchADR.mockResolvedValue({ ...sampleDetail, root: 'payments' })
+      const router = makeRouter()
+      router.push('/adr/payments:5')
+      await router.isReady()
+      const wrapper = mount(ADRDetailView, {
+        props: { number: 5, root: 'payments' },
+        global: { plugins: [router] },
+      })
+      await flushPromises()
+
+      expect(mockedFetchADR).toHaveBeenCalledWith('payments:5')
+      expect(wrapper.text()).toContain('ADR #payments:5: Use PostgreSQL')
+    })
+
+    it('links back to the list of a mounted project', async () => {
+      const router = makeRouter()
+      router.push('/projects/billing/adr/5')
+      await router.isReady()
+      const wrapper = mount(ADRDetailView, {
+        props: { number: 5 },
+        global: { plugins: [router] },
+      })
+      await flushPromises()
+
+      expect(wrapper.find('nav a').attributes('href')).toBe('/projects/billing')
+    })
+
     it('has back link to list', async () => {
       const { wrapper } = await mountView()
       await flushPromises()
@@ -1,17 +1,19 @@
 import { mount, flushPromises } from '@vue/test-utils'
 import { createRouter, createMemoryHistory } from 'vue-router'
 import ADRListView from './ADRListView.vue'
-import { fetchADRs, fetchStatuses, fetchMetaFields } from '../api'
+import { fetchADRs, fetchStatuses, fetchMetaFields, fetchProjects } from '../api'
 
 vi.mock('../api', () => ({
   fetchADRs: vi.fn(),
   fetchStatuses: vi.fn(),
   fetchMetaFields: vi.fn(),
+  fetchProjects: vi.fn(),
 }))
 
 const mockedFetchADRs = fetchADRs as ReturnType<typeof vi.fn>
 const mockedFetchStatuses = fetchStatuses as ReturnType<typeof vi.fn>
 const mockedFetchMetaFields = fetchMetaFields as ReturnType<typeof vi.fn>
+const mockedFetchProjects = fetchProjects as ReturnType<typeof vi.fn>
 
 function makeRouter() {
   return createRouter({
@@ -20,6 +22,9 @@ function makeRouter() {
       { path: '/', component: ADRListView },
       { path: '/adr/new', name: 'create', component: { template: '<div />' } },
       { path: '/adr/:number', name: 'detail', component: { template: '<div />' } },
+      { path: '/projects/:project', component: ADRListView },
+      { path: '/projects/:project/adr/new', component: { template: '<div />' } },
+      { path: '/projects/:project/adr/:number', component: { template: '<div />' } },
     ],
   })
 }
@@ -41,6 +46,8 @@ describe('ADRListView', () => {
     mockedFetchStatuses.mockResolvedValue(['Accepted', 'Proposed', 'Rejected', 'Deprecated', 'Superseded'])
     // Default: no metadata facets, so existing tests render only the status/sort groups.
     mockedFetchMetaFields.mockResolvedValue([])
+    // Default: a single project, so no project picker.
+    mockedFetchProjects.mockResolvedValue([''])
   })
 
   describe('loading state', () => {
@@ -194,6 +201,80 @@ describe('ADRListView', () => {
     })
   })
 
+  describe('roots', () => {
+    const adrs = [
+      { number: 1, title: 'Use PostgreSQL', status: 'Accepted', date: '2025-01-15' },
+      { root: 'payments', number: 1, title: 'Use Stripe', status: 'Proposed', date: '2025-02-01' },
+    ]
+
+    beforeEach(() => {
+      mockedFetchADRs.mockResolvedValue(adrs)
+    })
+
+    it('links ADRs of other roots to their root-qualified route', async () => {
+      const { wrapper } = await mountView()
+      await flushPromises()
+
+      const links = wrapper.findAll('ul a')
+      expect(links.map(l => l.attributes('href'))).toEqual(['/adr/1', '/adr/payments:1'])
+      expect(links[1].attributes('aria-label')).toBe('ADR #payments:1: Use Stripe')
+    })
+
+    it('shows a root column', async () => {
+      const { wrapper } = await mountView()
+      await flushPromises()
+
+      expect(wrapper.findAll('ul li')[1].text()).toContain('payments')
+    })
+
+    it('has no root column without roots', async () => {
+      mockedFetchADRs.mockResolvedValue([adrs[0]])
+      const { wrapper } = await mountView()
+      await flushPromises()
+
+      expect(wrapper.find('ul li').findAll('a > span')).toHaveLength(3)
+    })
+  })
+
+  describe('projects', () => {
+    beforeEach(() => {
+      mockedFetchADRs.mockResolvedValue([
+        { number: 1, title: 'Use PostgreSQL', status: 'Accepted', date: '2025-01-15' },
+      ])
+    })
+
+    it('has no project picker with a single project', async () => {
+      const { wrapper } = await mountView()
+      await flushPromises()
+
+      expect(wrapper.find('select[aria-label="Project"]').exists()).toBe(false)
+    })
+
+    it('switches projects with the picker', async () => {
+      mockedFetchProjects.mockResolvedValue(['', 'billing'])
+      const { wrapper, router } = await mountView()
+      await flushPromises()
+
+      const picker = wrapper.find('select[aria-label="Project"]')
+      expect(picker.findAll('option').map(o => o.text())).toEqual(['Current project', 'billing'])
+      await picker.setValue('billing')
+      await flushPromises()
+
+      expect(router.currentRoute.value.path).toBe('/projects/billing')
+    })
+
+    it('links into the mounted project it lists', async () => {
+      mockedFetchProjects.mockResolvedValue(['', 'billing'])
+      const { wrapper } = await mountView('/projects/billing')
+      await flushPromises()
+
+      const hrefs = wrapper.findAll('a').map(l => l.attributes('href'))
+      expect(hrefs).toContain('/projects/billing/adr/1')
+      expect(hrefs).toContain('/projects/billing/adr/new')
+      expect((wrapper.find('select[aria-label="Project"]').element as HTMLSelectElement).value).toBe('billing')
+    })
+  })
+
   describe('search', () => {
     beforeEach(() => {
       vi.useFakeTimers()